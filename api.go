@@ -2,21 +2,143 @@ package okta
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Client to access okta
+// Version is the version of this library, it is sent in the User-Agent
+// header as go-okta/Version
+const Version = "1.0.0"
+
+// Client to access okta, it is safe for concurrent use by multiple
+// goroutines once configured. Its fields must not be changed while it is in
+// use, except for the session which can be replaced with SetSessionCookie.
+// Clients should be reused rather than created per request so that they
+// share connections.
 type Client struct {
 	client        *http.Client
 	org           string
 	Url           string
 	ApiToken      string
 	SessionCookie *http.Cookie
+
+	// UserAgent is appended to the go-okta/Version User-Agent header of
+	// every request so Okta can tell the calling product, e.g. exporter/1.2
+	UserAgent string
+
+	// AccessToken is an OAuth 2.0 access token of the org authorization
+	// server, sent when there is no ApiToken, e.g. for the okta.myAccount
+	// scopes of end-user facing apps
+	AccessToken string
+
+	// Credentials supplies the token of every request when set, instead of
+	// ApiToken and AccessToken, see FileToken
+	Credentials CredentialsProvider
+
+	// DPoP sends access tokens with a proof of possession of the key they
+	// are bound to when set, see oauth.DPoP
+	DPoP DPoPProver
+
+	// BaseURL replaces https://{org}.{Url} when set, for custom domains
+	// such as https://login.example.com
+	BaseURL string
+
+	// DefaultQueryParams are added to every list request unless the
+	// request already sets the same parameter, e.g. limit=200
+	DefaultQueryParams url.Values
+
+	// Usage records call counts and rate limit consumption when set
+	Usage *UsageAnalyzer
+
+	// HookReceiver is the event hook endpoint of this org, Watch uses its
+	// deliveries instead of polling the System Log when set
+	HookReceiver *EventHookHandler
+
+	// WatchInterval is how often Watch polls the System Log, 10 seconds
+	// when zero
+	WatchInterval time.Duration
+
+	// Budget enables degradation mode, see ErrorBudget
+	Budget *ErrorBudget
+
+	// ErrorMap classifies error responses, DefaultErrorMap when nil
+	ErrorMap *ErrorMap
+
+	// OnDeprecation is called for every response announcing that its
+	// endpoint is deprecated, when nil a warning is logged once per endpoint
+	OnDeprecation func(DeprecationWarning)
+	deprecations  sync.Map
+
+	// Retry resends rate limited and failed requests, see RetryPolicy,
+	// requests are sent only once when nil
+	Retry *RetryPolicy
+
+	// OnRetry is called before sleeping for every retry, from the goroutine
+	// making the call
+	OnRetry func(RetryEvent)
+
+	// Hedge sends a second GET when the first one is slow, see HedgePolicy
+	// and WithHedging, GET requests are not hedged when nil
+	Hedge *HedgePolicy
+
+	// Cache stores GET responses carrying an ETag or Last-Modified header and
+	// revalidates them with If-None-Match and If-Modified-Since, see
+	// NewLRUCache
+	Cache Cache
+
+	// OnRateLimit is called with the rate limit of every response carrying
+	// the X-Rate-Limit headers, from the goroutine making the call, so
+	// schedulers can throttle before requests are rejected. RateLimits
+	// returns the latest of every bucket.
+	OnRateLimit func(RateLimit)
+	rateLimits  sync.Map
+
+	// StrictDecoding fails calls whose response has attributes without a
+	// field, e.g. in tests catching changes of the API
+	StrictDecoding bool
+
+	// PreserveUnknownFields keeps the attributes without a field in the
+	// Unknown field of the resources having one, see UnknownFields
+	PreserveUnknownFields bool
+
+	// OnResponse is called with the metadata of every response, from the
+	// goroutine making the call, for the headers of calls without a Context
+	// variant. See WithResponseMeta for those of a single call.
+	OnResponse func(ResponseMeta)
+
+	// Limiter delays requests to stay under the rate limits, see
+	// BucketLimiter. Requests are sent right away when nil.
+	Limiter Limiter
+
+	// CorrelationHeader is the header WithCorrelationID sends the id in,
+	// X-Correlation-Id when empty
+	CorrelationHeader string
+
+	// RequestTimeout bounds every call, retries and reading the response
+	// included, unless the context sets its own with WithCallOptions.
+	// Calls are only bounded by their context when zero.
+	RequestTimeout time.Duration
+
+	// MaxResponseSize is the largest response body in bytes accepted,
+	// larger responses fail with a ResponseTooLargeError. Unlimited when
+	// zero.
+	MaxResponseSize int64
+
+	// sessionMu guards SessionCookie once the client is shared, jarCookie
+	// is the SessionCookie last stored in the jar
+	sessionMu sync.Mutex
+	jarCookie *http.Cookie
 }
 
 // errorResponse is an error wrapper for the okta response
@@ -24,16 +146,24 @@ type errorResponse struct {
 	HTTPCode int
 	Response ErrorResponse
 	Endpoint string
+	Class    ErrorClass
+	// RequestID is the X-Okta-Request-Id of the response, to quote to Okta
+	// support
+	RequestID string
 }
 
 func (e *errorResponse) Error() string {
-	return fmt.Sprintf("Error hitting api endpoint %s %s", e.Endpoint, e.Response.ErrorCode)
+	if e.RequestID != "" {
+		return fmt.Sprintf("Error hitting api endpoint %s %s (request %s)", redactURL(e.Endpoint), e.Response.ErrorCode, e.RequestID)
+	}
+	return fmt.Sprintf("Error hitting api endpoint %s %s", redactURL(e.Endpoint), e.Response.ErrorCode)
 }
 
 // NewClient object for calling okta
 func NewClient(org string) *Client {
+	jar, _ := cookiejar.New(nil)
 	client := Client{
-		client: &http.Client{},
+		client: &http.Client{Jar: jar, Transport: defaultTransport},
 		org:    org,
 		Url:    "okta.com",
 	}
@@ -41,144 +171,582 @@ func NewClient(org string) *Client {
 	return &client
 }
 
-// Authenticate with okta using username and password
-func (c *Client) Authenticate(username, password string) (*AuthnResponse, error) {
-	var request = &AuthnRequest{
-		Username: username,
-		Password: password,
+// Jar returns the cookie jar of the client, it holds the session cookie
+// once Session succeeded and every cookie set while signing in to apps, so
+// that it can be shared with another http.Client
+func (c *Client) Jar() http.CookieJar {
+	return c.client.Jar
+}
+
+// SetHTTPClient makes the client send its requests through client, the
+// current cookie jar is kept when client has none
+func (c *Client) SetHTTPClient(client *http.Client) {
+	if client.Jar == nil {
+		client.Jar = c.client.Jar
+	}
+	c.client = client
+}
+
+// SetSessionCookie replaces the session of the client, unlike assigning
+// SessionCookie it is safe while the client is in use by other goroutines
+func (c *Client) SetSessionCookie(cookie *http.Cookie) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	c.SessionCookie = cookie
+	if u, err := url.Parse(c.baseURL()); err == nil && cookie != nil && c.client.Jar != nil {
+		c.client.Jar.SetCookies(u, []*http.Cookie{cookie})
+		c.jarCookie = cookie
+	}
+}
+
+// sessionCookie returns the current SessionCookie
+func (c *Client) sessionCookie() *http.Cookie {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	return c.SessionCookie
+}
+
+// addSessionCookie sends the session cookie with req, through the jar when
+// there is one so that cookies set by redirects are sent along with it
+func (c *Client) addSessionCookie(req *http.Request) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	cookie := c.SessionCookie
+	if cookie == nil {
+		return
+	}
+	if c.client.Jar == nil {
+		req.Header.Add("Cookie", cookie.String())
+		return
+	}
+	// only a changed SessionCookie is stored so that a sid the jar received
+	// from Okta since isn't replaced by an older one
+	if c.jarCookie != cookie {
+		c.client.Jar.SetCookies(req.URL, []*http.Cookie{cookie})
+		c.jarCookie = cookie
+	}
+}
+
+// Authenticate with okta using username and password, opts pass on the
+// context of the end user such as WithClientIP
+func (c *Client) Authenticate(username, password string, opts ...AuthnOption) (*AuthnResponse, error) {
+	return c.AuthenticateContext(context.Background(), username, password, opts...)
+}
+
+// AuthenticateContext is Authenticate honouring the deadline and call
+// options of ctx
+func (c *Client) AuthenticateContext(ctx context.Context, username, password string, opts ...AuthnOption) (*AuthnResponse, error) {
+	call := &authnCall{
+		request: &AuthnRequest{
+			Username: username,
+			Password: password,
+		},
+		header: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(call)
 	}
 
 	var response = &AuthnResponse{}
-	err, _ := c.call("authn", "POST", request, response)
+	err, _, _ := c.callHeader(ctx, "authn", "POST", call.header, call.request, response)
 	return response, err
 }
 
 // Session takes a session token and returns a session, the ID is stored
 // as a cookie so it can be consumed by this library and its clients.
+//
+// Deprecated: use Client.Sessions.Create of github.com/Cox-Automotive/go-okta/v2
 func (c *Client) Session(sessionToken string) (*SessionResponse, error) {
+	return c.SessionContext(context.Background(), sessionToken)
+}
+
+// SessionContext is Session honouring the deadline and call options of
+// ctx. A session token can only be used once, see SessionManager to sign
+// in again when creating the session fails.
+func (c *Client) SessionContext(ctx context.Context, sessionToken string) (*SessionResponse, error) {
 	var request = &SessionRequest{
 		SessionToken: sessionToken,
 	}
 
 	var response = &SessionResponse{}
-	err, _ := c.call("sessions", "POST", request, response)
+	err, _ := c.callContext(ctx, "sessions", "POST", request, response)
 	if err == nil {
-		c.SessionCookie = &http.Cookie{
+		c.SetSessionCookie(&http.Cookie{
 			Name:     "sid",
 			Value:    response.ID,
 			Path:     "/",
-			Domain:   c.org + "." + c.Url,
+			Domain:   c.cookieDomain(),
 			Secure:   true,
 			HttpOnly: true,
-		}
+		})
 	}
 	return response, err
 }
 
-// User takes a user id and returns data about that user
+func (c *Client) cookieDomain() string {
+	u, err := url.Parse(c.baseURL())
+	if err != nil {
+		return c.org + "." + c.Url
+	}
+	return u.Hostname()
+}
+
+// User takes a user id, login or primary email and returns data about
+// that user
+//
+// Deprecated: use Client.Users.Get of github.com/Cox-Automotive/go-okta/v2
 func (c *Client) User(userID string) (*User, error) {
+	return c.UserContext(context.Background(), userID)
+}
+
+// UpdateUserProfile replaces the profile of a user, attributes missing from
+// profile are removed. profile may be any value encoding to a JSON object,
+// e.g. a map[string]interface{} or json.RawMessage with custom attributes.
+func (c *Client) UpdateUserProfile(userID string, profile interface{}) (*User, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
 
 	var response = &User{}
-	err, _ := c.call("users/"+userID, "GET", nil, response)
+	err, _ := c.call("users/"+url.PathEscape(userID), "PUT", request, response)
 	return response, err
 }
 
+// UpdateUserProfilePartial merges profile into the current profile of a
+// user, only attributes present in profile are changed
+func (c *Client) UpdateUserProfilePartial(userID string, profile interface{}) (*User, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
+
+	var response = &User{}
+	err, _ := c.call("users/"+url.PathEscape(userID), "POST", request, response)
+	return response, err
+}
+
+// CreateUser creates a user, the user is activated right away if activate
+// is true, otherwise it is STAGED
+//
+// Deprecated: use Client.Users.Create of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) CreateUser(user *CreateUserRequest, activate bool) (*User, error) {
+	v := &url.Values{}
+	v.Add("activate", strconv.FormatBool(activate))
+
+	var response = &User{}
+	err, _ := c.call("users?"+v.Encode(), "POST", user, response)
+	return response, err
+}
+
+// ResetPassword expires the password of a user and sends them an email to
+// choose a new one if sendEmail is true, see ResetPasswordLink to get the
+// link instead
+func (c *Client) ResetPassword(userID string, sendEmail bool) error {
+	v := &url.Values{}
+	v.Add("sendEmail", strconv.FormatBool(sendEmail))
+
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/reset_password?"+v.Encode(), "POST", nil, nil)
+	return err
+}
+
+// AddUserToGroup makes a user a member of a group
+//
+// Deprecated: use Client.Groups.AddMember of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) AddUserToGroup(groupID, userID string) error {
+	err, _ := c.call("groups/"+url.PathEscape(groupID)+"/users/"+url.PathEscape(userID), "PUT", nil, nil)
+	return err
+}
+
+// RemoveUserFromGroup removes a user from a group
+//
+// Deprecated: use Client.Groups.RemoveMember of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) RemoveUserFromGroup(groupID, userID string) error {
+	err, _ := c.call("groups/"+url.PathEscape(groupID)+"/users/"+url.PathEscape(userID), "DELETE", nil, nil)
+	return err
+}
+
 // Groups takes a user id and returns the groups the user belongs to
+//
+// Deprecated: use Client.Users.ListGroups of github.com/Cox-Automotive/go-okta/v2
 func (c *Client) Groups(userID string) (*[]Group, error) {
+	return c.GroupsContext(context.Background(), userID)
+}
 
-	var response = &[]Group{}
-	var nextLink = "users/"+userID+"/groups?limit=200"
+// GroupMembers takes a group id and returns all users in the group
+//
+// Deprecated: use Client.Groups.ListMembers of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) GroupMembers(groupID string) (*[]User, error) {
+	var response = &[]User{}
+	err := c.listAll("groups/"+url.PathEscape(groupID)+"/users?limit=200", response)
+	return response, err
+}
 
-	for {
-		var resp = &[]Group{}
-		err, link := c.call(nextLink, "GET", nil, resp)
+// AppLinks takes a user id and returns the app links of the user, only
+// those of appName when it is not empty
+//
+// Deprecated: use Client.Apps.ListAppLinks of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) AppLinks(userID string, appName string) (*AppLinks, error) {
+	v := url.Values{}
+	if len(appName) > 0 {
+		v.Set("filter", filterExpr("appName", "eq", appName))
+	}
 
-		if err != nil {
-			return resp, err
-		}
+	var response = &AppLinks{}
+	err := c.listAll(withQuery("users/"+url.PathEscape(userID)+"/appLinks", v), response)
+	return response, err
+}
 
-		*response = append(*response, *resp...)
+// list issues a GET for a collection endpoint with the client's default
+// query parameters applied. Enrichment is dropped while the error budget is
+// exhausted and the result is flagged with a PartialResultError.
+func (c *Client) list(endpoint string, response interface{}) (error, string) {
+	return c.listContext(context.Background(), endpoint, response)
+}
 
-		parts := strings.Split(link, ";")
-		nextLink = strings.Replace(parts[0], fmt.Sprintf("<https://%s.okta.com/api/v1/", c.org), "", -1)
-		nextLink = strings.Replace(nextLink, ">", "", -1)
+func (c *Client) listContext(ctx context.Context, endpoint string, response interface{}) (error, string) {
+	endpoint = c.withDefaultParams(withExpand(ctx, endpoint))
+	ctx = withoutExpand(ctx)
 
-		if nextLink == "" {
-			break
+	var skipped []string
+	if c.Budget != nil && c.Budget.Degraded() {
+		endpoint, skipped = withoutEnrichment(endpoint)
+	}
+
+	err, link := c.callContext(ctx, endpoint, "GET", nil, response)
+	if err == nil && len(skipped) > 0 {
+		err = &PartialResultError{Endpoint: endpoint, Skipped: skipped}
+	}
+	return err, link
+}
+
+// listAll follows the next links of a collection endpoint and appends every
+// page to response, which must be a pointer to a slice
+func (c *Client) listAll(endpoint string, response interface{}) error {
+	return c.listAllContext(context.Background(), endpoint, response)
+}
+
+func (c *Client) listAllContext(ctx context.Context, endpoint string, response interface{}) error {
+	all := reflect.ValueOf(response).Elem()
+	var partial error
+
+	for endpoint != "" {
+		page := reflect.New(all.Type())
+		err, next := c.listContext(ctx, endpoint, page.Interface())
+		if IsPartialResult(err) {
+			partial = err
+		} else if err != nil {
+			return err
 		}
 
-		fmt.Println("go next link")
+		all.Set(reflect.AppendSlice(all, page.Elem()))
+
+		if next == endpoint {
+			break
+		}
+		endpoint = next
 	}
 
-	return response, nil
+	return partial
 }
 
-func (c *Client) AppLinks(userID string, appName string) (*AppLinks, error) {
-	u := "users/" + userID + "/appLinks"
+func (c *Client) withDefaultParams(endpoint string) string {
+	if len(c.DefaultQueryParams) == 0 {
+		return endpoint
+	}
 
-	if len(appName) > 0 {
-		v := &url.Values{}
-		v.Add("filter", fmt.Sprintf(`appName eq "%s"`, appName))
-		u += "?" + v.Encode()
+	path, rawQuery := endpoint, ""
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		path, rawQuery = endpoint[:i], endpoint[i+1:]
 	}
 
-	var response = &AppLinks{}
-	err, _ := c.call(u, "GET", nil, response)
-	return response, err
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return endpoint
+	}
+	for key, values := range c.DefaultQueryParams {
+		if _, ok := query[key]; !ok {
+			query[key] = values
+		}
+	}
+
+	return path + "?" + query.Encode()
+}
+
+// Do calls an endpoint this package has no method for yet, with the
+// authorization, retries, rate limiting and errors of the other calls.
+// path is relative to /api/v1/, e.g. users/00u1/factors, starts with / for
+// the other APIs of the org or is an absolute link returned by Okta. body
+// is encoded as JSON unless it is a []byte, which is sent as it is. out
+// may be nil, a GET into a pointer to a slice follows the next links and
+// appends every page.
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	endpoint := strings.TrimPrefix(path, "/api/v1/")
+	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
+		if endpoint = linkEndpoint(path); endpoint == "" {
+			return fmt.Errorf("okta: can't call %q", path)
+		}
+	}
+	method = strings.ToUpper(method)
+
+	if method == "GET" && out != nil {
+		if v := reflect.ValueOf(out); v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Slice {
+			return c.listAllContext(ctx, endpoint, out)
+		}
+	}
+	err, _ := c.callContext(ctx, endpoint, method, body, out)
+	return err
 }
 
 func (c *Client) call(endpoint, method string, request, response interface{}) (error, string) {
-	data, _ := json.Marshal(request)
-	link := ""
+	return c.callContext(context.Background(), endpoint, method, request, response)
+}
 
-	var url = "https://" + c.org + "." + c.Url + "/api/v1/" + endpoint
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
-	if err != nil {
-		return err, link
+func (c *Client) callContext(ctx context.Context, endpoint, method string, request, response interface{}) (error, string) {
+	err, link, _ := c.callHeader(ctx, endpoint, method, nil, request, response)
+	return err, link
+}
+
+// callHeader is callContext with headers replacing the defaults, it returns the
+// headers of the response too
+func (c *Client) callHeader(ctx context.Context, endpoint, method string, header http.Header, request, response interface{}) (error, string, http.Header) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if method == "GET" {
+		endpoint = withExpand(ctx, endpoint)
+	}
+
+	var data []byte
+	if raw, ok := request.([]byte); ok {
+		// sent as it is, header sets its Content-Type
+		data = raw
+	} else if request != nil {
+		data, _ = marshalRequest(request)
 	}
+	link := ""
 
-	req.Header.Add("Accept", `application/json`)
-	req.Header.Add("Content-Type", `application/json`)
-	if c.ApiToken != "" {
-		req.Header.Add("Authorization", "SSWS "+c.ApiToken)
+	var url = c.baseURL() + "/api/v1/" + endpoint
+	if strings.HasPrefix(endpoint, "/") {
+		// endpoints outside of the management API such as /idp/myaccount
+		url = c.baseURL() + endpoint
 	}
-	if c.SessionCookie != nil {
-		req.Header.Add("Cookie", c.SessionCookie.String())
+
+	var cached *CachedResponse
+	if c.Cache != nil && method == "GET" {
+		if cached = c.Cache.Get(c.cacheKey(url)); cached != nil {
+			header = cached.conditions(header)
+		}
 	}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return err, link
+	var resp *http.Response
+	nonceRetried := false
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(data))
+		if err != nil {
+			return err, link, nil
+		}
+
+		req.Header.Add("Accept", `application/json`)
+		req.Header.Add("Content-Type", `application/json`)
+		if err := c.authorize(ctx, req); err != nil {
+			return err, link, nil
+		}
+		req.Header.Set("User-Agent", c.userAgent())
+		if id := callOptionsOf(ctx).correlationID; id != "" {
+			req.Header.Set(c.correlationHeader(), id)
+		}
+		c.addSessionCookie(req)
+		for key, values := range header {
+			req.Header[http.CanonicalHeaderKey(key)] = values
+		}
+
+		if err := c.limit(ctx, method, endpoint); err != nil {
+			return err, link, nil
+		}
+
+		started := time.Now()
+		resp, err = c.send(ctx, req, endpoint)
+		err = redactError(err)
+		c.observeRateLimit(method, endpoint, resp)
+		if c.Usage != nil {
+			c.Usage.record(method, endpoint, resp)
+		}
+		if c.Budget != nil {
+			c.Budget.record(resp, time.Since(started))
+		}
+
+		if c.dpopRetry(req, resp) && !nonceRetried {
+			// sent again with the nonce, not counted as an attempt
+			nonceRetried = true
+			attempt--
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		retry, ok := c.retry(method, endpoint, attempt, resp, err)
+		if !ok {
+			if err != nil {
+				return err, link, nil
+			}
+			break
+		}
+		if resp != nil {
+			// drained so that the connection is reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := retry.wait(ctx); err != nil {
+			return err, link, nil
+		}
 	}
+	c.checkDeprecation(method, endpoint, resp.Header)
 	defer resp.Body.Close()
+	if meta := callOptionsOf(ctx).meta; meta != nil || c.OnResponse != nil {
+		m := newResponseMeta(method, endpoint, resp)
+		if meta != nil {
+			*meta = m
+		}
+		if c.OnResponse != nil {
+			c.OnResponse(m)
+		}
+	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	reader := c.limitBody(resp.Body, url)
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if success && c.Cache == nil && !c.PreserveUnknownFields {
+		// decoded while reading so that large pages aren't held in memory
+		// twice, 204 No Content and lifecycle operations have no body
+		if response != nil {
+			err := c.decode(reader, &response)
+			if err != nil && err != io.EOF {
+				return err, link, resp.Header
+			}
+		}
+		if _, err := io.Copy(ioutil.Discard, reader); err != nil {
+			return err, link, resp.Header
+		}
+		return nil, apiEndpoint(nextLink(resp.Header)), resp.Header
+	}
+
+	body, err := ioutil.ReadAll(reader)
 	if err != nil {
-		return err, link
+		return err, link, resp.Header
 	}
 
-	if resp.StatusCode == http.StatusOK {
-		err := json.Unmarshal(body, &response)
-		if err != nil {
-			return err, link
+	if c.Cache != nil {
+		body = c.revalidate(method, url, resp, body, cached)
+		success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	}
+
+	if success {
+		// 204 No Content and lifecycle operations return an empty body
+		if len(body) > 0 && response != nil {
+			err := c.decode(bytes.NewReader(body), &response)
+			if err != nil {
+				return err, link, resp.Header
+			}
+			if c.PreserveUnknownFields {
+				preserveUnknown(body, response)
+			}
 		}
 	} else {
 		var errors ErrorResponse
 		err = json.Unmarshal(body, &errors)
 
 		return &errorResponse{
-			HTTPCode: resp.StatusCode,
-			Response: errors,
-			Endpoint: url,
-		}, link
+			HTTPCode:  resp.StatusCode,
+			RequestID: resp.Header.Get("X-Okta-Request-Id"),
+			Response:  errors,
+			Endpoint:  url,
+			Class:     c.errorMap().Classify(resp.StatusCode, errors.ErrorCode),
+		}, link, resp.Header
+	}
+
+	link = apiEndpoint(nextLink(resp.Header))
+
+	return nil, link, resp.Header
+}
+
+// userAgent is the User-Agent header sent with every request
+func (c *Client) userAgent() string {
+	if c.UserAgent == "" {
+		return defaultUserAgent
 	}
+	return defaultUserAgent + " " + c.UserAgent
+}
+
+func (c *Client) correlationHeader() string {
+	if c.CorrelationHeader == "" {
+		return "X-Correlation-Id"
+	}
+	return c.CorrelationHeader
+}
+
+// defaultUserAgent identifies requests not made through a Client, e.g.
+// following the links of an authentication transaction
+const defaultUserAgent = "go-okta/" + Version
+
+// baseURL is the scheme and host requests are sent to
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return strings.TrimSuffix(c.BaseURL, "/")
+	}
+	return "https://" + c.org + "." + c.Url
+}
+
+// nextLink returns the target of the rel="next" Link header
+func nextLink(header http.Header) string {
+	return linkRel(header, "next")
+}
 
-	links := resp.Header.Values("Link")
-	if links != nil {
-		if len(links) == 2 {
-			link = links[1]
+// linkRel returns the target of the Link header with the given relation
+func linkRel(header http.Header, rel string) string {
+	return headerLinks(header)[rel]
+}
+
+// headerLinks returns the targets of the Link headers by relation, the
+// first one of a relation wins. Okta sends one header per relation but
+// several links in one header are accepted too.
+func headerLinks(header http.Header) map[string]string {
+	links := map[string]string{}
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				param = strings.Replace(strings.TrimSpace(param), " ", "", -1)
+				if !strings.HasPrefix(param, "rel=") {
+					continue
+				}
+				rel := strings.Trim(strings.TrimPrefix(param, "rel="), `"`)
+				if _, ok := links[rel]; !ok {
+					links[rel] = target
+				}
+			}
 		}
 	}
+	return links
+}
 
-	return nil, link
+// apiEndpoint turns an absolute link into an endpoint relative to /api/v1/
+// so it can be called with the configured base URL regardless of the
+// domain Okta put in the link
+func apiEndpoint(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	path := u.EscapedPath()
+	i := strings.Index(path, "/api/v1/")
+	if i < 0 || strings.HasPrefix(path, governanceAPI) {
+		// the governance API has an /api/v1/ of its own
+		return ""
+	}
+
+	endpoint := path[i+len("/api/v1/"):]
+	if u.RawQuery != "" {
+		endpoint += "?" + u.RawQuery
+	}
+	return endpoint
 }
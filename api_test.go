@@ -1,8 +1,14 @@
 package okta
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -68,3 +74,306 @@ func TestUserGroups(t *testing.T) {
 	}
 
 }
+
+func TestDefaultQueryParams(t *testing.T) {
+	client := NewClient("organization")
+	client.DefaultQueryParams = url.Values{"limit": {"200"}, "expand": {"stats"}}
+
+	endpoint := client.withDefaultParams("users/00u1/groups?limit=50")
+	if endpoint != "users/00u1/groups?expand=stats&limit=50" {
+		t.Error("Expected explicit limit to win over default, got ", endpoint)
+	}
+
+	endpoint = client.withDefaultParams("authorizationServers")
+	if endpoint != "authorizationServers?expand=stats&limit=200" {
+		t.Error("Expected defaults to be applied, got ", endpoint)
+	}
+}
+
+// newTestClient returns a client sending its requests to handler and a func
+// closing the server
+func newTestClient(handler http.Handler) (*Client, func()) {
+	server := httptest.NewTLSServer(handler)
+
+	client := NewClient("organization")
+	client.BaseURL = server.URL
+	client.SetHTTPClient(server.Client())
+	return client, server.Close
+}
+
+// requestRecorder is a test handler recording the method and URI of the
+// requests it passes on
+type requestRecorder struct {
+	handler http.Handler
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func recordRequests(handler http.Handler) *requestRecorder {
+	return &requestRecorder{handler: handler}
+}
+
+func (r *requestRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.requests = append(r.requests, req.Method+" "+req.URL.RequestURI())
+	r.mu.Unlock()
+	r.handler.ServeHTTP(w, req)
+}
+
+// reset forgets the requests recorded so far
+func (r *requestRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+// expect fails the test unless exactly the expected requests were received,
+// in order
+func (r *requestRecorder) expect(t *testing.T, expected ...string) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) != len(expected) {
+		t.Fatal("Expected requests ", expected, ", got ", r.requests)
+	}
+	for i := range expected {
+		if r.requests[i] != expected[i] {
+			t.Fatal("Expected requests ", expected, ", got ", r.requests)
+		}
+	}
+}
+
+// echoHandler answers writes with their body and reads with response
+func echoHandler(response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(response))
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(body)
+		}
+	}
+}
+
+func TestAPIEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"https://organization.okta.com/api/v1/users/00u1/groups?after=00g2&limit=200":        "users/00u1/groups?after=00g2&limit=200",
+		"https://organization.oktapreview.com/api/v1/users/00u1/groups?after=00g2&limit=200": "users/00u1/groups?after=00g2&limit=200",
+		"https://organization.okta-emea.com/api/v1/groups?after=00g2":                        "groups?after=00g2",
+		"https://login.example.com/api/v1/users/00u1/groups?after=00g2":                      "users/00u1/groups?after=00g2",
+		"http://127.0.0.1:8080/api/v1/authorizationServers":                                  "authorizationServers",
+		"https://organization.okta.com/governance/api/v1/campaigns?after=icicamp2":           "",
+		"": "",
+	}
+	for link, expected := range cases {
+		if got := apiEndpoint(link); got != expected {
+			t.Errorf("Expected %q for %s, got %q", expected, link, got)
+		}
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<https://login.example.com/api/v1/groups?limit=2>; rel="self"`)
+	header.Add("Link", `<https://login.example.com/api/v1/groups?after=00g2&limit=2>; rel="next"`)
+	if got := nextLink(header); got != "https://login.example.com/api/v1/groups?after=00g2&limit=2" {
+		t.Error("Expected next link, got ", got)
+	}
+
+	header = http.Header{}
+	header.Add("Link", `<https://a/api/v1/groups>; rel="self", <https://a/api/v1/groups?after=x>; rel=next`)
+	if got := nextLink(header); got != "https://a/api/v1/groups?after=x" {
+		t.Error("Expected next link from combined header, got ", got)
+	}
+
+	header = http.Header{}
+	header.Add("Link", `<https://a/api/v1/groups>; rel="self"`)
+	if got := nextLink(header); got != "" {
+		t.Error("Expected no next link on the last page, got ", got)
+	}
+}
+
+func TestGroupsPagination(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// links use a different host than the client to mimic vanity domains
+		w.Header().Add("Link", `<https://organization.okta.com`+r.URL.RequestURI()+`>; rel="self"`)
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Add("Link", `<https://organization.okta.com/api/v1/users/00u1/groups?after=00g1&limit=200>; rel="next"`)
+			w.Write([]byte(`[{"id":"00g1"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"00g2"}]`))
+	}))
+	defer done()
+
+	groups, err := client.Groups("00u1")
+	if err != nil {
+		t.Fatal("Expected nil, got ", err.Error())
+	}
+	if len(*groups) != 2 || (*groups)[1].ID != "00g2" {
+		t.Errorf("Expected both pages, got %+v", *groups)
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Add("Link", `<https://organization.okta.com/api/v1/groups/00g1/users?after=00u1&limit=200>; rel="next"`)
+			w.Write([]byte(`[{"id":"00u1","status":"ACTIVE","profile":{"login":"jane@example.com"}}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"00u2","status":"SUSPENDED","profile":{"login":"john@example.com"}}]`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	users, err := client.GroupMembers("00g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*users) != 2 || (*users)[0].Profile.Login != "jane@example.com" || (*users)[1].ID != "00u2" {
+		t.Errorf("Expected the members of both pages, got %+v", *users)
+	}
+	recorder.expect(t,
+		"GET /api/v1/groups/00g1/users?limit=200",
+		"GET /api/v1/groups/00g1/users?after=00u1&limit=200",
+	)
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+		w.Header().Set("X-Okta-Deprecation", "use /api/v1/apps instead")
+		w.Write([]byte(`[]`))
+	}))
+	defer done()
+
+	var warnings []DeprecationWarning
+	client.OnDeprecation = func(w DeprecationWarning) {
+		warnings = append(warnings, w)
+	}
+
+	if _, err := client.AppLinks("00u1", ""); err != nil {
+		t.Fatal("Expected nil, got ", err.Error())
+	}
+	if len(warnings) != 1 {
+		t.Fatal("Expected 1 warning, got ", len(warnings))
+	}
+	if warnings[0].Sunset == nil || warnings[0].Sunset.Year() != 2026 || warnings[0].Message != "use /api/v1/apps instead" {
+		t.Errorf("Unexpected warning %+v", warnings[0])
+	}
+}
+
+func TestSessionCookieJar(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sessions":
+			w.Write([]byte(`{"id":"102session"}`))
+		case "/app/sso":
+			http.SetCookie(w, &http.Cookie{Name: "DT", Value: "device", Path: "/"})
+			http.Redirect(w, r, "/api/v1/users/me", http.StatusFound)
+		default:
+			sid, err := r.Cookie("sid")
+			dt, _ := r.Cookie("DT")
+			if err != nil || sid.Value != "102session" || dt == nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"id":"00u1"}`))
+		}
+	}))
+	defer done()
+
+	if _, err := client.Session("token"); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.client.Get(client.BaseURL + "/app/sso")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Error("Expected the jar to carry the session through the redirect, got ", resp.StatusCode)
+	}
+
+	if _, err := client.User("me"); err != nil {
+		t.Error("Expected API calls to send the cookies of the jar, got ", err)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var agents []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.UserAgent())
+		w.Write([]byte(`{}`))
+	}))
+	defer done()
+
+	client.User("00u1")
+	client.UserAgent = "exporter/1.2"
+	client.User("00u1")
+
+	expected := []string{"go-okta/" + Version, "go-okta/" + Version + " exporter/1.2"}
+	if len(agents) != 2 || agents[0] != expected[0] || agents[1] != expected[1] {
+		t.Error("Expected ", expected, ", got ", agents)
+	}
+}
+
+func TestDo(t *testing.T) {
+	var requests []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch {
+		case r.URL.Path == "/api/v1/zones" && r.Method == "POST":
+			w.Write([]byte(`{"id":"nzo3"}`))
+		case r.URL.Path == "/api/v1/zones" && r.URL.Query().Get("after") == "":
+			w.Header().Set("Link", `<https://`+r.Host+`/api/v1/zones?after=nzo1>; rel="next"`)
+			w.Write([]byte(`[{"id":"nzo1"}]`))
+		case r.URL.Path == "/api/v1/zones":
+			w.Write([]byte(`[{"id":"nzo2"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007","errorSummary":"Not found: Resource not found: nzo4 (Zone)"}`))
+		}
+	}))
+	defer done()
+
+	var zones []struct {
+		ID string `json:"id"`
+	}
+	if err := client.Do(context.Background(), "get", "zones", nil, &zones); err != nil || len(zones) != 2 || zones[1].ID != "nzo2" {
+		t.Fatal("Expected both pages, got ", zones, err)
+	}
+
+	var zone struct {
+		ID string `json:"id"`
+	}
+	request := map[string]string{"name": "office"}
+	if err := client.Do(context.Background(), "POST", "/api/v1/zones", request, &zone); err != nil || zone.ID != "nzo3" {
+		t.Fatal("Expected the created zone, got ", zone, err)
+	}
+
+	err := client.Do(context.Background(), "DELETE", "zones/nzo4", nil, nil)
+	if ClassOf(err).Kind != ErrorNotFound {
+		t.Error("Expected a classified error, got ", err)
+	}
+
+	expected := []string{
+		"GET /api/v1/zones ",
+		"GET /api/v1/zones?after=nzo1 ",
+		`POST /api/v1/zones {"name":"office"}`,
+		"DELETE /api/v1/zones/nzo4 ",
+	}
+	for i := range expected {
+		if i >= len(requests) || requests[i] != expected[i] {
+			t.Fatal("Expected requests ", expected, ", got ", requests)
+		}
+	}
+}
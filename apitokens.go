@@ -0,0 +1,51 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// ApiTokenMetadata describes an SSWS token, the token value itself is only
+// shown once when created in the Admin Console
+type ApiTokenMetadata struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	UserID      string     `json:"userId"`
+	ClientName  string     `json:"clientName"`
+	TokenWindow string     `json:"tokenWindow"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	Network     struct {
+		Connection string   `json:"connection"`
+		Include    []string `json:"include"`
+		Exclude    []string `json:"exclude"`
+	} `json:"network"`
+}
+
+// ListApiTokens returns the metadata of every active API token in the org
+func (c *Client) ListApiTokens() (*[]ApiTokenMetadata, error) {
+	var response = &[]ApiTokenMetadata{}
+	err := c.listAll("api-tokens", response)
+	return response, err
+}
+
+// GetApiToken takes an API token id and returns its metadata
+func (c *Client) GetApiToken(tokenID string) (*ApiTokenMetadata, error) {
+	var response = &ApiTokenMetadata{}
+	err, _ := c.call("api-tokens/"+url.PathEscape(tokenID), "GET", nil, response)
+	return response, err
+}
+
+// RevokeApiToken revokes the API token with the given id
+func (c *Client) RevokeApiToken(tokenID string) error {
+	err, _ := c.call("api-tokens/"+url.PathEscape(tokenID), "DELETE", nil, nil)
+	return err
+}
+
+// RevokeCurrentApiToken revokes the token the client authenticates with,
+// the client can't make further calls with it
+func (c *Client) RevokeCurrentApiToken() error {
+	err, _ := c.call("api-tokens/current", "DELETE", nil, nil)
+	return err
+}
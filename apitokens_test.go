@@ -0,0 +1,51 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApiTokens(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/api-tokens" && r.URL.Query().Get("after") == "":
+			w.Header().Set("Link", `<https://`+r.Host+`/api/v1/api-tokens?after=00T1>; rel="next"`)
+			w.Write([]byte(`[{"id":"00T1","name":"Terraform","userId":"00u1","clientName":"Okta API","tokenWindow":"P30D",
+				"network":{"connection":"ZONE","include":["nzo1"]}}]`))
+		case r.URL.Path == "/api/v1/api-tokens":
+			w.Write([]byte(`[{"id":"00T2","name":"Sync","userId":"00u2","network":{"connection":"ANYWHERE"}}]`))
+		default:
+			w.Write([]byte(`{"id":"00T/2","name":"Sync","userId":"00u2","expiresAt":"2026-11-13T00:00:00.000Z"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	tokens, err := client.ListApiTokens()
+	if err != nil || len(*tokens) != 2 || (*tokens)[1].ID != "00T2" {
+		t.Fatal("Expected the tokens of both pages, got ", tokens, err)
+	}
+	if first := (*tokens)[0]; first.TokenWindow != "P30D" || first.Network.Connection != "ZONE" || first.Network.Include[0] != "nzo1" {
+		t.Errorf("Unexpected token %+v", first)
+	}
+	token, err := client.GetApiToken("00T/2")
+	if err != nil || token.UserID != "00u2" || token.ExpiresAt == nil || token.ExpiresAt.Year() != 2026 {
+		t.Fatal("Expected the token metadata, got ", token, err)
+	}
+	if err := client.RevokeApiToken("00T/2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RevokeCurrentApiToken(); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/api-tokens",
+		"GET /api/v1/api-tokens?after=00T1",
+		"GET /api/v1/api-tokens/00T%2F2",
+		"DELETE /api/v1/api-tokens/00T%2F2",
+		"DELETE /api/v1/api-tokens/current",
+	)
+}
@@ -0,0 +1,138 @@
+package okta
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ClientSecret is a client secret of an OIDC app, the secret itself is only
+// returned when it is created
+type ClientSecret struct {
+	ID           string     `json:"id"`
+	Status       string     `json:"status"`
+	ClientSecret string     `json:"client_secret"`
+	SecretHash   string     `json:"secret_hash"`
+	Created      *time.Time `json:"created"`
+	LastUpdated  *time.Time `json:"lastUpdated"`
+}
+
+// AppKey is a signing key of an app, as a JSON Web Key with the
+// certificate chain in X5C
+type AppKey struct {
+	Kid         string     `json:"kid"`
+	Kty         string     `json:"kty"`
+	Use         string     `json:"use"`
+	Alg         string     `json:"alg,omitempty"`
+	E           string     `json:"e"`
+	N           string     `json:"n"`
+	X5C         []string   `json:"x5c"`
+	X5T         string     `json:"x5t#S256"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+}
+
+func appCredentials(appID string) string {
+	return "apps/" + url.PathEscape(appID) + "/credentials/"
+}
+
+// ListClientSecrets returns the client secrets of an OIDC app, an app has
+// at most two
+func (c *Client) ListClientSecrets(appID string) (*[]ClientSecret, error) {
+	var response = &[]ClientSecret{}
+	err, _ := c.call(appCredentials(appID)+"secrets", "GET", nil, response)
+	return response, err
+}
+
+// CreateClientSecret adds a generated client secret to an OIDC app, it is
+// active right away next to the current one
+func (c *Client) CreateClientSecret(appID string) (*ClientSecret, error) {
+	var response = &ClientSecret{}
+	err, _ := c.call(appCredentials(appID)+"secrets", "POST", struct{}{}, response)
+	return response, err
+}
+
+// ActivateClientSecret activates an INACTIVE client secret
+func (c *Client) ActivateClientSecret(appID, secretID string) (*ClientSecret, error) {
+	var response = &ClientSecret{}
+	err, _ := c.call(appCredentials(appID)+"secrets/"+url.PathEscape(secretID)+"/lifecycle/activate", "POST", nil, response)
+	return response, err
+}
+
+// DeactivateClientSecret deactivates a client secret, the only active
+// secret of an app can't be deactivated
+func (c *Client) DeactivateClientSecret(appID, secretID string) (*ClientSecret, error) {
+	var response = &ClientSecret{}
+	err, _ := c.call(appCredentials(appID)+"secrets/"+url.PathEscape(secretID)+"/lifecycle/deactivate", "POST", nil, response)
+	return response, err
+}
+
+// DeleteClientSecret deletes an INACTIVE client secret
+func (c *Client) DeleteClientSecret(appID, secretID string) error {
+	err, _ := c.call(appCredentials(appID)+"secrets/"+url.PathEscape(secretID), "DELETE", nil, nil)
+	return err
+}
+
+// RotateClientSecret creates a new client secret and then deactivates and
+// deletes the other secrets of the app. The new secret is returned with
+// the secrets still in place when removing them fails, so the rotation can
+// be finished once the secret is deployed.
+func (c *Client) RotateClientSecret(appID string) (*ClientSecret, error) {
+	current, err := c.ListClientSecrets(appID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := c.CreateClientSecret(appID)
+	if err != nil {
+		return nil, err
+	}
+	for _, old := range *current {
+		if old.Status == "ACTIVE" {
+			if _, err := c.DeactivateClientSecret(appID, old.ID); err != nil {
+				return secret, err
+			}
+		}
+		if err := c.DeleteClientSecret(appID, old.ID); err != nil {
+			return secret, err
+		}
+	}
+	return secret, nil
+}
+
+// ListAppKeys returns the signing keys of an app
+func (c *Client) ListAppKeys(appID string) (*[]AppKey, error) {
+	var response = &[]AppKey{}
+	err, _ := c.call(appCredentials(appID)+"keys", "GET", nil, response)
+	return response, err
+}
+
+// AppKey returns the signing key of an app with the given key id
+func (c *Client) AppKey(appID, keyID string) (*AppKey, error) {
+	var response = &AppKey{}
+	err, _ := c.call(appCredentials(appID)+"keys/"+url.PathEscape(keyID), "GET", nil, response)
+	return response, err
+}
+
+// GenerateAppKey generates a signing key with a certificate valid for 2 to
+// 10 years. The app keeps signing with its current key until its
+// credentials are updated to the new kid.
+func (c *Client) GenerateAppKey(appID string, validityYears int) (*AppKey, error) {
+	v := &url.Values{}
+	v.Add("validityYears", strconv.Itoa(validityYears))
+
+	var response = &AppKey{}
+	err, _ := c.call(appCredentials(appID)+"keys/generate?"+v.Encode(), "POST", nil, response)
+	return response, err
+}
+
+// CloneAppKey copies a signing key of an app to the target app, e.g. to
+// share a certificate between apps of the same service provider
+func (c *Client) CloneAppKey(appID, keyID, targetAppID string) (*AppKey, error) {
+	v := &url.Values{}
+	v.Add("targetAid", targetAppID)
+
+	var response = &AppKey{}
+	err, _ := c.call(appCredentials(appID)+"keys/"+url.PathEscape(keyID)+"/clone?"+v.Encode(), "POST", nil, response)
+	return response, err
+}
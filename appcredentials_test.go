@@ -0,0 +1,57 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRotateClientSecret(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			w.Write([]byte(`[{"id":"ocs1","status":"ACTIVE","secret_hash":"yk4SVx4sUWVJVbHt6M-UPA"},{"id":"ocs2","status":"INACTIVE"}]`))
+		case r.URL.Path == "/api/v1/apps/0oa1/credentials/secrets" && r.Method == "POST":
+			w.Write([]byte(`{"id":"ocs3","status":"ACTIVE","client_secret":"secret"}`))
+		case r.Method == "POST":
+			w.Write([]byte(`{"status":"INACTIVE"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	secret, err := client.RotateClientSecret("0oa1")
+	if err != nil || secret.ID != "ocs3" || secret.ClientSecret != "secret" {
+		t.Fatal("Expected the new secret, got ", secret, err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/apps/0oa1/credentials/secrets",
+		"POST /api/v1/apps/0oa1/credentials/secrets",
+		"POST /api/v1/apps/0oa1/credentials/secrets/ocs1/lifecycle/deactivate",
+		"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs1",
+		"DELETE /api/v1/apps/0oa1/credentials/secrets/ocs2",
+	)
+}
+
+func TestGenerateAndCloneAppKey(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"kid":"key2","kty":"RSA","use":"sig","x5c":["MIIDqDCCApCgAwIBAgIGAVGNO4qeMA0GCSqGSIb3DQEBBQUAMIGUMQswCQYDVQQGEwJVUzETMBEG"],"x5t#S256":"5GOpy9CQVtfvBmu2T8BHvpKE4OGtC3BuS046t7p9pps"}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	key, err := client.GenerateAppKey("0oa1", 2)
+	if err != nil || key.Kid != "key2" || len(key.X5C) != 1 || key.X5T == "" {
+		t.Fatal("Expected the generated key, got ", key, err)
+	}
+	if _, err := client.CloneAppKey("0oa1", "key2", "0oa2"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"POST /api/v1/apps/0oa1/credentials/keys/generate?validityYears=2",
+		"POST /api/v1/apps/0oa1/credentials/keys/key2/clone?targetAid=0oa2",
+	)
+}
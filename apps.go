@@ -1,6 +1,17 @@
 package okta
 
-type AppLinks []struct {
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AppLinks are the links of the apps assigned to a user, shown on the end
+// user dashboard
+type AppLinks []AppLink
+
+type AppLink struct {
 	AppAssignmentID  string `json:"appAssignmentId"`
 	AppInstanceID    string `json:"appInstanceId"`
 	AppName          string `json:"appName"`
@@ -12,3 +23,203 @@ type AppLinks []struct {
 	LogoURL          string `json:"logoUrl"`
 	SortOrder        int64  `json:"sortOrder"`
 }
+
+// App is an app instance of the org
+type App struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Label       string     `json:"label"`
+	Status      string     `json:"status"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	SignOnMode  string     `json:"signOnMode"`
+	Features    []string   `json:"features"`
+	Visibility  struct {
+		AutoSubmitToolbar bool `json:"autoSubmitToolbar"`
+		Hide              struct {
+			IOS bool `json:"iOS"`
+			Web bool `json:"web"`
+		} `json:"hide"`
+		AppLinks map[string]bool `json:"appLinks"`
+	} `json:"visibility"`
+	Credentials AppCredentials         `json:"credentials"`
+	Settings    map[string]interface{} `json:"settings"`
+	Links       struct {
+		Logo []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+			Type string `json:"type"`
+		} `json:"logo"`
+		AppLinks []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		} `json:"appLinks"`
+	} `json:"_links"`
+	Embedded struct {
+		// User is the assignment of the user the apps were listed for
+		User *AppUser `json:"user"`
+	} `json:"_embedded"`
+	// Unknown are the attributes without a field, only decoded with
+	// Client.PreserveUnknownFields
+	Unknown UnknownFields `json:"-"`
+}
+
+// AppCredentials are the credentials of an app instance, Signing.Kid is the
+// key SAML and WS-Fed apps sign with
+type AppCredentials struct {
+	Scheme           string `json:"scheme,omitempty"`
+	UserNameTemplate struct {
+		Template string `json:"template,omitempty"`
+		Type     string `json:"type,omitempty"`
+	} `json:"userNameTemplate"`
+	Signing struct {
+		Kid string `json:"kid,omitempty"`
+	} `json:"signing"`
+}
+
+// Password sync states of an app user
+const (
+	SyncDisabled     = "DISABLED"
+	SyncOutOfSync    = "OUT_OF_SYNC"
+	SyncSyncing      = "SYNCING"
+	SyncSynchronized = "SYNCHRONIZED"
+	SyncError        = "ERROR"
+)
+
+// AppUser is the assignment of a user to an app instance
+type AppUser struct {
+	ID              string                 `json:"id,omitempty"`
+	ExternalID      string                 `json:"externalId,omitempty"`
+	Created         *time.Time             `json:"created,omitempty"`
+	LastUpdated     *time.Time             `json:"lastUpdated,omitempty"`
+	Scope           string                 `json:"scope,omitempty"`
+	Status          string                 `json:"status,omitempty"`
+	StatusChanged   *time.Time             `json:"statusChanged,omitempty"`
+	PasswordChanged *time.Time             `json:"passwordChanged,omitempty"`
+	SyncState       string                 `json:"syncState,omitempty"`
+	LastSync        *time.Time             `json:"lastSync,omitempty"`
+	Credentials     *AppUserCredentials    `json:"credentials,omitempty"`
+	Profile         map[string]interface{} `json:"profile,omitempty"`
+	Embedded        *struct {
+		// User is set when fetched with WithExpand("user")
+		User *User `json:"user,omitempty"`
+	} `json:"_embedded,omitempty"`
+}
+
+// AppUserCredentials are the credentials of a user for an app, Okta never
+// returns the password value
+type AppUserCredentials struct {
+	UserName string           `json:"userName,omitempty"`
+	Password *AppUserPassword `json:"password,omitempty"`
+}
+
+type AppUserPassword struct {
+	Value string `json:"value,omitempty"`
+}
+
+// AppUsers returns the users assigned to an app instance
+func (c *Client) AppUsers(appID string) (*[]AppUser, error) {
+	return c.AppUsersContext(context.Background(), appID)
+}
+
+// AppUsersContext is AppUsers honouring the deadline and call options of
+// ctx, WithExpand("user") embeds the user of each assignment
+func (c *Client) AppUsersContext(ctx context.Context, appID string) (*[]AppUser, error) {
+	var response = &[]AppUser{}
+	err := c.listAllContext(ctx, "apps/"+url.PathEscape(appID)+"/users?limit=500", response)
+	return response, err
+}
+
+// AppUser returns the assignment of a user to an app instance
+func (c *Client) AppUser(appID, userID string) (*AppUser, error) {
+	var response = &AppUser{}
+	err, _ := c.call("apps/"+url.PathEscape(appID)+"/users/"+url.PathEscape(userID), "GET", nil, response)
+	return response, err
+}
+
+// SetAppUserCredentials sets the app username and password of an assigned
+// user, e.g. for SWA apps vaulting the password in Okta. An empty password
+// only changes the username.
+func (c *Client) SetAppUserCredentials(appID, userID, userName, password string) (*AppUser, error) {
+	request := &AppUser{Credentials: &AppUserCredentials{UserName: userName}}
+	if password != "" {
+		request.Credentials.Password = &AppUserPassword{Value: password}
+	}
+
+	var response = &AppUser{}
+	err, _ := c.call("apps/"+url.PathEscape(appID)+"/users/"+url.PathEscape(userID), "POST", request, response)
+	return response, err
+}
+
+// ListAssignedApplicationsForUser returns the app instances a user is
+// assigned to, directly or through a group, with the assignment embedded
+// in each app. Unlike AppLinks it includes apps without a dashboard link.
+func (c *Client) ListAssignedApplicationsForUser(userID string) (*[]App, error) {
+	v := url.Values{}
+	v.Set("filter", filterExpr("user.id", "eq", userID))
+	v.Set("expand", "user/"+userID)
+	v.Set("limit", "200")
+
+	var response = &[]App{}
+	err := c.listAll(withQuery("apps", v), response)
+	return response, err
+}
+
+// AppRequest is the body of CreateApp and UpdateApp, fields left empty keep
+// the defaults of the app
+type AppRequest struct {
+	Name        string                 `json:"name"`
+	Label       string                 `json:"label"`
+	SignOnMode  string                 `json:"signOnMode"`
+	Features    []string               `json:"features,omitempty"`
+	Settings    map[string]interface{} `json:"settings,omitempty"`
+	Credentials *AppCredentials        `json:"credentials,omitempty"`
+	// Unknown is sent along, e.g. the Unknown of the App the request was
+	// built from
+	Unknown UnknownFields `json:"-"`
+}
+
+// ListApps returns every app instance of the org
+func (c *Client) ListApps() (*[]App, error) {
+	var response = &[]App{}
+	err := c.listAll("apps?limit=200", response)
+	return response, err
+}
+
+// CreateApp adds an app instance to the org, it is activated right away if
+// activate is true
+func (c *Client) CreateApp(app *AppRequest, activate bool) (*App, error) {
+	v := &url.Values{}
+	v.Add("activate", strconv.FormatBool(activate))
+
+	var response = &App{}
+	err, _ := c.call("apps?"+v.Encode(), "POST", app, response)
+	return response, err
+}
+
+// UpdateApp replaces the app instance with the given id, the name of an app
+// can't be changed
+func (c *Client) UpdateApp(appID string, app *AppRequest) (*App, error) {
+	var response = &App{}
+	err, _ := c.call("apps/"+url.PathEscape(appID), "PUT", app, response)
+	return response, err
+}
+
+// ActivateApp activates an INACTIVE app instance
+func (c *Client) ActivateApp(appID string) error {
+	err, _ := c.call("apps/"+url.PathEscape(appID)+"/lifecycle/activate", "POST", nil, nil)
+	return err
+}
+
+// DeactivateApp deactivates an app instance, it has to be deactivated
+// before it can be deleted
+func (c *Client) DeactivateApp(appID string) error {
+	err, _ := c.call("apps/"+url.PathEscape(appID)+"/lifecycle/deactivate", "POST", nil, nil)
+	return err
+}
+
+// DeleteApp deletes an INACTIVE app instance
+func (c *Client) DeleteApp(appID string) error {
+	err, _ := c.call("apps/"+url.PathEscape(appID), "DELETE", nil, nil)
+	return err
+}
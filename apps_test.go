@@ -0,0 +1,73 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestListAssignedApplicationsForUser(t *testing.T) {
+	var query string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{
+			"id": "0oa1",
+			"name": "bookmark",
+			"label": "Wiki",
+			"status": "ACTIVE",
+			"signOnMode": "BOOKMARK",
+			"visibility": {"hide": {"iOS": false, "web": true}, "appLinks": {"login": true}},
+			"_embedded": {"user": {"id": "00u1", "scope": "GROUP", "credentials": {"userName": "jane"}}}
+		}]`))
+	}))
+	defer done()
+
+	apps, err := client.ListAssignedApplicationsForUser("00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "expand=user%2F00u1&filter=user.id+eq+%2200u1%22&limit=200"; query != expected {
+		t.Error("Expected ", expected, ", got ", query)
+	}
+	if len(*apps) != 1 {
+		t.Fatal("Expected one app, got ", *apps)
+	}
+	app := (*apps)[0]
+	if app.Label != "Wiki" || !app.Visibility.Hide.Web || app.Embedded.User == nil || app.Embedded.User.Scope != "GROUP" {
+		t.Errorf("Expected the app with its assignment, got %+v", app)
+	}
+}
+
+func TestSetAppUserCredentials(t *testing.T) {
+	var bodies []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"id":"00u1","scope":"USER","status":"PROVISIONED","syncState":"SYNCHRONIZED","credentials":{"userName":"jane"},"passwordChanged":"2020-01-02T03:04:05.000Z"}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	user, err := client.SetAppUserCredentials("0oa1", "00u1", "jane", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Credentials == nil || user.Credentials.UserName != "jane" || user.Credentials.Password != nil || user.PasswordChanged == nil {
+		t.Errorf("Expected the credentials without the password, got %+v", user)
+	}
+	if _, err := client.SetAppUserCredentials("0oa1", "00u1", "jane.doe", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"credentials":{"userName":"jane","password":{"value":"correct horse"}}}`,
+		`{"credentials":{"userName":"jane.doe"}}`,
+	}
+	if len(bodies) != 2 || bodies[0] != expected[0] || bodies[1] != expected[1] {
+		t.Error("Expected bodies ", expected, ", got ", bodies)
+	}
+	recorder.expect(t,
+		"POST /api/v1/apps/0oa1/users/00u1",
+		"POST /api/v1/apps/0oa1/users/00u1",
+	)
+}
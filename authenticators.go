@@ -0,0 +1,174 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Push provider types
+const (
+	PushProviderAPNs = "APNS"
+	PushProviderFCM  = "FCM"
+)
+
+// PushProvider is the APNs or FCM configuration custom authenticators send
+// their push notifications through. The credentials in Configuration are
+// write only.
+type PushProvider struct {
+	ID              string     `json:"id,omitempty"`
+	Name            string     `json:"name"`
+	ProviderType    string     `json:"providerType"`
+	LastUpdatedDate *time.Time `json:"lastUpdatedDate,omitempty"`
+	Configuration   struct {
+		// KeyID, TeamID and TokenSigningKey, the PEM of the .p8 key, are
+		// the APNs settings
+		KeyID           string `json:"keyId,omitempty"`
+		TeamID          string `json:"teamId,omitempty"`
+		TokenSigningKey string `json:"tokenSigningKey,omitempty"`
+		// ServiceAccountJSON is the service account key of FCM
+		ServiceAccountJSON json.RawMessage `json:"serviceAccountJson,omitempty"`
+		FileName           string          `json:"fileName,omitempty"`
+	} `json:"configuration"`
+}
+
+// Authenticator is an authenticator of the org, such as okta_verify or a
+// custom_app authenticator built with the Devices SDK
+type Authenticator struct {
+	ID          string     `json:"id,omitempty"`
+	Key         string     `json:"key"`
+	Type        string     `json:"type,omitempty"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	// AgreeToCustomTermsOfService has to be set to create a custom_app
+	// authenticator
+	AgreeToCustomTermsOfService bool                   `json:"agreeToCustomTermsOfService,omitempty"`
+	Settings                    map[string]interface{} `json:"settings,omitempty"`
+	Provider                    *AuthenticatorProvider `json:"provider,omitempty"`
+}
+
+// AuthenticatorProvider connects a custom_app authenticator to the push
+// providers of its iOS and Android apps
+type AuthenticatorProvider struct {
+	Type          string `json:"type"`
+	Configuration struct {
+		APNs *AuthenticatorPush `json:"apns,omitempty"`
+		FCM  *AuthenticatorPush `json:"fcm,omitempty"`
+	} `json:"configuration"`
+}
+
+// AuthenticatorPush is the push provider with ID of an app of a custom
+// authenticator, the bundle ids only apply to APNs
+type AuthenticatorPush struct {
+	ID               string `json:"id"`
+	AppBundleID      string `json:"appBundleId,omitempty"`
+	DebugAppBundleID string `json:"debugAppBundleId,omitempty"`
+}
+
+// NewCustomAuthenticator returns a custom_app authenticator pushing through
+// the given APNs and FCM push providers, either id may be empty. Okta only
+// creates it once the caller accepted the custom authenticator terms of
+// service by setting AgreeToCustomTermsOfService.
+func NewCustomAuthenticator(name, appBundleID, apnsProviderID, fcmProviderID string) *Authenticator {
+	provider := &AuthenticatorProvider{Type: "PUSH"}
+	if apnsProviderID != "" {
+		provider.Configuration.APNs = &AuthenticatorPush{ID: apnsProviderID, AppBundleID: appBundleID}
+	}
+	if fcmProviderID != "" {
+		provider.Configuration.FCM = &AuthenticatorPush{ID: fcmProviderID}
+	}
+	return &Authenticator{
+		Key:      "custom_app",
+		Name:     name,
+		Provider: provider,
+		Settings: map[string]interface{}{"userVerification": "PREFERRED"},
+	}
+}
+
+// ListPushProviders returns the push providers of the org of a provider
+// type, all of them when empty
+func (c *Client) ListPushProviders(providerType string) (*[]PushProvider, error) {
+	v := url.Values{}
+	setNonEmpty(v, "type", providerType)
+
+	var response = &[]PushProvider{}
+	err, _ := c.call(withQuery("push-providers", v), "GET", nil, response)
+	return response, err
+}
+
+// PushProvider returns the push provider with the given id
+func (c *Client) PushProvider(providerID string) (*PushProvider, error) {
+	var response = &PushProvider{}
+	err, _ := c.call("push-providers/"+url.PathEscape(providerID), "GET", nil, response)
+	return response, err
+}
+
+// CreatePushProvider adds an APNs or FCM push provider
+func (c *Client) CreatePushProvider(provider *PushProvider) (*PushProvider, error) {
+	var response = &PushProvider{}
+	err, _ := c.call("push-providers", "POST", provider, response)
+	return response, err
+}
+
+// UpdatePushProvider replaces the push provider with the given id, e.g. to
+// rotate its key
+func (c *Client) UpdatePushProvider(providerID string, provider *PushProvider) (*PushProvider, error) {
+	var response = &PushProvider{}
+	err, _ := c.call("push-providers/"+url.PathEscape(providerID), "PUT", provider, response)
+	return response, err
+}
+
+// DeletePushProvider deletes a push provider no authenticator uses
+func (c *Client) DeletePushProvider(providerID string) error {
+	err, _ := c.call("push-providers/"+url.PathEscape(providerID), "DELETE", nil, nil)
+	return err
+}
+
+// ListAuthenticators returns the authenticators of the org
+func (c *Client) ListAuthenticators() (*[]Authenticator, error) {
+	var response = &[]Authenticator{}
+	err, _ := c.call("authenticators", "GET", nil, response)
+	return response, err
+}
+
+// Authenticator returns the authenticator with the given id
+func (c *Client) Authenticator(authenticatorID string) (*Authenticator, error) {
+	var response = &Authenticator{}
+	err, _ := c.call("authenticators/"+url.PathEscape(authenticatorID), "GET", nil, response)
+	return response, err
+}
+
+// CreateAuthenticator adds an authenticator, it is activated right away if
+// activate is true
+func (c *Client) CreateAuthenticator(authenticator *Authenticator, activate bool) (*Authenticator, error) {
+	v := &url.Values{}
+	v.Add("activate", strconv.FormatBool(activate))
+
+	var response = &Authenticator{}
+	err, _ := c.call("authenticators?"+v.Encode(), "POST", authenticator, response)
+	return response, err
+}
+
+// UpdateAuthenticator replaces the authenticator with the given id
+func (c *Client) UpdateAuthenticator(authenticatorID string, authenticator *Authenticator) (*Authenticator, error) {
+	var response = &Authenticator{}
+	err, _ := c.call("authenticators/"+url.PathEscape(authenticatorID), "PUT", authenticator, response)
+	return response, err
+}
+
+// ActivateAuthenticator activates an INACTIVE authenticator
+func (c *Client) ActivateAuthenticator(authenticatorID string) (*Authenticator, error) {
+	var response = &Authenticator{}
+	err, _ := c.call("authenticators/"+url.PathEscape(authenticatorID)+"/lifecycle/activate", "POST", nil, response)
+	return response, err
+}
+
+// DeactivateAuthenticator deactivates an authenticator no policy requires
+func (c *Client) DeactivateAuthenticator(authenticatorID string) (*Authenticator, error) {
+	var response = &Authenticator{}
+	err, _ := c.call("authenticators/"+url.PathEscape(authenticatorID)+"/lifecycle/deactivate", "POST", nil, response)
+	return response, err
+}
@@ -1,6 +1,12 @@
 package okta
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
 	"time"
 )
 
@@ -17,24 +23,124 @@ type ErrorResponse struct {
 type AuthnRequest struct {
 	Username   string `json:"username"`
 	Password   string `json:"password"`
-	RelayState string `json:"relayState"`
-	Options    struct {
+	RelayState string `json:"relayState,omitempty"`
+	// Audience is the app the session token will be used for
+	Audience string `json:"audience,omitempty"`
+	Options  struct {
 		MultiOptionalFactorEnroll bool `json:"multiOptionalFactorEnroll"`
 		WarnBeforePasswordExpired bool `json:"warnBeforePasswordExpired"`
 	} `json:"options"`
+	Context struct {
+		// DeviceToken identifies the device of the end user, so that
+		// policies based on new devices apply
+		DeviceToken string `json:"deviceToken,omitempty"`
+	} `json:"context"`
 }
 
+// AuthnOption changes the request of Authenticate
+type AuthnOption func(*authnCall)
+
+type authnCall struct {
+	request *AuthnRequest
+	header  http.Header
+}
+
+// WithClientIP sends the IP address of the end user signing in as
+// X-Forwarded-For, so that the risk engine and the System Log see the user
+// rather than the server calling Okta. Okta only honours it for requests
+// made with an API token.
+func WithClientIP(ip string) AuthnOption {
+	return func(c *authnCall) {
+		c.header.Set("X-Forwarded-For", ip)
+	}
+}
+
+// WithClientUserAgent sends the User-Agent of the end user's browser
+// instead of the one of the client, required for behavior detection and
+// new device notifications
+func WithClientUserAgent(userAgent string) AuthnOption {
+	return func(c *authnCall) {
+		c.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithRelayState sets the relayState returned with the transaction, e.g.
+// the URL to send the user to once signed in
+func WithRelayState(relayState string) AuthnOption {
+	return func(c *authnCall) {
+		c.request.RelayState = relayState
+	}
+}
+
+// WithAudience sets the app the session token will be used for
+func WithAudience(audience string) AuthnOption {
+	return func(c *authnCall) {
+		c.request.Audience = audience
+	}
+}
+
+// WithMultiOptionalFactorEnroll lets the user enroll optional factors
+// after the required ones, in MFA_ENROLL
+func WithMultiOptionalFactorEnroll() AuthnOption {
+	return func(c *authnCall) {
+		c.request.Options.MultiOptionalFactorEnroll = true
+	}
+}
+
+// WithWarnBeforePasswordExpired returns PASSWORD_WARN when the password is
+// about to expire instead of SUCCESS
+func WithWarnBeforePasswordExpired() AuthnOption {
+	return func(c *authnCall) {
+		c.request.Options.WarnBeforePasswordExpired = true
+	}
+}
+
+// WithDeviceToken sets the device token of the end user, an opaque value
+// of at most 32 characters the caller keeps per device
+func WithDeviceToken(deviceToken string) AuthnOption {
+	return func(c *authnCall) {
+		c.request.Context.DeviceToken = deviceToken
+	}
+}
+
+// WithDeviceFingerprint sends the X-Device-Fingerprint header computed by
+// the Okta sign-in widget, used for new device notifications
+func WithDeviceFingerprint(fingerprint string) AuthnOption {
+	return func(c *authnCall) {
+		c.header.Set("X-Device-Fingerprint", fingerprint)
+	}
+}
+
+// AuthnStatus is the state of an authentication transaction
+type AuthnStatus string
+
+// https://developer.okta.com/docs/reference/api/authn/#transaction-state
+const (
+	AuthnUnauthenticated   AuthnStatus = "UNAUTHENTICATED"
+	AuthnPasswordWarn      AuthnStatus = "PASSWORD_WARN"
+	AuthnPasswordExpired   AuthnStatus = "PASSWORD_EXPIRED"
+	AuthnRecovery          AuthnStatus = "RECOVERY"
+	AuthnRecoveryChallenge AuthnStatus = "RECOVERY_CHALLENGE"
+	AuthnPasswordReset     AuthnStatus = "PASSWORD_RESET"
+	AuthnLockedOut         AuthnStatus = "LOCKED_OUT"
+	AuthnMFAEnroll         AuthnStatus = "MFA_ENROLL"
+	AuthnMFAEnrollActivate AuthnStatus = "MFA_ENROLL_ACTIVATE"
+	AuthnMFARequired       AuthnStatus = "MFA_REQUIRED"
+	AuthnMFAChallenge      AuthnStatus = "MFA_CHALLENGE"
+	AuthnSuccess           AuthnStatus = "SUCCESS"
+)
+
 type AuthnResponse struct {
-	StateToken   string    `json:"stateToken"`
-	ExpiresAt    time.Time `json:"expiresAt"`
-	Status       string    `json:"status"`
-	RelayState   string    `json:"relayState"`
-	FactorResult string    `json:"factorResult"`
-	SessionToken string    `json:"sessionToken"`
+	StateToken   string      `json:"stateToken"`
+	ExpiresAt    *time.Time  `json:"expiresAt"`
+	Status       AuthnStatus `json:"status"`
+	RelayState   string      `json:"relayState"`
+	FactorResult string      `json:"factorResult"`
+	SessionToken string      `json:"sessionToken"`
 	Embedded     struct {
 		User struct {
-			ID              string    `json:"id"`
-			PasswordChanged time.Time `json:"passwordChanged"`
+			ID              string     `json:"id"`
+			PasswordChanged *time.Time `json:"passwordChanged"`
 			Profile         struct {
 				Login     string `json:"login"`
 				FirstName string `json:"firstName"`
@@ -44,7 +150,11 @@ type AuthnResponse struct {
 			} `json:"profile"`
 		} `json:"user"`
 		Factors []Factor `json:"factors"`
-		Policy  struct {
+		// Factor is the factor being activated in MFA_ENROLL_ACTIVATE
+		Factor *Factor `json:"factor"`
+		// Challenge is set in MFA_CHALLENGE for WebAuthn factors
+		Challenge *WebAuthnChallenge `json:"challenge"`
+		Policy    struct {
 			AllowRememberDevice             bool `json:"allowRememberDevice"`
 			RememberDeviceLifetimeInMinutes int  `json:"rememberDeviceLifetimeInMinutes"`
 			RememberDeviceByDefault         bool `json:"rememberDeviceByDefault"`
@@ -63,6 +173,172 @@ type AuthnResponse struct {
 			Hints struct {
 				Allow []string `json:"allow"`
 			} `json:"hints"`
-		}
+		} `json:"next"`
+		Prev struct {
+			Href string `json:"href"`
+		} `json:"prev"`
+		Skip struct {
+			Href string `json:"href"`
+		} `json:"skip"`
+		Resend []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		} `json:"resend"`
 	} `json:"_links"`
+
+	// challenged is when the challenge of MFA_CHALLENGE was sent, if known
+	challenged time.Time
+}
+
+// Done reports whether the transaction reached a state without further
+// transitions, SUCCESS or UNAUTHENTICATED after a cancel
+func (r *AuthnResponse) Done() bool {
+	return r.Status == AuthnSuccess || r.Status == AuthnUnauthenticated
+}
+
+// CanSkip reports whether the current step is optional, e.g. a password
+// warning or the enrollment of optional factors
+func (r *AuthnResponse) CanSkip() bool {
+	return r.Links.Skip.Href != ""
+}
+
+// Next advances the transaction along its next link, body holds the
+// fields the current state requires and gets the state token added
+func (r *AuthnResponse) Next(body map[string]interface{}) (*AuthnResponse, error) {
+	if r.Links.Next.Href == "" {
+		return nil, fmt.Errorf("no next transition from %s", r.Status)
+	}
+	return r.follow(r.Links.Next.Href, body)
+}
+
+// Prev returns to the previous state, e.g. from MFA_ENROLL_ACTIVATE back
+// to MFA_ENROLL to pick another factor
+func (r *AuthnResponse) Prev() (*AuthnResponse, error) {
+	if r.Links.Prev.Href == "" {
+		return nil, fmt.Errorf("no previous transition from %s", r.Status)
+	}
+	return r.follow(r.Links.Prev.Href, nil)
+}
+
+// Skip skips an optional step
+func (r *AuthnResponse) Skip() (*AuthnResponse, error) {
+	if !r.CanSkip() {
+		return nil, fmt.Errorf("can not skip %s", r.Status)
+	}
+	return r.follow(r.Links.Skip.Href, nil)
+}
+
+// Cancel ends the transaction, the state token can't be used afterwards
+func (r *AuthnResponse) Cancel() (*AuthnResponse, error) {
+	if r.Links.Cancel.Href == "" {
+		return nil, fmt.Errorf("can not cancel %s", r.Status)
+	}
+	return r.follow(r.Links.Cancel.Href, nil)
+}
+
+// ChangePassword sets a new password when the transaction is in
+// PASSWORD_EXPIRED or PASSWORD_WARN
+func (r *AuthnResponse) ChangePassword(oldPassword, newPassword string) (*AuthnResponse, error) {
+	if r.Status != AuthnPasswordExpired && r.Status != AuthnPasswordWarn {
+		return nil, fmt.Errorf("can not change password in %s", r.Status)
+	}
+	return r.Next(map[string]interface{}{
+		"oldPassword": oldPassword,
+		"newPassword": newPassword,
+	})
+}
+
+// ResetPassword sets a new password when the transaction is in
+// PASSWORD_RESET after a successful recovery
+func (r *AuthnResponse) ResetPassword(newPassword string) (*AuthnResponse, error) {
+	if r.Status != AuthnPasswordReset {
+		return nil, fmt.Errorf("can not reset password in %s", r.Status)
+	}
+	return r.Next(map[string]interface{}{
+		"newPassword": newPassword,
+	})
+}
+
+// AnswerRecoveryQuestion answers the recovery question in RECOVERY
+func (r *AuthnResponse) AnswerRecoveryQuestion(answer string) (*AuthnResponse, error) {
+	if r.Status != AuthnRecovery {
+		return nil, fmt.Errorf("can not answer recovery question in %s", r.Status)
+	}
+	return r.Next(map[string]interface{}{
+		"answer": answer,
+	})
+}
+
+// Unlock starts self-service unlock of a LOCKED_OUT user, factorType is the
+// recovery factor, EMAIL or SMS
+func (r *AuthnResponse) Unlock(username, factorType string) (*AuthnResponse, error) {
+	if r.Status != AuthnLockedOut {
+		return nil, fmt.Errorf("can not unlock in %s", r.Status)
+	}
+	if r.Links.Next.Href == "" {
+		return nil, errors.New("self-service unlock is not enabled")
+	}
+	return postAuthn(r.Links.Next.Href, map[string]interface{}{
+		"username":   username,
+		"factorType": factorType,
+	})
+}
+
+func (r *AuthnResponse) follow(href string, body map[string]interface{}) (*AuthnResponse, error) {
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	body["stateToken"] = r.StateToken
+	resp, err := postAuthn(href, body)
+	if err == nil && r.Status == AuthnMFAChallenge && resp.Status == AuthnMFAChallenge {
+		// e.g. a wrong passcode, the challenge wasn't sent again
+		resp.challenged = r.challenged
+	}
+	return resp, err
+}
+
+// postAuthn posts to a link of an authentication transaction and returns
+// the new state of the transaction
+func postAuthn(href string, body map[string]interface{}) (*AuthnResponse, error) {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest("POST", href, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	client := http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errors ErrorResponse
+		_ = json.Unmarshal(respBody, &errors)
+		return nil, &errorResponse{
+			HTTPCode:  resp.StatusCode,
+			RequestID: resp.Header.Get("X-Okta-Request-Id"),
+			Response:  errors,
+			Endpoint:  href,
+			Class:     DefaultErrorMap.Classify(resp.StatusCode, errors.ErrorCode),
+		}
+	}
+
+	var authnResp AuthnResponse
+	err = json.Unmarshal(respBody, &authnResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authnResp, nil
 }
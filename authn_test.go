@@ -0,0 +1,108 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthnTransitions(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		switch r.URL.Path {
+		case "/api/v1/authn/credentials/change_password":
+			w.Write([]byte(`{"status":"SUCCESS","sessionToken":"token"}`))
+		case "/api/v1/authn/cancel":
+			w.Write([]byte(`{"status":"UNAUTHENTICATED"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errorCode":"E0000011","errorSummary":"Invalid token provided"}`))
+		}
+	}))
+	defer server.Close()
+
+	expired := &AuthnResponse{StateToken: "state", Status: AuthnPasswordExpired}
+	expired.Links.Next.Href = server.URL + "/api/v1/authn/credentials/change_password"
+	expired.Links.Cancel.Href = server.URL + "/api/v1/authn/cancel"
+
+	if _, err := expired.Skip(); err == nil {
+		t.Error("Expected Skip to fail without a skip link")
+	}
+	if _, err := expired.ResetPassword("new"); err == nil {
+		t.Error("Expected ResetPassword to fail in PASSWORD_EXPIRED")
+	}
+
+	result, err := expired.ChangePassword("old", "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Done() || result.SessionToken != "token" {
+		t.Error("Expected SUCCESS with a session token, got ", result.Status)
+	}
+	if received["stateToken"] != "state" || received["oldPassword"] != "old" || received["newPassword"] != "new" {
+		t.Error("Expected state token and passwords to be posted, got ", received)
+	}
+
+	result, err = expired.Cancel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != AuthnUnauthenticated {
+		t.Error("Expected UNAUTHENTICATED after cancel, got ", result.Status)
+	}
+
+	expired.Links.Next.Href = server.URL + "/api/v1/authn/unknown"
+	if _, err := expired.Next(nil); ClassOf(err).Kind != ErrorUnauthorized {
+		t.Error("Expected a classified error, got ", err)
+	}
+}
+
+func TestAuthenticateClientContext(t *testing.T) {
+	var header http.Header
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{"status":"SUCCESS"}`))
+	}))
+	defer done()
+
+	_, err := client.Authenticate("username", "password",
+		WithClientIP("203.0.113.7"), WithClientUserAgent("Mozilla/5.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("X-Forwarded-For") != "203.0.113.7" || header.Get("User-Agent") != "Mozilla/5.0" {
+		t.Error("Expected the end user's IP and User-Agent, got ", header)
+	}
+}
+
+func TestAuthenticateRequestOptions(t *testing.T) {
+	var header http.Header
+	var received map[string]interface{}
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"status":"SUCCESS"}`))
+	}))
+	defer done()
+
+	_, err := client.Authenticate("username", "password",
+		WithRelayState("/dashboard"), WithAudience("0oa1"),
+		WithMultiOptionalFactorEnroll(), WithWarnBeforePasswordExpired(),
+		WithDeviceToken("26q43Ak9Eh04p7H6Nnx0m69JqYOrfVBY"), WithDeviceFingerprint("fingerprint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, _ := received["options"].(map[string]interface{})
+	context, _ := received["context"].(map[string]interface{})
+	if received["relayState"] != "/dashboard" || received["audience"] != "0oa1" ||
+		options["multiOptionalFactorEnroll"] != true || options["warnBeforePasswordExpired"] != true ||
+		context["deviceToken"] != "26q43Ak9Eh04p7H6Nnx0m69JqYOrfVBY" {
+		t.Error("Expected the options in the request, got ", received)
+	}
+	if header.Get("X-Device-Fingerprint") != "fingerprint" {
+		t.Error("Expected the device fingerprint header, got ", header)
+	}
+}
@@ -0,0 +1,313 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+type AuthorizationServer struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Audiences   []string   `json:"audiences"`
+	Issuer      string     `json:"issuer,omitempty"`
+	IssuerMode  string     `json:"issuerMode,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Credentials struct {
+		Signing struct {
+			Kid          string     `json:"kid,omitempty"`
+			LastRotated  *time.Time `json:"lastRotated,omitempty"`
+			NextRotation *time.Time `json:"nextRotation,omitempty"`
+			RotationMode string     `json:"rotationMode,omitempty"`
+			Use          string     `json:"use,omitempty"`
+		} `json:"signing"`
+	} `json:"credentials"`
+}
+
+// AuthorizationServerKey is a signing key of an authorization server, as a
+// JSON Web Key. Status is ACTIVE for the key signing tokens, NEXT for the
+// key the next rotation activates and EXPIRED for the key it replaced
+type AuthorizationServerKey struct {
+	Kid    string `json:"kid"`
+	Kty    string `json:"kty"`
+	Use    string `json:"use"`
+	Alg    string `json:"alg"`
+	E      string `json:"e"`
+	N      string `json:"n"`
+	Status string `json:"status"`
+}
+
+type AuthorizationServerScope struct {
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"displayName,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Consent         string `json:"consent,omitempty"`
+	MetadataPublish string `json:"metadataPublish,omitempty"`
+	Default         bool   `json:"default"`
+	System          bool   `json:"system,omitempty"`
+}
+
+type AuthorizationServerClaim struct {
+	ID                   string `json:"id,omitempty"`
+	Name                 string `json:"name"`
+	Status               string `json:"status,omitempty"`
+	ClaimType            string `json:"claimType"`
+	ValueType            string `json:"valueType"`
+	Value                string `json:"value"`
+	GroupFilterType      string `json:"group_filter_type,omitempty"`
+	AlwaysIncludeInToken bool   `json:"alwaysIncludeInToken"`
+	System               bool   `json:"system,omitempty"`
+	Conditions           struct {
+		Scopes []string `json:"scopes"`
+	} `json:"conditions"`
+}
+
+type AuthorizationServerPolicy struct {
+	ID          string     `json:"id,omitempty"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Status      string     `json:"status,omitempty"`
+	Priority    int        `json:"priority,omitempty"`
+	System      bool       `json:"system,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Conditions  struct {
+		Clients struct {
+			Include []string `json:"include"`
+		} `json:"clients"`
+	} `json:"conditions"`
+}
+
+type AuthorizationServerPolicyRule struct {
+	ID          string     `json:"id,omitempty"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status,omitempty"`
+	Priority    int        `json:"priority,omitempty"`
+	System      bool       `json:"system,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Conditions  struct {
+		People struct {
+			Users struct {
+				Include []string `json:"include"`
+				Exclude []string `json:"exclude"`
+			} `json:"users"`
+			Groups struct {
+				Include []string `json:"include"`
+				Exclude []string `json:"exclude"`
+			} `json:"groups"`
+		} `json:"people"`
+		GrantTypes struct {
+			Include []string `json:"include"`
+		} `json:"grantTypes"`
+		Scopes struct {
+			Include []string `json:"include"`
+		} `json:"scopes"`
+	} `json:"conditions"`
+	Actions struct {
+		Token struct {
+			AccessTokenLifetimeMinutes  int `json:"accessTokenLifetimeMinutes"`
+			RefreshTokenLifetimeMinutes int `json:"refreshTokenLifetimeMinutes"`
+			RefreshTokenWindowMinutes   int `json:"refreshTokenWindowMinutes"`
+			InlineHook                  *struct {
+				ID string `json:"id"`
+			} `json:"inlineHook,omitempty"`
+		} `json:"token"`
+	} `json:"actions"`
+}
+
+// AuthorizationServers returns all custom authorization servers in the org
+func (c *Client) AuthorizationServers() (*[]AuthorizationServer, error) {
+	var response = &[]AuthorizationServer{}
+	err := c.listAll("authorizationServers", response)
+	return response, err
+}
+
+// AuthorizationServer takes an authorization server id and returns it
+func (c *Client) AuthorizationServer(serverID string) (*AuthorizationServer, error) {
+	var response = &AuthorizationServer{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID), "GET", nil, response)
+	return response, err
+}
+
+// CreateAuthorizationServer creates a custom authorization server
+func (c *Client) CreateAuthorizationServer(server *AuthorizationServer) (*AuthorizationServer, error) {
+	var response = &AuthorizationServer{}
+	err, _ := c.call("authorizationServers", "POST", server, response)
+	return response, err
+}
+
+// UpdateAuthorizationServer replaces the authorization server with the given id
+func (c *Client) UpdateAuthorizationServer(serverID string, server *AuthorizationServer) (*AuthorizationServer, error) {
+	var response = &AuthorizationServer{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID), "PUT", server, response)
+	return response, err
+}
+
+// DeleteAuthorizationServer deletes the authorization server with the given id
+func (c *Client) DeleteAuthorizationServer(serverID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID), "DELETE", nil, nil)
+	return err
+}
+
+// ActivateAuthorizationServer makes the authorization server available for clients
+func (c *Client) ActivateAuthorizationServer(serverID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/lifecycle/activate", "POST", nil, nil)
+	return err
+}
+
+// DeactivateAuthorizationServer stops the authorization server from issuing tokens
+func (c *Client) DeactivateAuthorizationServer(serverID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/lifecycle/deactivate", "POST", nil, nil)
+	return err
+}
+
+// AuthorizationServerKeys returns the signing keys of an authorization server
+func (c *Client) AuthorizationServerKeys(serverID string) (*[]AuthorizationServerKey, error) {
+	var response = &[]AuthorizationServerKey{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/credentials/keys", "GET", nil, response)
+	return response, err
+}
+
+// RotateAuthorizationServerKeys activates the next signing key of an
+// authorization server and returns its keys. Only servers with a MANUAL
+// rotation mode can be rotated
+func (c *Client) RotateAuthorizationServerKeys(serverID string) (*[]AuthorizationServerKey, error) {
+	var response = &[]AuthorizationServerKey{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/credentials/lifecycle/keyRotate", "POST", map[string]string{"use": "sig"}, response)
+	return response, err
+}
+
+// AuthorizationServerScopes returns the scopes of an authorization server
+func (c *Client) AuthorizationServerScopes(serverID string) (*[]AuthorizationServerScope, error) {
+	var response = &[]AuthorizationServerScope{}
+	err := c.listAll("authorizationServers/"+url.PathEscape(serverID)+"/scopes", response)
+	return response, err
+}
+
+// AuthorizationServerScope returns a single scope of an authorization server
+func (c *Client) AuthorizationServerScope(serverID, scopeID string) (*AuthorizationServerScope, error) {
+	var response = &AuthorizationServerScope{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/scopes/"+url.PathEscape(scopeID), "GET", nil, response)
+	return response, err
+}
+
+// CreateAuthorizationServerScope adds a scope to an authorization server
+func (c *Client) CreateAuthorizationServerScope(serverID string, scope *AuthorizationServerScope) (*AuthorizationServerScope, error) {
+	var response = &AuthorizationServerScope{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/scopes", "POST", scope, response)
+	return response, err
+}
+
+// UpdateAuthorizationServerScope replaces a scope of an authorization server
+func (c *Client) UpdateAuthorizationServerScope(serverID, scopeID string, scope *AuthorizationServerScope) (*AuthorizationServerScope, error) {
+	var response = &AuthorizationServerScope{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/scopes/"+url.PathEscape(scopeID), "PUT", scope, response)
+	return response, err
+}
+
+// DeleteAuthorizationServerScope removes a scope from an authorization server
+func (c *Client) DeleteAuthorizationServerScope(serverID, scopeID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/scopes/"+url.PathEscape(scopeID), "DELETE", nil, nil)
+	return err
+}
+
+// AuthorizationServerClaims returns the claims of an authorization server
+func (c *Client) AuthorizationServerClaims(serverID string) (*[]AuthorizationServerClaim, error) {
+	var response = &[]AuthorizationServerClaim{}
+	err := c.listAll("authorizationServers/"+url.PathEscape(serverID)+"/claims", response)
+	return response, err
+}
+
+// AuthorizationServerClaim returns a single claim of an authorization server
+func (c *Client) AuthorizationServerClaim(serverID, claimID string) (*AuthorizationServerClaim, error) {
+	var response = &AuthorizationServerClaim{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/claims/"+url.PathEscape(claimID), "GET", nil, response)
+	return response, err
+}
+
+// CreateAuthorizationServerClaim adds a claim to an authorization server
+func (c *Client) CreateAuthorizationServerClaim(serverID string, claim *AuthorizationServerClaim) (*AuthorizationServerClaim, error) {
+	var response = &AuthorizationServerClaim{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/claims", "POST", claim, response)
+	return response, err
+}
+
+// UpdateAuthorizationServerClaim replaces a claim of an authorization server
+func (c *Client) UpdateAuthorizationServerClaim(serverID, claimID string, claim *AuthorizationServerClaim) (*AuthorizationServerClaim, error) {
+	var response = &AuthorizationServerClaim{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/claims/"+url.PathEscape(claimID), "PUT", claim, response)
+	return response, err
+}
+
+// DeleteAuthorizationServerClaim removes a claim from an authorization server
+func (c *Client) DeleteAuthorizationServerClaim(serverID, claimID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/claims/"+url.PathEscape(claimID), "DELETE", nil, nil)
+	return err
+}
+
+// AuthorizationServerPolicies returns the access policies of an authorization server
+func (c *Client) AuthorizationServerPolicies(serverID string) (*[]AuthorizationServerPolicy, error) {
+	var response = &[]AuthorizationServerPolicy{}
+	err := c.listAll("authorizationServers/"+url.PathEscape(serverID)+"/policies", response)
+	return response, err
+}
+
+// AuthorizationServerPolicy returns a single access policy of an authorization server
+func (c *Client) AuthorizationServerPolicy(serverID, policyID string) (*AuthorizationServerPolicy, error) {
+	var response = &AuthorizationServerPolicy{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID), "GET", nil, response)
+	return response, err
+}
+
+// CreateAuthorizationServerPolicy adds an access policy to an authorization server
+func (c *Client) CreateAuthorizationServerPolicy(serverID string, policy *AuthorizationServerPolicy) (*AuthorizationServerPolicy, error) {
+	var response = &AuthorizationServerPolicy{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies", "POST", policy, response)
+	return response, err
+}
+
+// UpdateAuthorizationServerPolicy replaces an access policy of an authorization server
+func (c *Client) UpdateAuthorizationServerPolicy(serverID, policyID string, policy *AuthorizationServerPolicy) (*AuthorizationServerPolicy, error) {
+	var response = &AuthorizationServerPolicy{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID), "PUT", policy, response)
+	return response, err
+}
+
+// DeleteAuthorizationServerPolicy removes an access policy from an authorization server
+func (c *Client) DeleteAuthorizationServerPolicy(serverID, policyID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID), "DELETE", nil, nil)
+	return err
+}
+
+// AuthorizationServerPolicyRules returns the rules of an access policy
+func (c *Client) AuthorizationServerPolicyRules(serverID, policyID string) (*[]AuthorizationServerPolicyRule, error) {
+	var response = &[]AuthorizationServerPolicyRule{}
+	err := c.listAll("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID)+"/rules", response)
+	return response, err
+}
+
+// CreateAuthorizationServerPolicyRule adds a rule to an access policy
+func (c *Client) CreateAuthorizationServerPolicyRule(serverID, policyID string, rule *AuthorizationServerPolicyRule) (*AuthorizationServerPolicyRule, error) {
+	var response = &AuthorizationServerPolicyRule{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID)+"/rules", "POST", rule, response)
+	return response, err
+}
+
+// UpdateAuthorizationServerPolicyRule replaces a rule of an access policy
+func (c *Client) UpdateAuthorizationServerPolicyRule(serverID, policyID, ruleID string, rule *AuthorizationServerPolicyRule) (*AuthorizationServerPolicyRule, error) {
+	var response = &AuthorizationServerPolicyRule{}
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID)+"/rules/"+url.PathEscape(ruleID), "PUT", rule, response)
+	return response, err
+}
+
+// DeleteAuthorizationServerPolicyRule removes a rule from an access policy
+func (c *Client) DeleteAuthorizationServerPolicyRule(serverID, policyID, ruleID string) error {
+	err, _ := c.call("authorizationServers/"+url.PathEscape(serverID)+"/policies/"+url.PathEscape(policyID)+"/rules/"+url.PathEscape(ruleID), "DELETE", nil, nil)
+	return err
+}
@@ -0,0 +1,291 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAuthorizationServers(t *testing.T) {
+	var bodies []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			bodies = append(bodies, string(body))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/authorizationServers":
+			w.Write([]byte(`[{"id":"aus1","name":"orders","audiences":["api://orders"],"issuerMode":"ORG_URL","status":"ACTIVE",
+				"credentials":{"signing":{"kid":"k1","rotationMode":"AUTO","use":"sig"}}}]`))
+		case strings.HasSuffix(r.URL.Path, "/lifecycle/activate") || strings.HasSuffix(r.URL.Path, "/lifecycle/deactivate"):
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"id":"aus1","name":"orders","audiences":["api://orders"],"status":"ACTIVE"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	servers, err := client.AuthorizationServers()
+	if err != nil || len(*servers) != 1 || (*servers)[0].Credentials.Signing.RotationMode != "AUTO" || (*servers)[0].Audiences[0] != "api://orders" {
+		t.Fatal("Expected the authorization server, got ", servers, err)
+	}
+	server, err := client.CreateAuthorizationServer(&AuthorizationServer{Name: "orders", Description: "Orders API", Audiences: []string{"api://orders"}})
+	if err != nil || server.ID != "aus1" {
+		t.Fatal("Expected the created server, got ", server, err)
+	}
+	if _, err := client.AuthorizationServer("aus 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateAuthorizationServer("aus1", &AuthorizationServer{Name: "orders", Description: "Orders", Audiences: []string{"api://orders"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeactivateAuthorizationServer("aus1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ActivateAuthorizationServer("aus1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteAuthorizationServer("aus1"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"name":"orders","description":"Orders API","audiences":["api://orders"],"credentials":{"signing":{}}}`,
+		`{"name":"orders","description":"Orders","audiences":["api://orders"],"credentials":{"signing":{}}}`,
+	}
+	if len(bodies) != len(expected) || bodies[0] != expected[0] || bodies[1] != expected[1] {
+		t.Error("Expected bodies ", expected, ", got ", bodies)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/authorizationServers",
+		"POST /api/v1/authorizationServers",
+		"GET /api/v1/authorizationServers/aus%201",
+		"PUT /api/v1/authorizationServers/aus1",
+		"POST /api/v1/authorizationServers/aus1/lifecycle/deactivate",
+		"POST /api/v1/authorizationServers/aus1/lifecycle/activate",
+		"DELETE /api/v1/authorizationServers/aus1",
+	)
+}
+
+func TestAuthorizationServerKeys(t *testing.T) {
+	var rotation string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			body, _ := ioutil.ReadAll(r.Body)
+			rotation = string(body)
+			w.Write([]byte(`[{"kid":"k2","kty":"RSA","use":"sig","alg":"RS256","status":"ACTIVE"},{"kid":"k3","kty":"RSA","use":"sig","status":"NEXT"},{"kid":"k1","kty":"RSA","use":"sig","status":"EXPIRED"}]`))
+			return
+		}
+		w.Write([]byte(`[{"kid":"k1","kty":"RSA","use":"sig","alg":"RS256","status":"ACTIVE"},{"kid":"k2","kty":"RSA","use":"sig","status":"NEXT"}]`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	keys, err := client.AuthorizationServerKeys("aus1")
+	if err != nil || len(*keys) != 2 || (*keys)[0].Status != "ACTIVE" || (*keys)[1].Kid != "k2" {
+		t.Fatal("Expected the active and next keys, got ", keys, err)
+	}
+	keys, err = client.RotateAuthorizationServerKeys("aus/1")
+	if err != nil || len(*keys) != 3 || (*keys)[0].Kid != "k2" || (*keys)[2].Status != "EXPIRED" {
+		t.Fatal("Expected the rotated keys, got ", keys, err)
+	}
+	if rotation != `{"use":"sig"}` {
+		t.Error("Expected the signing keys to be rotated, got ", rotation)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/authorizationServers/aus1/credentials/keys",
+		"POST /api/v1/authorizationServers/aus%2F1/credentials/lifecycle/keyRotate",
+	)
+}
+
+func TestAuthorizationServerScopesAndClaims(t *testing.T) {
+	var bodies []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" || r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/authorizationServers/aus1/scopes":
+			w.Write([]byte(`[{"id":"scp1","name":"openid","system":true,"default":false},{"id":"scp2","name":"orders:read","consent":"IMPLICIT","metadataPublish":"ALL_CLIENTS","default":true}]`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/authorizationServers/aus1/claims":
+			w.Write([]byte(`[{"id":"ocl1","name":"groups","claimType":"RESOURCE","valueType":"GROUPS","value":"Orders","group_filter_type":"STARTS_WITH","alwaysIncludeInToken":true,"conditions":{"scopes":["orders:read"]}}]`))
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(r.URL.Path, "/claims"):
+			w.Write([]byte(`{"id":"ocl1","name":"groups","claimType":"RESOURCE","valueType":"GROUPS","value":"Orders"}`))
+		default:
+			w.Write([]byte(`{"id":"scp2","name":"orders:read"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	scopes, err := client.AuthorizationServerScopes("aus1")
+	if err != nil || len(*scopes) != 2 || !(*scopes)[0].System || !(*scopes)[1].Default || (*scopes)[1].Consent != "IMPLICIT" {
+		t.Fatal("Expected the scopes, got ", scopes, err)
+	}
+	if _, err := client.CreateAuthorizationServerScope("aus1", &AuthorizationServerScope{Name: "orders:read", Consent: "IMPLICIT"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AuthorizationServerScope("aus1", "scp2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateAuthorizationServerScope("aus1", "scp2", &AuthorizationServerScope{Name: "orders:read", Default: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteAuthorizationServerScope("aus1", "scp 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := client.AuthorizationServerClaims("aus1")
+	if err != nil || len(*claims) != 1 || (*claims)[0].GroupFilterType != "STARTS_WITH" || (*claims)[0].Conditions.Scopes[0] != "orders:read" {
+		t.Fatal("Expected the claims, got ", claims, err)
+	}
+	claim := &AuthorizationServerClaim{Name: "groups", ClaimType: "RESOURCE", ValueType: "GROUPS", Value: "Orders", GroupFilterType: "STARTS_WITH", AlwaysIncludeInToken: true}
+	claim.Conditions.Scopes = []string{"orders:read"}
+	if _, err := client.CreateAuthorizationServerClaim("aus1", claim); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AuthorizationServerClaim("aus1", "ocl1"); err != nil {
+		t.Fatal(err)
+	}
+	claim.Value = "Orders-"
+	if _, err := client.UpdateAuthorizationServerClaim("aus1", "ocl1", claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteAuthorizationServerClaim("aus1", "ocl1"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"name":"orders:read","consent":"IMPLICIT","default":false}`,
+		`{"name":"orders:read","default":true}`,
+		`{"name":"groups","claimType":"RESOURCE","valueType":"GROUPS","value":"Orders","group_filter_type":"STARTS_WITH","alwaysIncludeInToken":true,"conditions":{"scopes":["orders:read"]}}`,
+		`{"name":"groups","claimType":"RESOURCE","valueType":"GROUPS","value":"Orders-","group_filter_type":"STARTS_WITH","alwaysIncludeInToken":true,"conditions":{"scopes":["orders:read"]}}`,
+	}
+	if len(bodies) != len(expected) {
+		t.Fatal("Expected bodies ", expected, ", got ", bodies)
+	}
+	for i := range expected {
+		if bodies[i] != expected[i] {
+			t.Errorf("Expected body %s, got %s", expected[i], bodies[i])
+		}
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/authorizationServers/aus1/scopes",
+		"POST /api/v1/authorizationServers/aus1/scopes",
+		"GET /api/v1/authorizationServers/aus1/scopes/scp2",
+		"PUT /api/v1/authorizationServers/aus1/scopes/scp2",
+		"DELETE /api/v1/authorizationServers/aus1/scopes/scp%202",
+		"GET /api/v1/authorizationServers/aus1/claims",
+		"POST /api/v1/authorizationServers/aus1/claims",
+		"GET /api/v1/authorizationServers/aus1/claims/ocl1",
+		"PUT /api/v1/authorizationServers/aus1/claims/ocl1",
+		"DELETE /api/v1/authorizationServers/aus1/claims/ocl1",
+	)
+}
+
+func TestAuthorizationServerPolicies(t *testing.T) {
+	var bodies []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" || r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/authorizationServers/aus1/policies":
+			w.Write([]byte(`[{"id":"00p1","type":"OAUTH_AUTHORIZATION_POLICY","name":"Default","priority":1,"system":true,"conditions":{"clients":{"include":["ALL_CLIENTS"]}}}]`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/rules"):
+			w.Write([]byte(`[{"id":"0pr1","type":"RESOURCE_ACCESS","name":"Service","priority":1,
+				"conditions":{"grantTypes":{"include":["client_credentials"]},"scopes":{"include":["orders:read"]}},
+				"actions":{"token":{"accessTokenLifetimeMinutes":60,"inlineHook":{"id":"cal1"}}}}]`))
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case strings.Contains(r.URL.Path, "/rules"):
+			w.Write([]byte(`{"id":"0pr1","type":"RESOURCE_ACCESS","name":"Service"}`))
+		default:
+			w.Write([]byte(`{"id":"00p1","type":"OAUTH_AUTHORIZATION_POLICY","name":"Services"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	policies, err := client.AuthorizationServerPolicies("aus1")
+	if err != nil || len(*policies) != 1 || !(*policies)[0].System || (*policies)[0].Conditions.Clients.Include[0] != "ALL_CLIENTS" {
+		t.Fatal("Expected the default policy, got ", policies, err)
+	}
+	policy := &AuthorizationServerPolicy{Type: "OAUTH_AUTHORIZATION_POLICY", Name: "Services", Description: "Service clients"}
+	policy.Conditions.Clients.Include = []string{"0oa1"}
+	if _, err := client.CreateAuthorizationServerPolicy("aus1", policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AuthorizationServerPolicy("aus1", "00p1"); err != nil {
+		t.Fatal(err)
+	}
+	policy.Priority = 2
+	if _, err := client.UpdateAuthorizationServerPolicy("aus1", "00p1", policy); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := client.AuthorizationServerPolicyRules("aus1", "00p1")
+	if err != nil || len(*rules) != 1 || (*rules)[0].Actions.Token.InlineHook == nil || (*rules)[0].Actions.Token.InlineHook.ID != "cal1" ||
+		(*rules)[0].Conditions.GrantTypes.Include[0] != "client_credentials" {
+		t.Fatal("Expected the rule, got ", rules, err)
+	}
+	rule := &AuthorizationServerPolicyRule{Type: "RESOURCE_ACCESS", Name: "Service"}
+	rule.Conditions.People.Groups.Include = []string{"EVERYONE"}
+	rule.Conditions.GrantTypes.Include = []string{"client_credentials"}
+	rule.Conditions.Scopes.Include = []string{"orders:read"}
+	rule.Actions.Token.AccessTokenLifetimeMinutes = 60
+	if _, err := client.CreateAuthorizationServerPolicyRule("aus1", "00p1", rule); err != nil {
+		t.Fatal(err)
+	}
+	rule.Actions.Token.AccessTokenLifetimeMinutes = 30
+	if _, err := client.UpdateAuthorizationServerPolicyRule("aus1", "00p1", "0pr/1", rule); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteAuthorizationServerPolicyRule("aus1", "00p1", "0pr1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteAuthorizationServerPolicy("aus1", "00p1"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"type":"OAUTH_AUTHORIZATION_POLICY","name":"Services","description":"Service clients","conditions":{"clients":{"include":["0oa1"]}}}`,
+		`{"type":"OAUTH_AUTHORIZATION_POLICY","name":"Services","description":"Service clients","priority":2,"conditions":{"clients":{"include":["0oa1"]}}}`,
+		`{"type":"RESOURCE_ACCESS","name":"Service","conditions":{"people":{"users":{"include":null,"exclude":null},"groups":{"include":["EVERYONE"],"exclude":null}},` +
+			`"grantTypes":{"include":["client_credentials"]},"scopes":{"include":["orders:read"]}},` +
+			`"actions":{"token":{"accessTokenLifetimeMinutes":60,"refreshTokenLifetimeMinutes":0,"refreshTokenWindowMinutes":0}}}`,
+		`{"type":"RESOURCE_ACCESS","name":"Service","conditions":{"people":{"users":{"include":null,"exclude":null},"groups":{"include":["EVERYONE"],"exclude":null}},` +
+			`"grantTypes":{"include":["client_credentials"]},"scopes":{"include":["orders:read"]}},` +
+			`"actions":{"token":{"accessTokenLifetimeMinutes":30,"refreshTokenLifetimeMinutes":0,"refreshTokenWindowMinutes":0}}}`,
+	}
+	if len(bodies) != len(expected) {
+		t.Fatal("Expected bodies ", expected, ", got ", bodies)
+	}
+	for i := range expected {
+		if bodies[i] != expected[i] {
+			t.Errorf("Expected body %s, got %s", expected[i], bodies[i])
+		}
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/authorizationServers/aus1/policies",
+		"POST /api/v1/authorizationServers/aus1/policies",
+		"GET /api/v1/authorizationServers/aus1/policies/00p1",
+		"PUT /api/v1/authorizationServers/aus1/policies/00p1",
+		"GET /api/v1/authorizationServers/aus1/policies/00p1/rules",
+		"POST /api/v1/authorizationServers/aus1/policies/00p1/rules",
+		"PUT /api/v1/authorizationServers/aus1/policies/00p1/rules/0pr%2F1",
+		"DELETE /api/v1/authorizationServers/aus1/policies/00p1/rules/0pr1",
+		"DELETE /api/v1/authorizationServers/aus1/policies/00p1",
+	)
+}
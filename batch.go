@@ -0,0 +1,135 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// batchAttempts is how often a rate limited item of a batch is tried
+const batchAttempts = 3
+
+// BatchError holds the errors of the items of a batch that failed, keyed by
+// the id of the item
+type BatchError map[string]error
+
+func (e BatchError) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, id+": "+e[id].Error())
+	}
+	return fmt.Sprintf("%d items failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// UsersBatch fetches users with at most concurrency requests in flight.
+// Workers pause when the remaining rate limit gets down to the number of
+// workers and until the window resets after a 429, rate limited users are
+// retried. The users fetched are returned in the order of ids together with
+// a BatchError for the others.
+func (c *Client) UsersBatch(ids []string, concurrency int) (*[]User, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	users := make([]*User, len(ids))
+	failed := BatchError{}
+	var mu sync.Mutex
+	gate := &rateGate{reserve: concurrency}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				user, err := c.batchUser(gate, ids[i])
+				mu.Lock()
+				if err != nil {
+					failed[ids[i]] = err
+				} else {
+					users[i] = user
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range ids {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	var response = &[]User{}
+	for _, user := range users {
+		if user != nil {
+			*response = append(*response, *user)
+		}
+	}
+	if len(failed) > 0 {
+		return response, failed
+	}
+	return response, nil
+}
+
+func (c *Client) batchUser(gate *rateGate, userID string) (*User, error) {
+	for attempt := 1; ; attempt++ {
+		gate.wait()
+
+		var response = &User{}
+		err, _, header := c.callHeader(context.Background(), "users/"+url.PathEscape(userID), "GET", nil, nil, response)
+		gate.observe(header)
+		if ClassOf(err).Kind != ErrorRateLimited || attempt == batchAttempts {
+			return response, err
+		}
+	}
+}
+
+// rateGate holds back the workers of a batch while the rate limit is
+// about to be exhausted
+type rateGate struct {
+	// reserve is the number of remaining requests at which workers pause,
+	// the number of requests that may be in flight
+	reserve int
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (g *rateGate) wait() {
+	g.mu.Lock()
+	until := g.until
+	g.mu.Unlock()
+	if wait := time.Until(until); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+func (g *rateGate) observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-Rate-Limit-Remaining"))
+	if err != nil || remaining > g.reserve {
+		return
+	}
+	reset := rateLimitWait(header)
+	if reset == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := time.Now().Add(reset); until.After(g.until) {
+		g.until = until
+	}
+}
@@ -0,0 +1,45 @@
+package okta
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUsersBatch(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/users/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"` + id + `"}`))
+	}))
+	defer done()
+
+	ids := []string{"00u1", "missing", "00u2", "00u3", "00u4", "00u5"}
+	users, err := client.UsersBatch(ids, 2)
+
+	batch, ok := err.(BatchError)
+	if !ok || len(batch) != 1 || ClassOf(batch["missing"]).Kind != ErrorNotFound {
+		t.Fatal("Expected a batch error for the missing user, got ", err)
+	}
+	if len(*users) != 5 || (*users)[0].ID != "00u1" || (*users)[1].ID != "00u2" {
+		t.Error("Expected the other users in order, got ", *users)
+	}
+	if maxInFlight > 2 {
+		t.Error("Expected at most 2 requests in flight, got ", maxInFlight)
+	}
+}
@@ -0,0 +1,157 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Behavior detection rule types
+const (
+	BehaviorAnomalousLocation = "ANOMALOUS_LOCATION"
+	BehaviorAnomalousDevice   = "ANOMALOUS_DEVICE"
+	BehaviorAnomalousIP       = "ANOMALOUS_IP"
+	BehaviorVelocity          = "VELOCITY"
+)
+
+// BehaviorRule detects a change in the sign in behavior of users, policies
+// can require MFA when it matches
+type BehaviorRule struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Settings    struct {
+		MaxEventsUsedForEvaluation   int `json:"maxEventsUsedForEvaluation,omitempty"`
+		MinEventsNeededForEvaluation int `json:"minEventsNeededForEvaluation,omitempty"`
+		// Granularity is LAT_LONG, CITY, COUNTRY or SUBDIVISION for
+		// ANOMALOUS_LOCATION rules
+		Granularity      string `json:"granularity,omitempty"`
+		RadiusKilometers int    `json:"radiusKilometers,omitempty"`
+		VelocityKph      int    `json:"velocityKph,omitempty"`
+	} `json:"settings"`
+}
+
+// Actions of a risk provider
+const (
+	RiskActionNone          = "none"
+	RiskActionLogOnly       = "log_only"
+	RiskActionEnforceAndLog = "enforce_and_log"
+)
+
+// RiskProvider is a third party sending risk events through the service
+// app with ClientID
+type RiskProvider struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	ClientID    string     `json:"clientId"`
+	Action      string     `json:"action"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+// RiskEvent reports the risk of IP addresses until ExpiresAt
+type RiskEvent struct {
+	Timestamp *time.Time         `json:"timestamp,omitempty"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Subjects  []RiskEventSubject `json:"subjects"`
+}
+
+type RiskEventSubject struct {
+	IP string `json:"ip"`
+	// RiskLevel is LOW, MEDIUM or HIGH
+	RiskLevel string `json:"riskLevel"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ListBehaviorRules returns the behavior detection rules of the org
+func (c *Client) ListBehaviorRules() (*[]BehaviorRule, error) {
+	var response = &[]BehaviorRule{}
+	err := c.listAll("behaviors", response)
+	return response, err
+}
+
+// BehaviorRule takes a rule id and returns the behavior detection rule
+func (c *Client) BehaviorRule(ruleID string) (*BehaviorRule, error) {
+	var response = &BehaviorRule{}
+	err, _ := c.call("behaviors/"+url.PathEscape(ruleID), "GET", nil, response)
+	return response, err
+}
+
+// CreateBehaviorRule adds an ACTIVE behavior detection rule
+func (c *Client) CreateBehaviorRule(rule *BehaviorRule) (*BehaviorRule, error) {
+	var response = &BehaviorRule{}
+	err, _ := c.call("behaviors", "POST", rule, response)
+	return response, err
+}
+
+// UpdateBehaviorRule replaces the behavior detection rule with the given id
+func (c *Client) UpdateBehaviorRule(ruleID string, rule *BehaviorRule) (*BehaviorRule, error) {
+	var response = &BehaviorRule{}
+	err, _ := c.call("behaviors/"+url.PathEscape(ruleID), "PUT", rule, response)
+	return response, err
+}
+
+// ActivateBehaviorRule activates an INACTIVE behavior detection rule
+func (c *Client) ActivateBehaviorRule(ruleID string) (*BehaviorRule, error) {
+	var response = &BehaviorRule{}
+	err, _ := c.call("behaviors/"+url.PathEscape(ruleID)+"/lifecycle/activate", "POST", nil, response)
+	return response, err
+}
+
+// DeactivateBehaviorRule deactivates a behavior detection rule, policies
+// using it no longer match it
+func (c *Client) DeactivateBehaviorRule(ruleID string) (*BehaviorRule, error) {
+	var response = &BehaviorRule{}
+	err, _ := c.call("behaviors/"+url.PathEscape(ruleID)+"/lifecycle/deactivate", "POST", nil, response)
+	return response, err
+}
+
+// DeleteBehaviorRule deletes a behavior detection rule no policy uses
+func (c *Client) DeleteBehaviorRule(ruleID string) error {
+	err, _ := c.call("behaviors/"+url.PathEscape(ruleID), "DELETE", nil, nil)
+	return err
+}
+
+// ListRiskProviders returns the risk providers of the org
+func (c *Client) ListRiskProviders() (*[]RiskProvider, error) {
+	var response = &[]RiskProvider{}
+	err, _ := c.call("risk/providers", "GET", nil, response)
+	return response, err
+}
+
+// RiskProvider takes a provider id and returns the risk provider
+func (c *Client) RiskProvider(providerID string) (*RiskProvider, error) {
+	var response = &RiskProvider{}
+	err, _ := c.call("risk/providers/"+url.PathEscape(providerID), "GET", nil, response)
+	return response, err
+}
+
+// CreateRiskProvider adds a risk provider, an org has at most three
+func (c *Client) CreateRiskProvider(provider *RiskProvider) (*RiskProvider, error) {
+	var response = &RiskProvider{}
+	err, _ := c.call("risk/providers", "POST", provider, response)
+	return response, err
+}
+
+// UpdateRiskProvider replaces the risk provider with the given id, e.g. to
+// change its action
+func (c *Client) UpdateRiskProvider(providerID string, provider *RiskProvider) (*RiskProvider, error) {
+	var response = &RiskProvider{}
+	err, _ := c.call("risk/providers/"+url.PathEscape(providerID), "PUT", provider, response)
+	return response, err
+}
+
+// DeleteRiskProvider deletes the risk provider with the given id
+func (c *Client) DeleteRiskProvider(providerID string) error {
+	err, _ := c.call("risk/providers/"+url.PathEscape(providerID), "DELETE", nil, nil)
+	return err
+}
+
+// SendRiskEvents reports risk events, it needs an access token of the
+// service app of a risk provider
+func (c *Client) SendRiskEvents(events []RiskEvent) error {
+	err, _ := c.call("risk/events/ip", "POST", events, nil)
+	return err
+}
@@ -0,0 +1,29 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestCreateBehaviorRule(t *testing.T) {
+	var created map[string]interface{}
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&created)
+		w.Write([]byte(`{"id":"abcd1234","name":"New city","type":"ANOMALOUS_LOCATION","status":"ACTIVE","settings":{"maxEventsUsedForEvaluation":50,"granularity":"CITY"}}`))
+	}))
+	defer done()
+
+	rule := &BehaviorRule{Name: "New city", Type: BehaviorAnomalousLocation}
+	rule.Settings.MaxEventsUsedForEvaluation = 50
+	rule.Settings.Granularity = "CITY"
+	response, err := client.CreateBehaviorRule(rule)
+	if err != nil || response.Status != "ACTIVE" || response.Settings.Granularity != "CITY" {
+		t.Fatal("Expected the rule, got ", response, err)
+	}
+
+	data, _ := json.Marshal(created)
+	if expected := `{"name":"New city","settings":{"granularity":"CITY","maxEventsUsedForEvaluation":50},"type":"ANOMALOUS_LOCATION"}`; string(data) != expected {
+		t.Error("Expected ", expected, ", got ", string(data))
+	}
+}
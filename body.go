@@ -0,0 +1,52 @@
+package okta
+
+import (
+	"fmt"
+	"io"
+)
+
+// ResponseTooLargeError is returned for response bodies exceeding
+// Client.MaxResponseSize
+type ResponseTooLargeError struct {
+	Endpoint string
+	Limit    int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("Response of %s exceeds %d bytes", e.Endpoint, e.Limit)
+}
+
+// limitBody returns body failing once more than MaxResponseSize bytes are
+// read from it
+func (c *Client) limitBody(body io.Reader, endpoint string) io.Reader {
+	if c.MaxResponseSize <= 0 {
+		return body
+	}
+	return &limitedBody{body: body, remaining: c.MaxResponseSize, err: &ResponseTooLargeError{
+		Endpoint: endpoint,
+		Limit:    c.MaxResponseSize,
+	}}
+}
+
+type limitedBody struct {
+	body      io.Reader
+	remaining int64
+	err       error
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, l.err
+	}
+	// one byte past the limit is read to tell a body of exactly the limit
+	// from a larger one
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.body.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n - 1, l.err
+	}
+	return n, err
+}
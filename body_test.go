@@ -0,0 +1,33 @@
+package okta
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	page := `[{"id":"00g1"},{"id":"00g2"}]`
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/groups/00g1/users" {
+			w.Write([]byte(`[` + strings.Repeat(`{"id":"00u1"},`, 100) + `{"id":"00u2"}]`))
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer done()
+	client.MaxResponseSize = int64(len(page))
+
+	groups, err := client.Groups("00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*groups) != 2 {
+		t.Error("Expected a response of exactly the limit to be decoded, got ", *groups)
+	}
+
+	_, err = client.GroupMembers("00g1")
+	if e, ok := err.(*ResponseTooLargeError); !ok || e.Limit != int64(len(page)) {
+		t.Error("Expected ResponseTooLargeError, got ", err)
+	}
+}
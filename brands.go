@@ -0,0 +1,175 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+type Brand struct {
+	ID                         string `json:"id,omitempty"`
+	Name                       string `json:"name,omitempty"`
+	IsDefault                  bool   `json:"isDefault,omitempty"`
+	Locale                     string `json:"locale,omitempty"`
+	EmailDomainID              string `json:"emailDomainId,omitempty"`
+	CustomPrivacyPolicyURL     string `json:"customPrivacyPolicyUrl,omitempty"`
+	AgreeToCustomPrivacyPolicy bool   `json:"agreeToCustomPrivacyPolicy,omitempty"`
+	RemovePoweredByOkta        bool   `json:"removePoweredByOkta"`
+}
+
+// Theme holds the colors, images and page variants of a brand, the images
+// are read only and uploaded separately
+type Theme struct {
+	ID                                string `json:"id,omitempty"`
+	Logo                              string `json:"logo,omitempty"`
+	Favicon                           string `json:"favicon,omitempty"`
+	BackgroundImage                   string `json:"backgroundImage,omitempty"`
+	PrimaryColorHex                   string `json:"primaryColorHex,omitempty"`
+	PrimaryColorContrastHex           string `json:"primaryColorContrastHex,omitempty"`
+	SecondaryColorHex                 string `json:"secondaryColorHex,omitempty"`
+	SecondaryColorContrastHex         string `json:"secondaryColorContrastHex,omitempty"`
+	SignInPageTouchPointVariant       string `json:"signInPageTouchPointVariant,omitempty"`
+	EndUserDashboardTouchPointVariant string `json:"endUserDashboardTouchPointVariant,omitempty"`
+	ErrorPageTouchPointVariant        string `json:"errorPageTouchPointVariant,omitempty"`
+	EmailTemplateTouchPointVariant    string `json:"emailTemplateTouchPointVariant,omitempty"`
+	LoadingPageTouchPointVariant      string `json:"loadingPageTouchPointVariant,omitempty"`
+}
+
+// EmailTemplate is an email Okta sends, e.g. UserActivation
+type EmailTemplate struct {
+	Name string `json:"name"`
+}
+
+// EmailCustomization is a translation of an email template, the default
+// one is used for languages without a customization
+type EmailCustomization struct {
+	ID          string     `json:"id,omitempty"`
+	Language    string     `json:"language"`
+	IsDefault   bool       `json:"isDefault,omitempty"`
+	Subject     string     `json:"subject"`
+	Body        string     `json:"body"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+// SmsTemplate is the text of an SMS, Translations are keyed by language
+type SmsTemplate struct {
+	ID           string            `json:"id,omitempty"`
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Template     string            `json:"template"`
+	Translations map[string]string `json:"translations,omitempty"`
+	Created      *time.Time        `json:"created,omitempty"`
+	LastUpdated  *time.Time        `json:"lastUpdated,omitempty"`
+}
+
+// Brands returns the brands of the org
+func (c *Client) Brands() (*[]Brand, error) {
+	var response = &[]Brand{}
+	err := c.listAll("brands", response)
+	return response, err
+}
+
+// Brand takes a brand id and returns the brand
+func (c *Client) Brand(brandID string) (*Brand, error) {
+	var response = &Brand{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID), "GET", nil, response)
+	return response, err
+}
+
+// UpdateBrand replaces the settings of a brand
+func (c *Client) UpdateBrand(brandID string, brand *Brand) (*Brand, error) {
+	var response = &Brand{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID), "PUT", brand, response)
+	return response, err
+}
+
+// Themes returns the themes of a brand
+func (c *Client) Themes(brandID string) (*[]Theme, error) {
+	var response = &[]Theme{}
+	err := c.listAll("brands/"+url.PathEscape(brandID)+"/themes", response)
+	return response, err
+}
+
+// Theme returns a theme of a brand
+func (c *Client) Theme(brandID, themeID string) (*Theme, error) {
+	var response = &Theme{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/themes/"+url.PathEscape(themeID), "GET", nil, response)
+	return response, err
+}
+
+// UpdateTheme replaces the colors and page variants of a theme
+func (c *Client) UpdateTheme(brandID, themeID string, theme *Theme) (*Theme, error) {
+	var response = &Theme{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/themes/"+url.PathEscape(themeID), "PUT", theme, response)
+	return response, err
+}
+
+// EmailTemplates returns the email templates of a brand
+func (c *Client) EmailTemplates(brandID string) (*[]EmailTemplate, error) {
+	var response = &[]EmailTemplate{}
+	err := c.listAll("brands/"+url.PathEscape(brandID)+"/templates/email", response)
+	return response, err
+}
+
+// EmailTemplateDefaultContent returns the content Okta sends when a
+// template isn't customized
+func (c *Client) EmailTemplateDefaultContent(brandID, templateName string) (*EmailCustomization, error) {
+	var response = &EmailCustomization{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/templates/email/"+url.PathEscape(templateName)+"/default-content", "GET", nil, response)
+	return response, err
+}
+
+// EmailCustomizations returns the customizations of an email template
+func (c *Client) EmailCustomizations(brandID, templateName string) (*[]EmailCustomization, error) {
+	var response = &[]EmailCustomization{}
+	err := c.listAll("brands/"+url.PathEscape(brandID)+"/templates/email/"+url.PathEscape(templateName)+"/customizations", response)
+	return response, err
+}
+
+// CreateEmailCustomization adds a customization for a language
+func (c *Client) CreateEmailCustomization(brandID, templateName string, customization *EmailCustomization) (*EmailCustomization, error) {
+	var response = &EmailCustomization{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/templates/email/"+url.PathEscape(templateName)+"/customizations", "POST", customization, response)
+	return response, err
+}
+
+// UpdateEmailCustomization replaces a customization
+func (c *Client) UpdateEmailCustomization(brandID, templateName, customizationID string, customization *EmailCustomization) (*EmailCustomization, error) {
+	var response = &EmailCustomization{}
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/templates/email/"+url.PathEscape(templateName)+"/customizations/"+url.PathEscape(customizationID), "PUT", customization, response)
+	return response, err
+}
+
+// DeleteEmailCustomization removes a customization, the default one can
+// only be removed together with all others
+func (c *Client) DeleteEmailCustomization(brandID, templateName, customizationID string) error {
+	err, _ := c.call("brands/"+url.PathEscape(brandID)+"/templates/email/"+url.PathEscape(templateName)+"/customizations/"+url.PathEscape(customizationID), "DELETE", nil, nil)
+	return err
+}
+
+// SmsTemplates returns the custom SMS templates of the org
+func (c *Client) SmsTemplates() (*[]SmsTemplate, error) {
+	var response = &[]SmsTemplate{}
+	err := c.listAll("templates/sms", response)
+	return response, err
+}
+
+// CreateSmsTemplate adds a custom SMS template, Type is SMS_VERIFY_CODE
+func (c *Client) CreateSmsTemplate(template *SmsTemplate) (*SmsTemplate, error) {
+	var response = &SmsTemplate{}
+	err, _ := c.call("templates/sms", "POST", template, response)
+	return response, err
+}
+
+// UpdateSmsTemplate replaces a custom SMS template
+func (c *Client) UpdateSmsTemplate(templateID string, template *SmsTemplate) (*SmsTemplate, error) {
+	var response = &SmsTemplate{}
+	err, _ := c.call("templates/sms/"+url.PathEscape(templateID), "PUT", template, response)
+	return response, err
+}
+
+// DeleteSmsTemplate removes a custom SMS template
+func (c *Client) DeleteSmsTemplate(templateID string) error {
+	err, _ := c.call("templates/sms/"+url.PathEscape(templateID), "DELETE", nil, nil)
+	return err
+}
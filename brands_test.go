@@ -0,0 +1,139 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestBrands(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/brands":
+			w.Write([]byte(`[{"id":"bnd1","name":"Example","isDefault":true,"removePoweredByOkta":true}]`))
+		case "/api/v1/brands/bnd1/themes":
+			w.Write([]byte(`[{"id":"thm1","primaryColorHex":"#1662dd","signInPageTouchPointVariant":"OKTA_DEFAULT"}]`))
+		default:
+			echoHandler(`{"id":"bnd1","customPrivacyPolicyUrl":"https://example.com/privacy","primaryColorHex":"#1662dd"}`)(w, r)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	brands, err := client.Brands()
+	if err != nil || len(*brands) != 1 || !(*brands)[0].IsDefault || !(*brands)[0].RemovePoweredByOkta {
+		t.Fatal("Expected the default brand, got ", brands, err)
+	}
+	brand, err := client.Brand("bnd1")
+	if err != nil || brand.CustomPrivacyPolicyURL != "https://example.com/privacy" {
+		t.Fatal("Expected the brand, got ", brand, err)
+	}
+	brand.AgreeToCustomPrivacyPolicy = true
+	if updated, err := client.UpdateBrand("bnd1", brand); err != nil || !updated.AgreeToCustomPrivacyPolicy {
+		t.Fatal("Expected the updated brand, got ", updated, err)
+	}
+
+	themes, err := client.Themes("bnd1")
+	if err != nil || len(*themes) != 1 || (*themes)[0].SignInPageTouchPointVariant != "OKTA_DEFAULT" {
+		t.Fatal("Expected the theme, got ", themes, err)
+	}
+	theme, err := client.Theme("bnd1", "thm1")
+	if err != nil || theme.PrimaryColorHex != "#1662dd" {
+		t.Fatal("Expected the theme, got ", theme, err)
+	}
+	theme.SecondaryColorHex = "#ebebed"
+	if updated, err := client.UpdateTheme("bnd1", "thm1", theme); err != nil || updated.SecondaryColorHex != "#ebebed" {
+		t.Fatal("Expected the updated theme, got ", updated, err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/brands",
+		"GET /api/v1/brands/bnd1",
+		"PUT /api/v1/brands/bnd1",
+		"GET /api/v1/brands/bnd1/themes",
+		"GET /api/v1/brands/bnd1/themes/thm1",
+		"PUT /api/v1/brands/bnd1/themes/thm1",
+	)
+}
+
+func TestEmailCustomizations(t *testing.T) {
+	var created EmailCustomization
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/brands/bnd1/templates/email":
+			w.Write([]byte(`[{"name":"UserActivation"},{"name":"ForgotPassword"}]`))
+		case r.URL.Path == "/api/v1/brands/bnd1/templates/email/UserActivation/customizations" && r.Method == "GET":
+			w.Write([]byte(`[{"id":"oel1","language":"en","isDefault":true,"subject":"Welcome","body":"<html>${activationLink}</html>"}]`))
+		case r.Method == "POST":
+			json.NewDecoder(r.Body).Decode(&created)
+			created.ID = "oel2"
+			json.NewEncoder(w).Encode(created)
+		default:
+			echoHandler(`{"language":"en","subject":"Welcome to ${org.name}","body":"<html>${activationLink}</html>"}`)(w, r)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	templates, err := client.EmailTemplates("bnd1")
+	if err != nil || len(*templates) != 2 || (*templates)[0].Name != "UserActivation" {
+		t.Fatal("Expected the email templates, got ", templates, err)
+	}
+	content, err := client.EmailTemplateDefaultContent("bnd1", "UserActivation")
+	if err != nil || content.Subject != "Welcome to ${org.name}" {
+		t.Fatal("Expected the default content, got ", content, err)
+	}
+	customizations, err := client.EmailCustomizations("bnd1", "UserActivation")
+	if err != nil || len(*customizations) != 1 || !(*customizations)[0].IsDefault {
+		t.Fatal("Expected the default customization, got ", customizations, err)
+	}
+	customization, err := client.CreateEmailCustomization("bnd1", "UserActivation", &EmailCustomization{Language: "fr", Subject: "Bienvenue", Body: "<html>${activationLink}</html>"})
+	if err != nil || customization.ID != "oel2" || created.Language != "fr" {
+		t.Fatal("Expected the created customization, got ", customization, err)
+	}
+	customization.Subject = "Bienvenue chez ${org.name}"
+	if updated, err := client.UpdateEmailCustomization("bnd1", "UserActivation", "oel2", customization); err != nil || updated.Subject != customization.Subject {
+		t.Fatal("Expected the updated customization, got ", updated, err)
+	}
+	if err := client.DeleteEmailCustomization("bnd1", "UserActivation", "oel2"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/brands/bnd1/templates/email",
+		"GET /api/v1/brands/bnd1/templates/email/UserActivation/default-content",
+		"GET /api/v1/brands/bnd1/templates/email/UserActivation/customizations",
+		"POST /api/v1/brands/bnd1/templates/email/UserActivation/customizations",
+		"PUT /api/v1/brands/bnd1/templates/email/UserActivation/customizations/oel2",
+		"DELETE /api/v1/brands/bnd1/templates/email/UserActivation/customizations/oel2",
+	)
+}
+
+func TestSmsTemplates(t *testing.T) {
+	recorder := recordRequests(echoHandler(`[{"id":"cstk2flOtuCMDJK4b0g3","name":"Custom","type":"SMS_VERIFY_CODE","template":"Your ${org.name} code is ${code}","translations":{"fr":"Votre code ${org.name} est ${code}"}}]`))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	templates, err := client.SmsTemplates()
+	if err != nil || len(*templates) != 1 || (*templates)[0].Translations["fr"] == "" {
+		t.Fatal("Expected the SMS template, got ", templates, err)
+	}
+	template := &(*templates)[0]
+	if created, err := client.CreateSmsTemplate(template); err != nil || created.Type != "SMS_VERIFY_CODE" {
+		t.Fatal("Expected the created template, got ", created, err)
+	}
+	template.Template = "${code} is your ${org.name} code"
+	if updated, err := client.UpdateSmsTemplate(template.ID, template); err != nil || updated.Template != template.Template {
+		t.Fatal("Expected the updated template, got ", updated, err)
+	}
+	if err := client.DeleteSmsTemplate(template.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/templates/sms",
+		"POST /api/v1/templates/sms",
+		"PUT /api/v1/templates/sms/cstk2flOtuCMDJK4b0g3",
+		"DELETE /api/v1/templates/sms/cstk2flOtuCMDJK4b0g3",
+	)
+}
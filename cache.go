@@ -0,0 +1,142 @@
+package okta
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+)
+
+// Cache stores responses for revalidation, implementations must be safe for
+// concurrent use
+type Cache interface {
+	// Get returns the response stored for key or nil
+	Get(key string) *CachedResponse
+	Set(key string, response *CachedResponse)
+	Delete(key string)
+}
+
+// CachedResponse is a response body with its validators
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	// Link holds the Link headers so that paging works on revalidated pages
+	Link []string
+	Body []byte
+}
+
+// conditions returns header with the validators of the response added
+func (r *CachedResponse) conditions(header http.Header) http.Header {
+	conditional := http.Header{}
+	for key, values := range header {
+		conditional[key] = values
+	}
+	if r.ETag != "" {
+		conditional.Set("If-None-Match", r.ETag)
+	}
+	if r.LastModified != "" {
+		conditional.Set("If-Modified-Since", r.LastModified)
+	}
+	return conditional
+}
+
+// cacheKey identifies a URL as seen with the credentials of the client,
+// a Cache may be shared by clients of different users
+func (c *Client) cacheKey(url string) string {
+	credentials := c.ApiToken + "\x00" + c.AccessToken
+	if cookie := c.sessionCookie(); cookie != nil {
+		credentials += "\x00" + cookie.Value
+	}
+	sum := sha256.Sum256([]byte(credentials))
+	return url + " " + hex.EncodeToString(sum[:8])
+}
+
+// revalidate stores or revalidates a GET response and invalidates the URL when
+// it was modified, it returns the body to decode
+func (c *Client) revalidate(method, url string, resp *http.Response, body []byte, cached *CachedResponse) []byte {
+	key := c.cacheKey(url)
+	switch {
+	case method != "GET":
+		if resp.StatusCode < 300 {
+			c.Cache.Delete(key)
+		}
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.StatusCode = http.StatusOK
+		resp.Header["Link"] = cached.Link
+		return cached.Body
+	case resp.StatusCode == http.StatusOK:
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return body
+		}
+		c.Cache.Set(key, &CachedResponse{
+			ETag:         etag,
+			LastModified: lastModified,
+			Link:         resp.Header.Values("Link"),
+			Body:         body,
+		})
+	}
+	return body
+}
+
+// LRUCache is an in-memory Cache holding a limited number of responses,
+// the least recently used are dropped first
+type LRUCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewLRUCache returns a cache holding up to size responses
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (l *LRUCache) Get(key string) *CachedResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruEntry).response
+}
+
+func (l *LRUCache) Set(key string, response *CachedResponse) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruEntry).response = response
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, response: response})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *LRUCache) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
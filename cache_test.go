@@ -0,0 +1,60 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCacheRevalidation(t *testing.T) {
+	requests, notModified := 0, 0
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method == "GET" && r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id":"00u1","status":"ACTIVE"}`))
+	}))
+	defer done()
+	client.Cache = NewLRUCache(10)
+
+	for i := 0; i < 2; i++ {
+		user, err := client.User("00u1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Status != "ACTIVE" {
+			t.Error("Expected the cached user on a 304, got ", user.Status)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Error("Expected the second request to be revalidated, got ", requests, notModified)
+	}
+
+	if _, err := client.UpdateUserProfilePartial("00u1", map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.User("00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if notModified != 1 {
+		t.Error("Expected an update to invalidate the cached user")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", &CachedResponse{ETag: "a"})
+	cache.Set("b", &CachedResponse{ETag: "b"})
+	cache.Get("a")
+	cache.Set("c", &CachedResponse{ETag: "c"})
+
+	if cache.Get("b") != nil {
+		t.Error("Expected the least recently used entry to be dropped")
+	}
+	if cache.Get("a") == nil || cache.Get("c") == nil {
+		t.Error("Expected recently used entries to be kept")
+	}
+}
@@ -0,0 +1,156 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type callOptionsKey struct{}
+
+// CallOption changes how the calls made with a context are sent, see
+// WithCallOptions
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout       time.Duration
+	expand        []string
+	meta          *ResponseMeta
+	correlationID string
+	hedge         *HedgePolicy
+}
+
+// ResponseMeta is the metadata of a response, see WithResponseMeta
+type ResponseMeta struct {
+	StatusCode int
+	// RequestID is the X-Okta-Request-Id to quote to Okta support
+	RequestID string
+	Header    http.Header
+	// Links are the targets of the Link headers by relation, e.g. self and
+	// next
+	Links map[string]string
+	// NextPage is the after cursor of the next page to pass as
+	// ListOptions.After, empty on the last page
+	NextPage string
+	// RateLimit is the rate limit of the bucket of the endpoint, zero when
+	// the response had no X-Rate-Limit headers
+	RateLimit RateLimit
+}
+
+func newResponseMeta(method, endpoint string, resp *http.Response) ResponseMeta {
+	meta := ResponseMeta{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Okta-Request-Id"),
+		Header:     resp.Header,
+		Links:      headerLinks(resp.Header),
+	}
+	if next, err := url.Parse(meta.Links["next"]); err == nil {
+		meta.NextPage = next.Query().Get("after")
+	}
+	if limit, ok := parseRateLimit(resp.Header); ok {
+		limit.Bucket = method + " " + endpointTemplate(endpoint)
+		meta.RateLimit = limit
+	}
+	return meta
+}
+
+// WithTimeout bounds every call made with the context to d, retries and
+// reading the response included. It replaces Client.RequestTimeout.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithExpand requests embedded resources with the GET calls made with the
+// context, e.g. "blocks" for users, "app" or "stats" for groups and "user"
+// for app users. They are decoded into the Embedded field of the results.
+// Like other enrichment they are skipped by list calls in degradation mode.
+func WithExpand(embeds ...string) CallOption {
+	return func(o *callOptions) {
+		o.expand = embeds
+	}
+}
+
+// WithResponseMeta stores the metadata of the last response to a call made
+// with the context in meta, which must not be shared by concurrent calls.
+// For list calls reading several pages it is the one of the last page.
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(o *callOptions) {
+		o.meta = meta
+	}
+}
+
+// WithCorrelationID sends id with the calls made with the context in the
+// Client.CorrelationHeader header, so that requests can be matched with the
+// logs of the caller
+func WithCorrelationID(id string) CallOption {
+	return func(o *callOptions) {
+		o.correlationID = id
+	}
+}
+
+// WithCallOptions returns a context applying opts to the calls of the
+// Context variants made with it, e.g. a short deadline for AuthenticateContext
+// and a long one for ListLogsContext. Options of a parent context are kept
+// unless overridden.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	options := callOptions{}
+	if parent, ok := ctx.Value(callOptionsKey{}).(callOptions); ok {
+		options = parent
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, options)
+}
+
+// withTimeout returns ctx bounded by the timeout of its call options or
+// else RequestTimeout
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.RequestTimeout
+	if options := callOptionsOf(ctx); options.timeout > 0 {
+		timeout = options.timeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func callOptionsOf(ctx context.Context) callOptions {
+	options, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return options
+}
+
+// withExpand adds the embeds requested with WithExpand to a GET endpoint
+// that doesn't set its own
+func withExpand(ctx context.Context, endpoint string) string {
+	expand := callOptionsOf(ctx).expand
+	if len(expand) == 0 {
+		return endpoint
+	}
+
+	path, rawQuery := endpoint, ""
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		path, rawQuery = endpoint[:i], endpoint[i+1:]
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil || query.Get("expand") != "" {
+		return endpoint
+	}
+	query.Set("expand", strings.Join(expand, ","))
+	return path + "?" + query.Encode()
+}
+
+// withoutExpand returns ctx no longer requesting embeds
+func withoutExpand(ctx context.Context) context.Context {
+	options, ok := ctx.Value(callOptionsKey{}).(callOptions)
+	if !ok || len(options.expand) == 0 {
+		return ctx
+	}
+	options.expand = nil
+	return context.WithValue(ctx, callOptionsKey{}, options)
+}
@@ -0,0 +1,109 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCallTimeouts(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer done()
+	client.RequestTimeout = 20 * time.Millisecond
+
+	started := time.Now()
+	if _, err := client.Authenticate("username", "password"); err == nil {
+		t.Fatal("Expected RequestTimeout to end the call")
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Error("Expected the call to end after RequestTimeout, took ", elapsed)
+	}
+
+	ctx := WithCallOptions(context.Background(), WithTimeout(200*time.Millisecond))
+	if _, err := client.ListLogsContext(ctx, nil); err != nil {
+		t.Error("Expected the call option to replace RequestTimeout, got ", err)
+	}
+}
+
+func TestWithExpand(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Path == "/api/v1/users/00u1" {
+			w.Write([]byte(`{"id":"00u1","_embedded":{"blocks":[{"type":"DEVICE_BASED","appliesTo":"ANY_DEVICES"}]}}`))
+			return
+		}
+		w.Write([]byte(`[{"id":"00u1","_embedded":{"user":{"id":"00u1","profile":{"login":"jane@example.com"}}}}]`))
+	}))
+	defer done()
+	ctx := WithCallOptions(context.Background(), WithExpand("blocks"))
+
+	user, err := client.UserContext(ctx, "00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(user.Embedded.Blocks) != 1 || user.Embedded.Blocks[0].Type != "DEVICE_BASED" {
+		t.Error("Expected the embedded blocks, got ", user.Embedded.Blocks)
+	}
+
+	ctx = WithCallOptions(context.Background(), WithExpand("user"))
+	assignments, err := client.AppUsersContext(ctx, "0oa1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embedded := (*assignments)[0].Embedded; embedded == nil || embedded.User.Profile.Login != "jane@example.com" {
+		t.Error("Expected the embedded user, got ", embedded)
+	}
+
+	client.Budget = &ErrorBudget{degradedUntil: time.Now().Add(time.Minute)}
+	if _, err := client.AppUsersContext(ctx, "0oa1"); !IsPartialResult(err) {
+		t.Error("Expected embeds to be skipped in degradation mode, got ", err)
+	}
+
+	expected := []string{"expand=blocks", "expand=user&limit=500", "limit=500"}
+	if len(queries) != 3 || queries[0] != expected[0] || queries[1] != expected[1] || queries[2] != expected[2] {
+		t.Error("Expected queries ", expected, ", got ", queries)
+	}
+}
+
+func TestResponseMeta(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Okta-Request-Id", "XkbvTKzLB0ZmjPJdhqGAHQAABqA")
+		w.Header().Set("X-Rate-Limit-Limit", "600")
+		w.Header().Set("X-Rate-Limit-Remaining", "599")
+		w.Header().Set("X-Rate-Limit-Reset", "1700000000")
+		w.Header().Add("Link", `<https://`+r.Host+`/api/v1/logs?limit=2>; rel="self"`)
+		w.Header().Add("Link", `<https://`+r.Host+`/api/v1/logs?after=1700000000000_1&limit=2>; rel="next"`)
+		w.Write([]byte(`[]`))
+	}))
+	defer done()
+
+	var observed []ResponseMeta
+	client.OnResponse = func(meta ResponseMeta) { observed = append(observed, meta) }
+
+	meta := &ResponseMeta{}
+	ctx := WithCallOptions(context.Background(), WithResponseMeta(meta))
+	if _, err := client.ListLogsContext(ctx, &ListLogsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if meta.NextPage != "1700000000000_1" || meta.Links["self"] == "" {
+		t.Errorf("Expected the pagination links, got %+v", meta)
+	}
+	if meta.RateLimit.Bucket != "GET logs" || meta.RateLimit.Remaining != 599 {
+		t.Errorf("Expected the rate limit, got %+v", meta.RateLimit)
+	}
+
+	if _, err := client.Logs(time.Time{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(observed) != 2 || observed[1].RequestID != "XkbvTKzLB0ZmjPJdhqGAHQAABqA" {
+		t.Error("Expected the metadata of both calls, got ", observed)
+	}
+}
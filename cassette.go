@@ -0,0 +1,229 @@
+package okta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// CassetteMode is whether a Cassette records or replays
+type CassetteMode int
+
+const (
+	// CassetteAuto replays the cassette file if it exists and records it
+	// otherwise
+	CassetteAuto CassetteMode = iota
+	// CassetteReplay only replays, requests without a recorded interaction
+	// fail, e.g. in CI
+	CassetteReplay
+	// CassetteRecord sends every request and records it, replacing the
+	// cassette file on Save
+	CassetteRecord
+)
+
+// Interaction is a request and its response as recorded in a cassette,
+// with passwords, tokens and cookies redacted
+type Interaction struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+		Body   string      `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// Cassette is a RoundTripper recording the calls of a client to a file and
+// replaying them, so tests of code using the client run without an org
+// or its credentials. Secrets are redacted before anything is written,
+// the secrets of a replayed response are REDACTED as well. Interactions
+// match on method, path, query and body but not on the org: a cassette
+// recorded against one org replays for any other. Every interaction is
+// replayed once in the order recorded, so a call repeated returns the
+// responses of its recording in turn. It is safe for concurrent use.
+//
+//	cassette, err := okta.NewCassette("testdata/cassettes/users.json", okta.CassetteAuto)
+//	client.SetHTTPClient(&http.Client{Transport: cassette})
+//	defer cassette.Save()
+type Cassette struct {
+	// Transport sends the requests being recorded, the transport of
+	// NewClient when nil
+	Transport http.RoundTripper
+
+	path         string
+	mode         CassetteMode
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewCassette returns the cassette of the file at path, which is read
+// unless recording
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode}
+	if mode == CassetteAuto {
+		c.mode = CassetteRecord
+		if _, err := os.Stat(path); err == nil {
+			c.mode = CassetteReplay
+		}
+	}
+	if c.mode == CassetteRecord {
+		return c, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("okta: invalid cassette %s: %v", path, err)
+	}
+	c.replayed = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Recording reports whether the cassette sends the requests rather than
+// replaying them
+func (c *Cassette) Recording() bool {
+	return c.mode == CassetteRecord
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		clone := *req
+		clone.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req = &clone
+	}
+
+	var recorded Interaction
+	recorded.Request.Method = req.Method
+	recorded.Request.URL = redactURL(req.URL.String())
+	recorded.Request.Header = redactHeader(req.Header)
+	recorded.Request.Body = string(redactBody(req.Header.Get("Content-Type"), body))
+
+	if !c.Recording() {
+		return c.replay(req, &recorded)
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = defaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	recorded.Response.StatusCode = resp.StatusCode
+	recorded.Response.Header = redactHeader(resp.Header)
+	recorded.Response.Header.Del("Content-Length")
+	recorded.Response.Body = string(redactBody(resp.Header.Get("Content-Type"), data))
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, recorded)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// replay returns the response of the first interaction not replayed yet
+// matching the request
+func (c *Cassette) replay(req *http.Request, recorded *Interaction) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.replayed[i] || !cassetteMatch(&interaction, recorded) {
+			continue
+		}
+		c.replayed[i] = true
+
+		body := interaction.Response.Body
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("okta: no interaction of cassette %s left for %s %s", c.path, recorded.Request.Method, recorded.Request.URL)
+}
+
+// Save writes the interactions recorded to the cassette file, creating its
+// directory. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if !c.Recording() {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, append(data, '\n'), 0644)
+}
+
+// cassetteMatch compares the method, path, query and body of the requests
+// of two interactions, both redacted
+func cassetteMatch(a, b *Interaction) bool {
+	if a.Request.Method != b.Request.Method || a.Request.Body != b.Request.Body {
+		return false
+	}
+	ua, err := url.Parse(a.Request.URL)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b.Request.URL)
+	if err != nil {
+		return false
+	}
+	return ua.Path == ub.Path && ua.Query().Encode() == ub.Query().Encode()
+}
+
+// redactHeader returns a copy of header with the secret headers redacted
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := http.Header{}
+	for name, values := range header {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			values = []string{redacted}
+		}
+		redactedHeader[name] = append([]string(nil), values...)
+	}
+	return redactedHeader
+}
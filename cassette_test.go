@@ -0,0 +1,106 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	dir, err := ioutil.TempDir("", "okta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cassettes", "authn.json")
+	calls := 0
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/api/v1/authn":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Set-Cookie", "DT=secret")
+			w.Write([]byte(`{"status":"SUCCESS","sessionToken":"20111secret"}`))
+		case "/api/v1/users/00u1":
+			w.Header().Set("Content-Type", "application/json")
+			if calls == 2 {
+				w.Write([]byte(`{"id":"00u1","status":"ACTIVE"}`))
+			} else {
+				w.Write([]byte(`{"id":"00u1","status":"SUSPENDED"}`))
+			}
+		}
+	}))
+	defer done()
+	client.ApiToken = "00secret"
+
+	recorder, err := NewCassette(path, CassetteAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("Expected to record without a cassette file")
+	}
+	recorder.Transport = client.client.Transport
+	client.SetHTTPClient(&http.Client{Transport: recorder})
+
+	if _, err := client.Authenticate("jane", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range []UserStatus{UserActive, UserSuspended} {
+		if user, err := client.User("00u1"); err != nil || user.Status != status {
+			t.Fatal("Expected ", status, ", got ", user, err)
+		}
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "20111secret", "00secret", "DT=secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Error("Expected ", secret, " to be redacted from ", string(data))
+		}
+	}
+
+	// replaying against another org without any server
+	replayer, err := NewCassette(path, CassetteAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayer.Recording() {
+		t.Fatal("Expected to replay the cassette file")
+	}
+	other := NewClient("other")
+	other.ApiToken = "00other"
+	other.SetHTTPClient(&http.Client{Transport: replayer})
+
+	authn, err := other.Authenticate("jane", "another password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authn.Status != AuthnSuccess || authn.SessionToken != redacted {
+		t.Error("Unexpected replay ", authn.Status, authn.SessionToken)
+	}
+	for _, status := range []UserStatus{UserActive, UserSuspended} {
+		if user, err := other.User("00u1"); err != nil || user.Status != status {
+			t.Fatal("Expected ", status, " replayed, got ", user, err)
+		}
+	}
+	if calls != 3 {
+		t.Error("Expected the replay to send no request, got ", calls)
+	}
+
+	if _, err := other.User("00u1"); err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Error("Expected the interactions to be used up, got ", err)
+	}
+	if _, err := NewCassette(filepath.Join(dir, "missing.json"), CassetteReplay); err == nil {
+		t.Error("Expected an error replaying a missing cassette")
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+func newFlagSet(env *environment, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	return flags
+}
+
+// listUsers prints the id, status and login of the users, one per line
+func listUsers(c *okta.Client, env *environment, args []string) error {
+	flags := newFlagSet(env, "users")
+	opts := &okta.ListUsersOptions{}
+	flags.StringVar(&opts.Q, "q", "", "match the start of the login, email and names")
+	flags.StringVar(&opts.Filter, "filter", "", `filter expression, e.g. status eq "ACTIVE"`)
+	flags.StringVar(&opts.Search, "search", "", "search expression")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	users, err := c.ListUsers(opts)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	for _, user := range *users {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", user.ID, user.Status, user.Profile.Login)
+	}
+	return w.Flush()
+}
+
+// getUser prints a user as indented JSON
+func getUser(c *okta.Client, env *environment, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: okta user <id or login>")
+	}
+
+	user, err := c.User(args[0])
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(env.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(user)
+}
+
+// addToGroup adds a user to a group given by name or id
+func addToGroup(c *okta.Client, env *environment, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: okta add-to-group <group name or id> <user id or login>")
+	}
+
+	group, err := c.GroupByName(args[0])
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		if group, err = c.Group(args[0]); err != nil {
+			return fmt.Errorf("no group %q: %v", args[0], err)
+		}
+	}
+	user, err := c.User(args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := c.AddUserToGroup(group.ID, user.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.stdout, "added %s to %s\n", user.Profile.Login, group.Profile.Name)
+	return nil
+}
+
+// tailLogs prints System Log events, following new ones with -f until
+// interrupted
+func tailLogs(c *okta.Client, env *environment, args []string) error {
+	flags := newFlagSet(env, "logs")
+	since := flags.Duration("since", 15*time.Minute, "print the events of this long ago onwards")
+	filter := flags.String("filter", "", `filter expression, e.g. eventType eq "user.session.start"`)
+	follow := flags.Bool("f", false, "keep printing new events")
+	interval := flags.Duration("interval", 10*time.Second, "how often to poll with -f")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	from := time.Now().Add(-*since)
+	// since is inclusive, the events already printed at the newest
+	// timestamp are skipped, see Client.Watch
+	seen := map[string]bool{}
+	for {
+		events, err := c.Logs(from, *filter)
+		if err != nil {
+			return err
+		}
+
+		fresh := 0
+		for _, event := range *events {
+			if seen[event.UUID] {
+				continue
+			}
+			if event.Published != nil && event.Published.After(from) {
+				from = *event.Published
+				seen = map[string]bool{}
+			}
+			seen[event.UUID] = true
+			fresh++
+			printEvent(w, event)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		switch {
+		case fresh > 0:
+			// read the rest of the backlog
+		case !*follow:
+			return nil
+		default:
+			time.Sleep(*interval)
+		}
+	}
+}
+
+func printEvent(w *tabwriter.Writer, event okta.LogEvent) {
+	published := ""
+	if event.Published != nil {
+		published = event.Published.Local().Format(time.RFC3339)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", published, event.EventType, event.Outcome.Result,
+		event.Actor.AlternateID, event.DisplayMessage)
+}
+
+// authenticate signs a user in, completing push or passcode MFA, and
+// prints the session token
+func authenticate(c *okta.Client, env *environment, args []string) error {
+	flags := newFlagSet(env, "authn")
+	factorType := flags.String("factor", "", "factor type to verify, e.g. push or token:software:totp, the first supported when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: okta authn [-factor type] <username>")
+	}
+
+	password := env.getenv("OKTA_PASSWORD")
+	if password == "" {
+		var err error
+		if password, err = prompt(env, "Password: "); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.Authenticate(flags.Arg(0), password)
+	for err == nil && !resp.Done() {
+		switch {
+		case resp.Status == okta.AuthnMFARequired:
+			resp, err = verifyFactor(env, resp, *factorType)
+		case resp.CanSkip():
+			resp, err = resp.Skip()
+		default:
+			return fmt.Errorf("transaction is %s, it has to be completed in the browser", resp.Status)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if resp.Status != okta.AuthnSuccess {
+		return fmt.Errorf("transaction is %s", resp.Status)
+	}
+	fmt.Fprintln(env.stdout, resp.SessionToken)
+	return nil
+}
+
+func verifyFactor(env *environment, resp *okta.AuthnResponse, factorType string) (*okta.AuthnResponse, error) {
+	var factor *okta.Factor
+	for _, supported := range resp.GetSupportedFactors() {
+		if factorType == "" || string(supported.FactorType) == factorType {
+			factor = &supported
+			break
+		}
+	}
+	if factor == nil {
+		return nil, fmt.Errorf("no supported factor enrolled, push and passcodes are")
+	}
+
+	switch {
+	case factor.FactorType == okta.FactorPush:
+		fmt.Fprintln(env.stderr, "Waiting for the push to be approved...")
+		verification, err := resp.VerifyPush("okta-cli/"+okta.Version, 2*time.Second, time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		if verification.Result != okta.PushApproved {
+			return nil, fmt.Errorf("push %s", strings.ToLower(string(verification.Result)))
+		}
+		return verification.Response, nil
+	case factor.FactorType.IsToken():
+		code, err := prompt(env, "Passcode: ")
+		if err != nil {
+			return nil, err
+		}
+		return factor.VerifyOTP(resp.StateToken, code)
+	}
+	return nil, fmt.Errorf("%s factors aren't supported, use -factor push or a passcode factor", factor.FactorType)
+}
+
+// prompt asks for a line of standard input
+func prompt(env *environment, label string) (string, error) {
+	fmt.Fprint(env.stderr, label)
+	line, err := env.stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading standard input: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,112 @@
+// Command okta calls the Okta API of an org from the shell:
+//
+//	okta users [-q prefix] [-filter expr] [-search expr]
+//	okta user <id or login>
+//	okta add-to-group <group name or id> <user id or login>
+//	okta logs [-since 15m] [-filter expr] [-f]
+//	okta authn [-factor type] <username>
+//
+// The org is read from OKTA_ORG, or OKTA_BASE_URL for custom domains, and
+// the API token from OKTA_API_TOKEN. Without them the okta.yaml files and
+// variables of the official SDKs are used, see okta.LoadConfig. authn
+// reads the password from OKTA_PASSWORD or the first line of standard
+// input.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+const usage = `usage: okta <command> [flags] [args]
+
+commands:
+  users          list users, -q, -filter and -search narrow the list
+  user           print a user as JSON
+  add-to-group   add a user to a group
+  logs           print System Log events, -f keeps following
+  authn          sign in, completing push or passcode MFA, and print the
+                 session token
+
+environment:
+  OKTA_ORG, OKTA_BASE_URL, OKTA_API_TOKEN, OKTA_PASSWORD, or the
+  ~/.okta/okta.yaml and OKTA_CLIENT_* settings of the Okta SDKs,
+  OKTA_DEBUG=1 prints the requests with their secrets redacted
+`
+
+// command runs a subcommand with its arguments
+type command func(c *okta.Client, env *environment, args []string) error
+
+var commands = map[string]command{
+	"users":        listUsers,
+	"user":         getUser,
+	"add-to-group": addToGroup,
+	"logs":         tailLogs,
+	"authn":        authenticate,
+}
+
+// environment is where commands read input and write output
+type environment struct {
+	stdin  *bufio.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	env := &environment{
+		stdin:  bufio.NewReader(os.Stdin),
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	if err := run(env, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "okta:", err)
+		os.Exit(1)
+	}
+}
+
+func run(env *environment, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(env.stderr, usage)
+		return nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprint(env.stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	c, err := newClient(env)
+	if err != nil {
+		return err
+	}
+	return cmd(c, env, args[1:])
+}
+
+func newClient(env *environment) (*okta.Client, error) {
+	var c *okta.Client
+	org, baseURL := env.getenv("OKTA_ORG"), env.getenv("OKTA_BASE_URL")
+	if org == "" && baseURL == "" {
+		var err error
+		if c, err = okta.NewClientFromEnv(); err != nil {
+			return nil, err
+		}
+	} else {
+		c = okta.NewClient(org)
+		c.BaseURL = baseURL
+		c.ApiToken = env.getenv("OKTA_API_TOKEN")
+	}
+	c.UserAgent = "okta-cli/" + okta.Version
+	if env.getenv("OKTA_DEBUG") != "" {
+		c.SetDebug(env.stderr)
+	}
+	if c.Retry == nil {
+		c.Retry = &okta.RetryPolicy{}
+	}
+	return c, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestEnvironment(handler http.HandlerFunc, stdin string) (*environment, *bytes.Buffer, func()) {
+	server := httptest.NewServer(handler)
+
+	stdout := &bytes.Buffer{}
+	vars := map[string]string{"OKTA_BASE_URL": server.URL, "OKTA_API_TOKEN": "token"}
+	return &environment{
+		stdin:  bufio.NewReader(strings.NewReader(stdin)),
+		stdout: stdout,
+		stderr: &bytes.Buffer{},
+		getenv: func(key string) string { return vars[key] },
+	}, stdout, server.Close
+}
+
+func TestListUsers(t *testing.T) {
+	env, stdout, done := newTestEnvironment(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "jane" || r.Header.Get("Authorization") != "SSWS token" {
+			t.Error("Unexpected request ", r.URL, r.Header)
+		}
+		w.Write([]byte(`[{"id":"00u1","status":"ACTIVE","profile":{"login":"jane@example.com"}}]`))
+	}, "")
+	defer done()
+
+	if err := run(env, []string{"users", "-q", "jane"}); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "00u1  ACTIVE  jane@example.com\n" {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+}
+
+func TestAddToGroup(t *testing.T) {
+	var added string
+	env, stdout, done := newTestEnvironment(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/groups":
+			w.Write([]byte(`[{"id":"00g1","profile":{"name":"engineering"}}]`))
+		case r.URL.Path == "/api/v1/users/jane@example.com":
+			w.Write([]byte(`{"id":"00u1","profile":{"login":"jane@example.com"}}`))
+		case r.Method == "PUT":
+			added = r.URL.Path
+		}
+	}, "")
+	defer done()
+
+	if err := run(env, []string{"add-to-group", "engineering", "jane@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if added != "/api/v1/groups/00g1/users/00u1" {
+		t.Error("Unexpected membership ", added)
+	}
+	if stdout.String() != "added jane@example.com to engineering\n" {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+}
+
+func TestAuthenticateMFA(t *testing.T) {
+	var server string
+	env, stdout, done := newTestEnvironment(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/authn":
+			w.Write([]byte(`{"status":"MFA_REQUIRED","stateToken":"state","_embedded":{"factors":[{
+				"factorType":"token:software:totp",
+				"_links":{"verify":{"href":"` + server + `/api/v1/authn/factors/1/verify","hints":{"allow":["POST"]}}}
+			}]}}`))
+		case "/api/v1/authn/factors/1/verify":
+			w.Write([]byte(`{"status":"SUCCESS","sessionToken":"session"}`))
+		}
+	}, "password\n123456\n")
+	defer done()
+	server = env.getenv("OKTA_BASE_URL")
+
+	if err := run(env, []string{"authn", "jane@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "session\n" {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	env, _, done := newTestEnvironment(func(w http.ResponseWriter, r *http.Request) {}, "")
+	defer done()
+	if err := run(env, []string{"nope"}); err == nil {
+		t.Error("Expected an error for an unknown command")
+	}
+}
@@ -0,0 +1,60 @@
+package okta
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentUse is meant to be run with -race
+func TestConcurrentUse(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/sessions":
+			w.Write([]byte(`{"id":"102session"}`))
+		case "/api/v1/users/00u1/groups":
+			w.Write([]byte(`[{"id":"00g1"}]`))
+		default:
+			w.Write([]byte(`{"id":"00u1"}`))
+		}
+	}))
+	defer done()
+	client.Usage = NewUsageAnalyzer()
+	client.Budget = &ErrorBudget{Threshold: 0.5}
+	client.Retry = &RetryPolicy{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 4; j++ {
+				var err error
+				switch (i + j) % 4 {
+				case 0:
+					_, err = client.User("00u1")
+				case 1:
+					_, err = client.Groups("00u1")
+				case 2:
+					_, err = client.Session("token")
+				case 3:
+					client.SetSessionCookie(&http.Cookie{Name: "sid", Value: fmt.Sprint(i)})
+				}
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if len(client.Usage.Summary()) == 0 {
+		t.Error("Expected the usage of all goroutines to be recorded")
+	}
+}
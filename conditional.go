@@ -0,0 +1,103 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ConcurrentModificationError is returned by the IfUnmodified updates when
+// the resource was changed since it was read, the update is not applied
+type ConcurrentModificationError struct {
+	Endpoint string
+	// Expected is the lastUpdated the caller read the resource at
+	Expected *time.Time
+	// Actual is the current lastUpdated, nil when Okta rejected an If-Match
+	Actual *time.Time
+}
+
+func (e *ConcurrentModificationError) Error() string {
+	if e.Actual == nil {
+		return fmt.Sprintf("%s was modified concurrently", e.Endpoint)
+	}
+	return fmt.Sprintf("%s was modified concurrently, last updated %s instead of %s",
+		e.Endpoint, e.Actual.Format(time.RFC3339), e.Expected.Format(time.RFC3339))
+}
+
+// IsConcurrentModification reports whether an update was refused because
+// someone else changed the resource first
+func IsConcurrentModification(err error) bool {
+	_, ok := err.(*ConcurrentModificationError)
+	return ok
+}
+
+// UpdateUserProfileIfUnmodified replaces the profile of user like
+// UpdateUserProfile, unless the user was changed after it was read
+func (c *Client) UpdateUserProfileIfUnmodified(user *User, profile interface{}) (*User, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
+
+	var response = &User{}
+	err := c.updateIfUnmodified("users/"+url.PathEscape(user.ID), "PUT", user.LastUpdated, request, response)
+	return response, err
+}
+
+// UpdateAuthorizationServerIfUnmodified updates server like
+// UpdateAuthorizationServer, unless it was changed after it was read
+func (c *Client) UpdateAuthorizationServerIfUnmodified(server *AuthorizationServer) (*AuthorizationServer, error) {
+	var response = &AuthorizationServer{}
+	err := c.updateIfUnmodified("authorizationServers/"+url.PathEscape(server.ID), "PUT", server.LastUpdated, server, response)
+	return response, err
+}
+
+// UpdateEventHookIfUnmodified updates hook like UpdateEventHook, unless it
+// was changed after it was read
+func (c *Client) UpdateEventHookIfUnmodified(hook *EventHook) (*EventHook, error) {
+	var response = &EventHook{}
+	err := c.updateIfUnmodified("eventHooks/"+url.PathEscape(hook.ID), "PUT", hook.LastUpdated, hook, response)
+	return response, err
+}
+
+// UpdateInlineHookIfUnmodified updates hook like UpdateInlineHook, unless
+// it was changed after it was read
+func (c *Client) UpdateInlineHookIfUnmodified(hook *InlineHook) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err := c.updateIfUnmodified("inlineHooks/"+url.PathEscape(hook.ID), "PUT", hook.LastUpdated, hook, response)
+	return response, err
+}
+
+// updateIfUnmodified re-reads the resource at endpoint and only sends the
+// update when its lastUpdated still matches. Okta resources rarely carry
+// ETags, when one is returned it's sent as If-Match so that Okta rejects
+// changes made between the read and the write, otherwise that window is
+// narrowed to a single round trip but not closed.
+func (c *Client) updateIfUnmodified(endpoint, method string, lastUpdated *time.Time, request, response interface{}) error {
+	if lastUpdated == nil {
+		return fmt.Errorf("%s has no lastUpdated to compare against", endpoint)
+	}
+
+	ctx := context.Background()
+	var current struct {
+		LastUpdated *time.Time `json:"lastUpdated"`
+	}
+	err, _, header := c.callHeader(ctx, endpoint, "GET", nil, nil, &current)
+	if err != nil {
+		return err
+	}
+	if current.LastUpdated != nil && !current.LastUpdated.Equal(*lastUpdated) {
+		return &ConcurrentModificationError{Endpoint: endpoint, Expected: lastUpdated, Actual: current.LastUpdated}
+	}
+
+	conditions := http.Header{}
+	if etag := header.Get("ETag"); etag != "" {
+		conditions.Set("If-Match", etag)
+	}
+	err, _, _ = c.callHeader(ctx, endpoint, method, conditions, request, response)
+	if e, ok := err.(*errorResponse); ok && e.HTTPCode == http.StatusPreconditionFailed {
+		return &ConcurrentModificationError{Endpoint: endpoint, Expected: lastUpdated}
+	}
+	return err
+}
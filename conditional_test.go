@@ -0,0 +1,38 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUpdateIfUnmodified(t *testing.T) {
+	updates := 0
+	ifMatch := ""
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Header().Set("ETag", `"v2"`)
+			w.Write([]byte(`{"id":"00u1","lastUpdated":"2020-01-02T00:00:00.000Z"}`))
+			return
+		}
+		updates++
+		ifMatch = r.Header.Get("If-Match")
+		w.Write([]byte(`{"id":"00u1","lastUpdated":"2020-01-03T00:00:00.000Z"}`))
+	}))
+	defer done()
+
+	stale := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.UpdateUserProfileIfUnmodified(&User{ID: "00u1", LastUpdated: &stale}, map[string]string{})
+	if !IsConcurrentModification(err) || updates != 0 {
+		t.Error("Expected a concurrent modification without update, got ", err, updates)
+	}
+
+	current := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	user, err := client.UpdateUserProfileIfUnmodified(&User{ID: "00u1", LastUpdated: &current}, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates != 1 || ifMatch != `"v2"` || !user.LastUpdated.After(current) {
+		t.Error("Expected a conditional update, got ", updates, ifMatch)
+	}
+}
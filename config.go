@@ -0,0 +1,319 @@
+package okta
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of a client, read by LoadConfig in the order
+// of the official Okta SDKs: ~/.okta/okta.yaml, then .okta.yaml in the
+// working directory, then the environment, each overriding the previous
+type Config struct {
+	// OrgURL is the base URL of the org, e.g. https://example.okta.com
+	OrgURL string
+	// Token is the API token, or an access token with the Bearer
+	// AuthorizationMode
+	Token string
+	// ClientID is the OAuth client id of the app, for the oauth package
+	ClientID string
+	// AuthorizationMode is SSWS when empty or Bearer
+	AuthorizationMode string
+	// Scopes are the OAuth scopes requested for the app
+	Scopes []string
+	// RequestTimeout bounds every call, see Client.RequestTimeout
+	RequestTimeout time.Duration
+	// MaxRetries enables retries with that many retries, see RetryPolicy
+	MaxRetries int
+}
+
+// configEnv are the environment variables of every setting, the names of
+// the official SDKs first
+var configEnv = map[string][]string{
+	"orgUrl":               {"OKTA_CLIENT_ORGURL", "OKTA_ORG_URL"},
+	"token":                {"OKTA_CLIENT_TOKEN", "OKTA_API_TOKEN"},
+	"clientId":             {"OKTA_CLIENT_CLIENTID", "OKTA_CLIENT_ID"},
+	"authorizationMode":    {"OKTA_CLIENT_AUTHORIZATIONMODE"},
+	"scopes":               {"OKTA_CLIENT_SCOPES"},
+	"requestTimeout":       {"OKTA_CLIENT_REQUESTTIMEOUT"},
+	"rateLimit.maxRetries": {"OKTA_CLIENT_RATELIMIT_MAXRETRIES"},
+}
+
+// LoadConfig reads the configuration from the okta.yaml files and the
+// environment, see Config
+func LoadConfig() (*Config, error) {
+	var paths []string
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".okta", "okta.yaml"))
+	}
+	paths = append(paths, ".okta.yaml")
+	return loadConfig(paths, os.Getenv)
+}
+
+// NewClientFromEnv returns a client configured by LoadConfig
+func NewClientFromEnv() (*Client, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config.NewClient()
+}
+
+func loadConfig(paths []string, getenv func(string) string) (*Config, error) {
+	settings := map[string]interface{}{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values, err := parseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("okta: %s: %v", path, err)
+		}
+		for key, value := range values {
+			if strings.HasPrefix(key, "okta.client.") {
+				settings[strings.TrimPrefix(key, "okta.client.")] = value
+			}
+		}
+	}
+	for setting, names := range configEnv {
+		for _, name := range names {
+			if value := getenv(name); value != "" {
+				settings[setting] = value
+				break
+			}
+		}
+	}
+
+	config := &Config{}
+	config.OrgURL, _ = settings["orgUrl"].(string)
+	config.Token, _ = settings["token"].(string)
+	config.ClientID, _ = settings["clientId"].(string)
+	config.AuthorizationMode, _ = settings["authorizationMode"].(string)
+	switch scopes := settings["scopes"].(type) {
+	case []string:
+		config.Scopes = scopes
+	case string:
+		for _, scope := range strings.Split(scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				config.Scopes = append(config.Scopes, scope)
+			}
+		}
+	}
+	if timeout, ok := settings["requestTimeout"].(string); ok {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("okta: requestTimeout %q is not a number of seconds", timeout)
+		}
+		config.RequestTimeout = time.Duration(seconds) * time.Second
+	}
+	if retries, ok := settings["rateLimit.maxRetries"].(string); ok {
+		var err error
+		if config.MaxRetries, err = strconv.Atoi(retries); err != nil {
+			return nil, fmt.Errorf("okta: rateLimit.maxRetries %q is not a number", retries)
+		}
+	}
+	return config, nil
+}
+
+// NewClient returns a client for the configured org
+func (c *Config) NewClient() (*Client, error) {
+	if c.OrgURL == "" {
+		return nil, fmt.Errorf("okta: no orgUrl configured, set OKTA_CLIENT_ORGURL or okta.client.orgUrl")
+	}
+	org, err := url.Parse(c.OrgURL)
+	if err != nil || org.Scheme == "" || org.Host == "" {
+		return nil, fmt.Errorf("okta: orgUrl %q is not a URL", c.OrgURL)
+	}
+
+	client := NewClient(strings.Split(org.Hostname(), ".")[0])
+	client.BaseURL = org.Scheme + "://" + org.Host
+	switch c.AuthorizationMode {
+	case "", "SSWS":
+		client.ApiToken = c.Token
+	case "Bearer":
+		client.AccessToken = c.Token
+	default:
+		return nil, fmt.Errorf("okta: authorizationMode %s is not supported", c.AuthorizationMode)
+	}
+	client.RequestTimeout = c.RequestTimeout
+	if c.MaxRetries > 0 {
+		client.Retry = &RetryPolicy{MaxAttempts: c.MaxRetries + 1}
+	}
+	return client, nil
+}
+
+// parseYAML reads the subset of YAML used by okta.yaml: nested mappings of
+// scalars, lists of scalars and | or > block scalars such as the
+// privateKey PEM of the official SDKs. Keys are returned joined with dots,
+// values are strings or []string.
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	type level struct {
+		indent int
+		key    string
+	}
+	var parents []level
+	var block *blockScalar
+	values := map[string]interface{}{}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if block != nil {
+			if strings.TrimSpace(line) == "" || indent > block.indent {
+				block.add(line)
+				continue
+			}
+			values[block.key] = block.value()
+			block = nil
+		}
+
+		trimmed := strings.TrimSpace(stripComment(line))
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if len(parents) == 0 {
+				return nil, fmt.Errorf("line %d: list item outside of a key", n+1)
+			}
+			key := parents[len(parents)-1].key
+			list, _ := values[key].([]string)
+			values[key] = append(list, unquote(strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))))
+			continue
+		}
+
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		colon := strings.Index(trimmed, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("line %d: expected key: value", n+1)
+		}
+		key := unquote(strings.TrimSpace(trimmed[:colon]))
+		if len(parents) > 0 {
+			key = parents[len(parents)-1].key + "." + key
+		}
+		value := strings.TrimSpace(trimmed[colon+1:])
+
+		switch {
+		case value == "":
+			parents = append(parents, level{indent: indent, key: key})
+		case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			block = &blockScalar{key: key, indent: indent, folded: value[0] == '>', chomp: strings.TrimLeft(value[1:], "0123456789")}
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			list := []string{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, unquote(item))
+				}
+			}
+			values[key] = list
+		default:
+			values[key] = unquote(value)
+		}
+	}
+	if block != nil {
+		values[block.key] = block.value()
+	}
+	return values, nil
+}
+
+// blockScalar collects the lines of a | (literal) or > (folded) block
+// scalar, indented deeper than its key
+type blockScalar struct {
+	key    string
+	indent int
+	folded bool
+	// chomp is - to strip the final line break, + to keep trailing empty
+	// lines, or empty to keep a single line break
+	chomp string
+
+	content int
+	lines   []string
+}
+
+func (b *blockScalar) add(line string) {
+	if strings.TrimSpace(line) == "" {
+		b.lines = append(b.lines, "")
+		return
+	}
+	if b.content == 0 {
+		b.content = len(line) - len(strings.TrimLeft(line, " "))
+	}
+	if len(line) >= b.content {
+		line = line[b.content:]
+	}
+	b.lines = append(b.lines, line)
+}
+
+func (b *blockScalar) value() string {
+	lines := b.lines
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var text string
+	if b.folded {
+		// lines of a paragraph are joined with spaces, empty lines are
+		// line breaks
+		for i, line := range lines {
+			switch {
+			case line == "":
+				text += "\n"
+			case i == 0 || lines[i-1] == "":
+			default:
+				text += " "
+			}
+			text += line
+		}
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+
+	switch {
+	case len(lines) == 0 || b.chomp == "-":
+		return text
+	case b.chomp == "+":
+		return text + strings.Repeat("\n", trailing+1)
+	}
+	return text + "\n"
+}
+
+// stripComment removes a # comment outside of quotes
+func stripComment(line string) string {
+	var quote rune
+	for i, ch := range line {
+		switch {
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && (ch == '"' || ch == '\''):
+			quote = ch
+		case quote == 0 && ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		if value[0] == '"' {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		}
+		return value[1 : len(value)-1]
+	}
+	return value
+}
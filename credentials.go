@@ -0,0 +1,150 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials authorize a request, Scheme is SSWS for API tokens or Bearer
+// for access tokens
+type Credentials struct {
+	Scheme string
+	Token  string
+}
+
+// CredentialsProvider supplies the credentials of every request, so tokens
+// can rotate without recreating the client. It is called for every
+// attempt and must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc is a CredentialsProvider calling a function, e.g. one
+// reading the current token from Vault
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticToken provides an API token that never changes
+func StaticToken(token string) CredentialsProvider {
+	return CredentialsFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Scheme: "SSWS", Token: token}, nil
+	})
+}
+
+// EnvToken provides the API token in the environment variable name, read
+// for every request
+func EnvToken(name string) CredentialsProvider {
+	return CredentialsFunc(func(context.Context) (Credentials, error) {
+		token := os.Getenv(name)
+		if token == "" {
+			return Credentials{}, fmt.Errorf("okta: %s is not set", name)
+		}
+		return Credentials{Scheme: "SSWS", Token: token}, nil
+	})
+}
+
+// FileToken provides the API token in a file, e.g. one rendered by a Vault
+// agent. The file is read again whenever its modification time changes,
+// surrounding whitespace is ignored.
+func FileToken(path string) CredentialsProvider {
+	return &fileToken{path: path}
+}
+
+type fileToken struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	token   string
+}
+
+func (f *fileToken) Credentials(context.Context) (Credentials, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.token == "" || !info.ModTime().Equal(f.modTime) {
+		data, err := ioutil.ReadFile(f.path)
+		if err != nil {
+			return Credentials{}, err
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return Credentials{}, fmt.Errorf("okta: %s is empty", f.path)
+		}
+		f.token, f.modTime = token, info.ModTime()
+	}
+	return Credentials{Scheme: "SSWS", Token: f.token}, nil
+}
+
+// DPoPProver proves possession of the key DPoP bound access tokens are
+// bound to, see oauth.DPoP
+type DPoPProver interface {
+	// Proof returns the DPoP header of a request authorized with
+	// accessToken
+	Proof(method, uri, accessToken string) (string, error)
+	// SetNonce records the DPoP-Nonce of a response
+	SetNonce(uri, nonce string)
+}
+
+// authorize sets the Authorization header from Client.Credentials, or
+// ApiToken and AccessToken when there is no provider. Access tokens are
+// sent with a proof with Client.DPoP.
+func (c *Client) authorize(ctx context.Context, req *http.Request) error {
+	var credentials Credentials
+	if c.Credentials == nil {
+		if c.ApiToken != "" {
+			credentials = Credentials{Scheme: "SSWS", Token: c.ApiToken}
+		} else if c.AccessToken != "" {
+			credentials = Credentials{Scheme: "Bearer", Token: c.AccessToken}
+		} else {
+			return nil
+		}
+	} else {
+		var err error
+		if credentials, err = c.Credentials.Credentials(ctx); err != nil {
+			return err
+		}
+	}
+
+	scheme := credentials.Scheme
+	switch {
+	case scheme == "":
+		scheme = "SSWS"
+	case c.DPoP != nil && (scheme == "Bearer" || scheme == "DPoP"):
+		proof, err := c.DPoP.Proof(req.Method, req.URL.String(), credentials.Token)
+		if err != nil {
+			return err
+		}
+		scheme = "DPoP"
+		req.Header.Set("DPoP", proof)
+	}
+	req.Header.Set("Authorization", scheme+" "+credentials.Token)
+	return nil
+}
+
+// dpopRetry records the DPoP-Nonce of a response and tells whether it
+// rejected the proof of the request for lacking it
+func (c *Client) dpopRetry(req *http.Request, resp *http.Response) bool {
+	if c.DPoP == nil || resp == nil {
+		return false
+	}
+	nonce := resp.Header.Get("DPoP-Nonce")
+	if nonce == "" {
+		return false
+	}
+	c.DPoP.SetNonce(req.URL.String(), nonce)
+	return req.Header.Get("DPoP") != "" && resp.StatusCode == http.StatusUnauthorized &&
+		strings.Contains(resp.Header.Get("WWW-Authenticate"), "use_dpop_nonce")
+}
@@ -0,0 +1,122 @@
+package okta
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Cox-Automotive/go-okta/oauth"
+)
+
+func TestCredentialsProvider(t *testing.T) {
+	var authorization string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer done()
+
+	dir, err := ioutil.TempDir("", "okta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	ioutil.WriteFile(path, []byte("00first\n"), 0600)
+	client.ApiToken = "ignored"
+	client.Credentials = FileToken(path)
+
+	if _, err := client.User("00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "SSWS 00first" {
+		t.Error("Expected the token of the file, got ", authorization)
+	}
+
+	ioutil.WriteFile(path, []byte("00second"), 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(path, later, later)
+	if _, err := client.User("00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "SSWS 00second" {
+		t.Error("Expected the rotated token, got ", authorization)
+	}
+
+	client.Credentials = CredentialsFunc(func(context.Context) (Credentials, error) {
+		return Credentials{Scheme: "Bearer", Token: "access"}, nil
+	})
+	if _, err := client.User("00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "Bearer access" {
+		t.Error("Expected the token of the callback, got ", authorization)
+	}
+
+	client.Credentials = EnvToken("OKTA_TEST_TOKEN_UNSET")
+	if _, err := client.User("00u1"); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+// dpopRecorder is a DPoPProver recording the proofs and nonces
+type dpopRecorder struct {
+	mu     sync.Mutex
+	proofs []string
+	nonce  string
+}
+
+func (d *dpopRecorder) Proof(method, uri, accessToken string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	proof := method + " " + uri + " " + accessToken + " " + d.nonce
+	d.proofs = append(d.proofs, proof)
+	return proof, nil
+}
+
+func (d *dpopRecorder) SetNonce(uri, nonce string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nonce = nonce
+}
+
+func TestDPoP(t *testing.T) {
+	var authorizations []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		if !strings.HasSuffix(r.Header.Get("DPoP"), " nonce") {
+			w.Header().Set("WWW-Authenticate", `DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`)
+			w.Header().Set("DPoP-Nonce", "nonce")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"00o1"}`))
+	}))
+	defer done()
+	dpop := &dpopRecorder{}
+	client.AccessToken = "access"
+	client.DPoP = dpop
+
+	if _, err := client.OrgSettings(); err != nil {
+		t.Fatal(err)
+	}
+	if len(dpop.proofs) != 2 || dpop.proofs[0] != "GET "+client.baseURL()+"/api/v1/org access " {
+		t.Error("Expected a proof of the request and one with the nonce, got ", dpop.proofs)
+	}
+	if len(authorizations) != 2 || authorizations[1] != "DPoP access" {
+		t.Error("Expected the DPoP scheme, got ", authorizations)
+	}
+
+	client.ApiToken = "token"
+	if _, err := client.OrgSettings(); err == nil || len(dpop.proofs) != 2 || len(authorizations) != 3 || authorizations[2] != "SSWS token" {
+		t.Error("Expected API tokens to be sent without a proof, got ", authorizations)
+	}
+}
+
+var _ DPoPProver = (*oauth.DPoP)(nil)
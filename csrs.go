@@ -0,0 +1,95 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CSRRequest is the subject of a certificate signing request generated
+// for an app
+type CSRRequest struct {
+	Subject struct {
+		CountryName            string `json:"countryName,omitempty"`
+		StateOrProvinceName    string `json:"stateOrProvinceName,omitempty"`
+		LocalityName           string `json:"localityName,omitempty"`
+		OrganizationName       string `json:"organizationName,omitempty"`
+		OrganizationalUnitName string `json:"organizationalUnitName,omitempty"`
+		CommonName             string `json:"commonName,omitempty"`
+	} `json:"subject"`
+	SubjectAltNames struct {
+		DNSNames []string `json:"dnsNames,omitempty"`
+	} `json:"subjectAltNames"`
+}
+
+// CSR is a certificate signing request of an app, CSR holds the base64
+// encoded DER of the request to send to the certificate authority
+type CSR struct {
+	ID      string     `json:"id"`
+	Created *time.Time `json:"created"`
+	CSR     string     `json:"csr"`
+	Kty     string     `json:"kty"`
+}
+
+// GenerateCSR creates a key pair for an app and returns the signing
+// request of its public key, the key is only usable once the signed
+// certificate is published with PublishCert
+func (c *Client) GenerateCSR(appID string, request *CSRRequest) (*CSR, error) {
+	var response = &CSR{}
+	err, _ := c.call(appCredentials(appID)+"csrs", "POST", request, response)
+	return response, err
+}
+
+// ListCSRs returns the signing requests of an app that weren't published
+// or revoked
+func (c *Client) ListCSRs(appID string) (*[]CSR, error) {
+	var response = &[]CSR{}
+	err, _ := c.call(appCredentials(appID)+"csrs", "GET", nil, response)
+	return response, err
+}
+
+// RevokeCSR deletes a signing request of an app and its key pair
+func (c *Client) RevokeCSR(appID, csrID string) error {
+	err, _ := c.call(appCredentials(appID)+"csrs/"+url.PathEscape(csrID), "DELETE", nil, nil)
+	return err
+}
+
+// PublishCert publishes the certificate signed for a signing request and
+// returns the key it adds to the app. The certificate is PEM or DER
+// encoded. The app keeps signing with its current key until
+// SetAppSigningKey switches it to the new one.
+func (c *Client) PublishCert(appID, csrID string, cert []byte) (*AppKey, error) {
+	contentType := "application/pkix-cert"
+	if bytes.HasPrefix(bytes.TrimSpace(cert), []byte("-----BEGIN")) {
+		contentType = "application/x-pem-file"
+	}
+	header := http.Header{"Content-Type": {contentType}}
+
+	var response = &AppKey{}
+	err, _, _ := c.callHeader(context.Background(), appCredentials(appID)+"csrs/"+url.PathEscape(csrID)+"/lifecycle/publish", "POST", header, cert, response)
+	return response, err
+}
+
+// SetAppSigningKey switches a SAML or WS-Fed app to sign with the key with
+// the given kid, e.g. one of GenerateAppKey, CloneAppKey or PublishCert.
+// The service provider must trust the new certificate first.
+func (c *Client) SetAppSigningKey(appID, keyID string) (*App, error) {
+	var app = &App{}
+	if err, _ := c.call("apps/"+url.PathEscape(appID), "GET", nil, app); err != nil {
+		return nil, err
+	}
+
+	credentials := app.Credentials
+	credentials.Signing.Kid = keyID
+	return c.UpdateApp(appID, &AppRequest{
+		Name:        app.Name,
+		Label:       app.Label,
+		SignOnMode:  app.SignOnMode,
+		Features:    app.Features,
+		Settings:    app.Settings,
+		Credentials: &credentials,
+		Unknown:     app.Unknown,
+	})
+}
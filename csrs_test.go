@@ -0,0 +1,55 @@
+package okta
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const testCert = `-----BEGIN CERTIFICATE-----
+MIIDqDCCApCgAwIBAgIGAVGNO4qeMA0GCSqGSIb3DQEBBQUAMIGUMQswCQYDVQQG
+-----END CERTIFICATE-----
+`
+
+func TestRotateSigningCertificate(t *testing.T) {
+	var published, contentType string
+	var updated AppRequest
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/apps/0oa1/credentials/csrs":
+			w.Write([]byte(`{"id":"h9zkutaSe7fZX0SwN1GqDApofgD1OW8g2B5l2azha50","csr":"MIIC4DCCAcgCAQAwcTELMAkGA1UEBhMCVVMx","kty":"RSA"}`))
+		case "/api/v1/apps/0oa1/credentials/csrs/h9zkutaSe7fZX0SwN1GqDApofgD1OW8g2B5l2azha50/lifecycle/publish":
+			body, _ := ioutil.ReadAll(r.Body)
+			published, contentType = string(body), r.Header.Get("Content-Type")
+			w.Write([]byte(`{"kid":"key2","kty":"RSA","use":"sig"}`))
+		case "/api/v1/apps/0oa1":
+			if r.Method == "PUT" {
+				json.NewDecoder(r.Body).Decode(&updated)
+			}
+			w.Write([]byte(`{"id":"0oa1","name":"wiki_saml","label":"Wiki","signOnMode":"SAML_2_0","credentials":{"userNameTemplate":{"template":"${source.login}","type":"BUILT_IN"},"signing":{"kid":"key1"}},"settings":{"app":{}}}`))
+		}
+	}))
+	defer done()
+
+	request := &CSRRequest{}
+	request.Subject.CommonName = "wiki.example.com"
+	csr, err := client.GenerateCSR("0oa1", request)
+	if err != nil || csr.CSR == "" {
+		t.Fatal("Expected the signing request, got ", csr, err)
+	}
+	key, err := client.PublishCert("0oa1", csr.ID, []byte(testCert))
+	if err != nil || key.Kid != "key2" {
+		t.Fatal("Expected the published key, got ", key, err)
+	}
+	if published != testCert || contentType != "application/x-pem-file" {
+		t.Error("Expected the PEM certificate to be published, got ", contentType, published)
+	}
+
+	if _, err := client.SetAppSigningKey("0oa1", key.Kid); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Credentials == nil || updated.Credentials.Signing.Kid != "key2" || updated.Credentials.UserNameTemplate.Type != "BUILT_IN" || updated.Name != "wiki_saml" {
+		t.Errorf("Expected the app to sign with the new key, got %+v", updated)
+	}
+}
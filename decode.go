@@ -0,0 +1,128 @@
+package okta
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// UnknownFields are the attributes of a resource this package has no field
+// for, by JSON name. They are only decoded when Client.PreserveUnknownFields
+// is set and are encoded again with the resource, so that an update built
+// from a fetched resource keeps the attributes Okta added since.
+type UnknownFields map[string]json.RawMessage
+
+var unknownFieldsType = reflect.TypeOf(UnknownFields(nil))
+
+// decode reads a successful response into v, rejecting attributes without
+// a field with StrictDecoding. Custom attributes of user profiles are never
+// unknown.
+func (c *Client) decode(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	if c.StrictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+// preserveUnknown stores the attributes of data without a field in the
+// Unknown field of v, or of its elements when it is a slice
+func preserveUnknown(data []byte, v interface{}) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil || len(items) != value.Len() {
+			return
+		}
+		for i, item := range items {
+			preserveUnknown(item, value.Index(i).Addr().Interface())
+		}
+	case reflect.Struct:
+		field := value.FieldByName("Unknown")
+		if !field.IsValid() || field.Type() != unknownFieldsType || !field.CanSet() {
+			return
+		}
+		var attributes map[string]json.RawMessage
+		if json.Unmarshal(data, &attributes) != nil {
+			return
+		}
+		known := jsonNames(value.Type())
+		unknown := UnknownFields{}
+		for name, attribute := range attributes {
+			if !known[strings.ToLower(name)] {
+				unknown[name] = attribute
+			}
+		}
+		if len(unknown) > 0 {
+			field.Set(reflect.ValueOf(unknown))
+		}
+	}
+}
+
+// jsonNames are the lowercase JSON names of the fields of a struct type,
+// encoding/json matches them case-insensitively
+func jsonNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// marshalRequest encodes a request body with the unknown fields of the
+// resource, attributes with a field take precedence
+func marshalRequest(request interface{}) ([]byte, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(request)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return data, nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return data, nil
+	}
+	field := value.FieldByName("Unknown")
+	if !field.IsValid() || field.Type() != unknownFieldsType || field.Len() == 0 {
+		return data, nil
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return data, nil
+	}
+	for name, attribute := range field.Interface().(UnknownFields) {
+		if _, ok := attributes[name]; !ok {
+			attributes[name] = attribute
+		}
+	}
+	return json.Marshal(attributes)
+}
@@ -0,0 +1,77 @@
+package okta
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"who1","name":"audit","filter":{"type":"EXPRESSION"}}`))
+	}))
+	defer done()
+
+	if _, err := client.EventHook("who1"); err != nil {
+		t.Fatal("Expected unknown attributes to be ignored by default, got ", err)
+	}
+
+	client.StrictDecoding = true
+	_, err := client.EventHook("who1")
+	if err == nil || !strings.Contains(err.Error(), "filter") {
+		t.Error("Expected an error naming the unknown attribute, got ", err)
+	}
+}
+
+func TestPreserveUnknownFields(t *testing.T) {
+	var sent map[string]json.RawMessage
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/groups/rules":
+			w.Write([]byte(`[{"id":"0pr1","name":"all"},{"id":"0pr2","name":"staff","priority":2}]`))
+		case r.Method == "GET":
+			w.Write([]byte(`{"id":"0oa1","name":"bookmark","label":"Wiki","accessibility":{"selfService":false},"profile":{"tier":1}}`))
+		case r.Method == "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &sent)
+			w.Write(body)
+		}
+	}))
+	defer done()
+	client.PreserveUnknownFields = true
+
+	rules, err := client.GroupRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len((*rules)[0].Unknown) != 0 || string((*rules)[1].Unknown["priority"]) != "2" {
+		t.Error("Expected the unknown attributes of every item, got ", (*rules)[0].Unknown, (*rules)[1].Unknown)
+	}
+
+	if _, err := client.SetAppSigningKey("0oa1", "akm1"); err != nil {
+		t.Fatal(err)
+	}
+	if string(sent["profile"]) != `{"tier":1}` || string(sent["accessibility"]) != `{"selfService":false}` {
+		t.Error("Expected the unknown attributes to be sent back, got ", sent)
+	}
+	if string(sent["label"]) != `"Wiki"` {
+		t.Error("Expected the known attributes to be sent once, got ", string(sent["label"]))
+	}
+}
+
+func TestMarshalRequestPrecedence(t *testing.T) {
+	data, err := marshalRequest(&AppRequest{
+		Label:   "Team wiki",
+		Unknown: UnknownFields{"label": json.RawMessage(`"stale"`), "profile": json.RawMessage(`{}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]json.RawMessage
+	json.Unmarshal(data, &sent)
+	if string(sent["label"]) != `"Team wiki"` || string(sent["profile"]) != "{}" {
+		t.Error("Expected the fields to take precedence over Unknown, got ", string(data))
+	}
+}
@@ -0,0 +1,141 @@
+package okta
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBudgetWindow   = 20
+	defaultBudgetCooldown = 30 * time.Second
+)
+
+// enrichmentParams are query parameters that only add data to a response,
+// they are dropped while the client is degraded
+var enrichmentParams = []string{"expand"}
+
+// ErrorBudget puts a client into degradation mode once too many recent
+// calls failed or were slow. While degraded, non-critical enrichment such
+// as expand=stats is skipped and list results are returned together with a
+// PartialResultError, authentication calls are never affected.
+type ErrorBudget struct {
+	// Threshold is the share of failed or slow calls, between 0 and 1,
+	// that exhausts the budget
+	Threshold float64
+	// SlowCall is the latency above which a call counts as failed, slow
+	// calls are not considered when zero
+	SlowCall time.Duration
+	// Window is the number of most recent calls evaluated, 20 when zero
+	Window int
+	// Cooldown is how long the client stays degraded, 30 seconds when zero
+	Cooldown time.Duration
+
+	mu            sync.Mutex
+	outcomes      []bool
+	next          int
+	degradedUntil time.Time
+}
+
+// Degraded reports whether enrichment is currently being skipped
+func (b *ErrorBudget) Degraded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().Before(b.degradedUntil)
+}
+
+func (b *ErrorBudget) record(resp *http.Response, latency time.Duration) {
+	failed := resp == nil ||
+		resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		(b.SlowCall > 0 && latency > b.SlowCall)
+
+	window := b.Window
+	if window == 0 {
+		window = defaultBudgetWindow
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.outcomes) < window {
+		b.outcomes = append(b.outcomes, failed)
+	} else {
+		b.outcomes[b.next%window] = failed
+	}
+	b.next++
+
+	if len(b.outcomes) < window {
+		return
+	}
+
+	failures := 0
+	for _, outcome := range b.outcomes {
+		if outcome {
+			failures++
+		}
+	}
+	if float64(failures)/float64(window) < b.Threshold {
+		return
+	}
+
+	cooldown := b.Cooldown
+	if cooldown == 0 {
+		cooldown = defaultBudgetCooldown
+	}
+	b.degradedUntil = time.Now().Add(cooldown)
+	// start over so the client isn't degraded again straight after the
+	// cooldown because of calls made before it
+	b.outcomes = b.outcomes[:0]
+	b.next = 0
+}
+
+// PartialResultError is returned alongside a usable result when enrichment
+// was skipped because the error budget was exhausted
+type PartialResultError struct {
+	Endpoint string
+	Skipped  []string
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("Partial result for %s, skipped %s", e.Endpoint, strings.Join(e.Skipped, ", "))
+}
+
+// IsPartialResult reports whether err only flags a degraded result
+func IsPartialResult(err error) bool {
+	_, ok := err.(*PartialResultError)
+	return ok
+}
+
+// withoutEnrichment strips enrichment parameters from an endpoint and
+// returns the names of those it removed
+func withoutEnrichment(endpoint string) (string, []string) {
+	i := strings.Index(endpoint, "?")
+	if i < 0 {
+		return endpoint, nil
+	}
+
+	query, err := url.ParseQuery(endpoint[i+1:])
+	if err != nil {
+		return endpoint, nil
+	}
+
+	var skipped []string
+	for _, param := range enrichmentParams {
+		for _, value := range query[param] {
+			skipped = append(skipped, param+"="+value)
+		}
+		query.Del(param)
+	}
+	if len(skipped) == 0 {
+		return endpoint, nil
+	}
+
+	if len(query) == 0 {
+		return endpoint[:i], skipped
+	}
+	return endpoint[:i] + "?" + query.Encode(), skipped
+}
@@ -0,0 +1,36 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorBudget(t *testing.T) {
+	budget := &ErrorBudget{Threshold: 0.5, Window: 4, SlowCall: time.Second}
+
+	ok := &http.Response{StatusCode: http.StatusOK}
+	budget.record(ok, time.Millisecond)
+	budget.record(ok, time.Millisecond)
+	budget.record(&http.Response{StatusCode: http.StatusServiceUnavailable}, time.Millisecond)
+	if budget.Degraded() {
+		t.Fatal("Expected budget not to be evaluated before the window is full")
+	}
+
+	budget.record(ok, 2*time.Second)
+	if !budget.Degraded() {
+		t.Error("Expected budget to be exhausted by one error and one slow call")
+	}
+}
+
+func TestWithoutEnrichment(t *testing.T) {
+	endpoint, skipped := withoutEnrichment("groups?expand=stats&limit=200")
+	if endpoint != "groups?limit=200" || len(skipped) != 1 || skipped[0] != "expand=stats" {
+		t.Error("Expected expand to be stripped, got ", endpoint, skipped)
+	}
+
+	endpoint, skipped = withoutEnrichment("users/00u1")
+	if endpoint != "users/00u1" || skipped != nil {
+		t.Error("Expected endpoint without enrichment to be unchanged, got ", endpoint, skipped)
+	}
+}
@@ -0,0 +1,61 @@
+package okta
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// DeprecationWarning describes the deprecation headers of a response
+type DeprecationWarning struct {
+	Method   string
+	Endpoint string
+	// Deprecation is the raw Deprecation header, either "true" or the
+	// date the endpoint was deprecated
+	Deprecation string
+	// Sunset is when the endpoint is scheduled for removal if announced
+	Sunset *time.Time
+	// Message is Okta's explanation from the X-Okta-Deprecation header
+	Message string
+	// Link points to documentation about the deprecation
+	Link string
+}
+
+func (c *Client) checkDeprecation(method, endpoint string, header http.Header) {
+	warning := DeprecationWarning{
+		Method:      method,
+		Endpoint:    endpoint,
+		Deprecation: header.Get("Deprecation"),
+		Message:     header.Get("X-Okta-Deprecation"),
+		Link:        linkRel(header, "deprecation"),
+	}
+	if sunset, err := http.ParseTime(header.Get("Sunset")); err == nil {
+		warning.Sunset = &sunset
+	}
+
+	if warning.Deprecation == "" && warning.Message == "" && warning.Sunset == nil {
+		return
+	}
+
+	if c.OnDeprecation != nil {
+		c.OnDeprecation(warning)
+		return
+	}
+
+	key := method + " " + endpointTemplate(endpoint)
+	if _, logged := c.deprecations.LoadOrStore(key, true); logged {
+		return
+	}
+
+	msg := "okta: " + key + " is deprecated"
+	if warning.Sunset != nil {
+		msg += " and will be removed on " + warning.Sunset.Format("2006-01-02")
+	}
+	if warning.Message != "" {
+		msg += ": " + warning.Message
+	}
+	if warning.Link != "" {
+		msg += " (" + warning.Link + ")"
+	}
+	log.Println(msg)
+}
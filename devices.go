@@ -0,0 +1,109 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Device states
+const (
+	DeviceCreated     = "CREATED"
+	DeviceActive      = "ACTIVE"
+	DeviceSuspended   = "SUSPENDED"
+	DeviceDeactivated = "DEACTIVATED"
+)
+
+// Device is a device registered with Okta Verify or a device management
+// integration
+type Device struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	Profile     struct {
+		DisplayName string `json:"displayName"`
+		// Platform is ANDROID, IOS, MACOS or WINDOWS
+		Platform              string `json:"platform"`
+		Manufacturer          string `json:"manufacturer"`
+		Model                 string `json:"model"`
+		OSVersion             string `json:"osVersion"`
+		SerialNumber          string `json:"serialNumber"`
+		IMEI                  string `json:"imei"`
+		MEID                  string `json:"meid"`
+		UDID                  string `json:"udid"`
+		SID                   string `json:"sid"`
+		Registered            bool   `json:"registered"`
+		SecureHardwarePresent bool   `json:"secureHardwarePresent"`
+		DiskEncryptionType    string `json:"diskEncryptionType"`
+	} `json:"profile"`
+	ResourceID   string `json:"resourceId"`
+	ResourceType string `json:"resourceType"`
+}
+
+// DeviceUser is a user of a device
+type DeviceUser struct {
+	Created *time.Time `json:"created"`
+	// ManagementStatus is MANAGED or NOT_MANAGED
+	ManagementStatus string `json:"managementStatus"`
+	ScreenLockType   string `json:"screenLockType"`
+	User             User   `json:"user"`
+}
+
+// ListDevices returns the devices matching a search expression such as
+// profile.platform eq "MACOS", all of them when empty
+func (c *Client) ListDevices(search string) (*[]Device, error) {
+	v := url.Values{}
+	setNonEmpty(v, "search", search)
+	v.Set("limit", "200")
+
+	var response = &[]Device{}
+	err := c.listAll(withQuery("devices", v), response)
+	return response, err
+}
+
+// GetDevice returns the device with the given id
+func (c *Client) GetDevice(deviceID string) (*Device, error) {
+	var response = &Device{}
+	err, _ := c.call("devices/"+url.PathEscape(deviceID), "GET", nil, response)
+	return response, err
+}
+
+// DeviceUsers returns the users of a device
+func (c *Client) DeviceUsers(deviceID string) (*[]DeviceUser, error) {
+	var response = &[]DeviceUser{}
+	err, _ := c.call("devices/"+url.PathEscape(deviceID)+"/users", "GET", nil, response)
+	return response, err
+}
+
+// ActivateDevice activates a CREATED or DEACTIVATED device
+func (c *Client) ActivateDevice(deviceID string) error {
+	return c.deviceLifecycle(deviceID, "activate")
+}
+
+// DeactivateDevice deactivates a device, which removes its Okta Verify
+// credentials, it has to be deactivated before it can be deleted
+func (c *Client) DeactivateDevice(deviceID string) error {
+	return c.deviceLifecycle(deviceID, "deactivate")
+}
+
+// SuspendDevice suspends an ACTIVE device, it can't be used to sign in
+// until it is unsuspended
+func (c *Client) SuspendDevice(deviceID string) error {
+	return c.deviceLifecycle(deviceID, "suspend")
+}
+
+// UnsuspendDevice activates a SUSPENDED device again
+func (c *Client) UnsuspendDevice(deviceID string) error {
+	return c.deviceLifecycle(deviceID, "unsuspend")
+}
+
+// DeleteDevice deletes a DEACTIVATED device
+func (c *Client) DeleteDevice(deviceID string) error {
+	err, _ := c.call("devices/"+url.PathEscape(deviceID), "DELETE", nil, nil)
+	return err
+}
+
+func (c *Client) deviceLifecycle(deviceID, lifecycle string) error {
+	err, _ := c.call("devices/"+url.PathEscape(deviceID)+"/lifecycle/"+lifecycle, "POST", nil, nil)
+	return err
+}
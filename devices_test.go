@@ -0,0 +1,39 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDevices(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/devices":
+			w.Write([]byte(`[{"id":"guo1","status":"ACTIVE","profile":{"displayName":"Jane's MacBook","platform":"MACOS","registered":true}}]`))
+		case "/api/v1/devices/guo1/users":
+			w.Write([]byte(`[{"managementStatus":"MANAGED","screenLockType":"BIOMETRIC","user":{"id":"00u1","profile":{"login":"jane@example.com"}}}]`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	devices, err := client.ListDevices(`profile.platform eq "MACOS"`)
+	if err != nil || len(*devices) != 1 || (*devices)[0].Profile.Platform != "MACOS" || !(*devices)[0].Profile.Registered {
+		t.Fatal("Expected the device, got ", devices, err)
+	}
+	users, err := client.DeviceUsers("guo1")
+	if err != nil || len(*users) != 1 || (*users)[0].User.ID != "00u1" || (*users)[0].ManagementStatus != "MANAGED" {
+		t.Fatal("Expected the user of the device, got ", users, err)
+	}
+	if err := client.SuspendDevice("guo1"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/devices?limit=200&search=profile.platform+eq+%22MACOS%22",
+		"GET /api/v1/devices/guo1/users",
+		"POST /api/v1/devices/guo1/lifecycle/suspend",
+	)
+}
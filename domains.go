@@ -0,0 +1,94 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Certificate source types of a custom domain
+const (
+	CertificateManual      = "MANUAL"
+	CertificateOktaManaged = "OKTA_MANAGED"
+)
+
+type Domain struct {
+	ID                    string `json:"id,omitempty"`
+	Domain                string `json:"domain"`
+	CertificateSourceType string `json:"certificateSourceType"`
+	// ValidationStatus is NOT_STARTED, IN_PROGRESS, VERIFIED or COMPLETED
+	ValidationStatus string `json:"validationStatus,omitempty"`
+	// DNSRecords are the records to create before VerifyDomain
+	DNSRecords []struct {
+		RecordType string     `json:"recordType"`
+		FQDN       string     `json:"fqdn"`
+		Values     []string   `json:"values"`
+		Expiration *time.Time `json:"expiration,omitempty"`
+	} `json:"dnsRecords,omitempty"`
+	PublicCertificate *struct {
+		Subject     string     `json:"subject"`
+		Fingerprint string     `json:"fingerprint"`
+		Expiration  *time.Time `json:"expiration"`
+	} `json:"publicCertificate,omitempty"`
+}
+
+// DomainCertificate is a certificate for a MANUAL custom domain, all
+// values PEM encoded
+type DomainCertificate struct {
+	Type             string `json:"type"`
+	Certificate      string `json:"certificate"`
+	CertificateChain string `json:"certificateChain"`
+	PrivateKey       string `json:"privateKey"`
+}
+
+// ListDomains returns the custom domains of the org
+func (c *Client) ListDomains() (*[]Domain, error) {
+	var response struct {
+		Domains []Domain `json:"domains"`
+	}
+	err, _ := c.call("domains", "GET", nil, &response)
+	return &response.Domains, err
+}
+
+// Domain takes a domain id and returns the custom domain
+func (c *Client) Domain(domainID string) (*Domain, error) {
+	var response = &Domain{}
+	err, _ := c.call("domains/"+url.PathEscape(domainID), "GET", nil, response)
+	return response, err
+}
+
+// CreateDomain adds a custom domain, the returned DNSRecords have to be
+// created before the domain can be verified
+func (c *Client) CreateDomain(domain, certificateSourceType string) (*Domain, error) {
+	var request = &Domain{
+		Domain:                domain,
+		CertificateSourceType: certificateSourceType,
+	}
+
+	var response = &Domain{}
+	err, _ := c.call("domains", "POST", request, response)
+	return response, err
+}
+
+// VerifyDomain checks the DNS records of a custom domain, Okta managed
+// certificates are issued once verified
+func (c *Client) VerifyDomain(domainID string) (*Domain, error) {
+	var response = &Domain{}
+	err, _ := c.call("domains/"+url.PathEscape(domainID)+"/verify", "POST", nil, response)
+	return response, err
+}
+
+// UploadDomainCertificate sets the certificate of a MANUAL custom domain
+func (c *Client) UploadDomainCertificate(domainID string, certificate *DomainCertificate) error {
+	var request = *certificate
+	if request.Type == "" {
+		request.Type = "PEM"
+	}
+	err, _ := c.call("domains/"+url.PathEscape(domainID)+"/certificate", "PUT", &request, nil)
+	return err
+}
+
+// DeleteDomain removes a custom domain
+func (c *Client) DeleteDomain(domainID string) error {
+	err, _ := c.call("domains/"+url.PathEscape(domainID), "DELETE", nil, nil)
+	return err
+}
@@ -0,0 +1,163 @@
+package okta
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ErrorKind is the library's category of an Okta error
+type ErrorKind int
+
+const (
+	ErrorUnknown ErrorKind = iota
+	ErrorInvalidRequest
+	ErrorUnauthorized
+	ErrorForbidden
+	ErrorNotFound
+	ErrorConflict
+	ErrorRateLimited
+	ErrorServer
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorInvalidRequest:
+		return "invalid request"
+	case ErrorUnauthorized:
+		return "unauthorized"
+	case ErrorForbidden:
+		return "forbidden"
+	case ErrorNotFound:
+		return "not found"
+	case ErrorConflict:
+		return "conflict"
+	case ErrorRateLimited:
+		return "rate limited"
+	case ErrorServer:
+		return "server error"
+	}
+	return "unknown"
+}
+
+// ErrorClass is how the library treats an error returned by Okta
+type ErrorClass struct {
+	Kind      ErrorKind
+	Retryable bool
+}
+
+type errorKey struct {
+	status int
+	code   string
+}
+
+// ErrorMap maps an HTTP status and Okta error code to an ErrorClass. The
+// most specific entry wins: status and code, then code alone, then status
+// alone, then the status range. It is safe for concurrent use.
+type ErrorMap struct {
+	mu      sync.RWMutex
+	classes map[errorKey]ErrorClass
+}
+
+// DefaultErrorMap is used by clients without an ErrorMap of their own,
+// changing it affects every such client
+var DefaultErrorMap = NewErrorMap()
+
+// NewErrorMap returns a map populated with the library defaults
+func NewErrorMap() *ErrorMap {
+	m := &ErrorMap{classes: map[errorKey]ErrorClass{}}
+
+	m.Set(http.StatusBadRequest, "", ErrorClass{Kind: ErrorInvalidRequest})
+	m.Set(http.StatusUnauthorized, "", ErrorClass{Kind: ErrorUnauthorized})
+	m.Set(http.StatusForbidden, "", ErrorClass{Kind: ErrorForbidden})
+	m.Set(http.StatusNotFound, "", ErrorClass{Kind: ErrorNotFound})
+	m.Set(http.StatusConflict, "", ErrorClass{Kind: ErrorConflict})
+	m.Set(http.StatusPreconditionFailed, "", ErrorClass{Kind: ErrorConflict})
+	m.Set(http.StatusTooManyRequests, "", ErrorClass{Kind: ErrorRateLimited, Retryable: true})
+	m.Set(http.StatusInternalServerError, "", ErrorClass{Kind: ErrorServer, Retryable: true})
+	m.Set(http.StatusBadGateway, "", ErrorClass{Kind: ErrorServer, Retryable: true})
+	m.Set(http.StatusServiceUnavailable, "", ErrorClass{Kind: ErrorServer, Retryable: true})
+	m.Set(http.StatusGatewayTimeout, "", ErrorClass{Kind: ErrorServer, Retryable: true})
+
+	// https://developer.okta.com/docs/reference/error-codes/
+	m.Set(0, "E0000001", ErrorClass{Kind: ErrorInvalidRequest})
+	m.Set(0, "E0000004", ErrorClass{Kind: ErrorUnauthorized})
+	m.Set(0, "E0000005", ErrorClass{Kind: ErrorUnauthorized})
+	m.Set(0, "E0000006", ErrorClass{Kind: ErrorForbidden})
+	m.Set(0, "E0000007", ErrorClass{Kind: ErrorNotFound})
+	m.Set(0, "E0000009", ErrorClass{Kind: ErrorServer, Retryable: true})
+	m.Set(0, "E0000011", ErrorClass{Kind: ErrorUnauthorized})
+	m.Set(0, "E0000047", ErrorClass{Kind: ErrorRateLimited, Retryable: true})
+
+	return m
+}
+
+// Set overrides the class for an HTTP status and Okta error code, a status
+// of 0 matches any status and an empty code matches any code
+func (m *ErrorMap) Set(status int, code string, class ErrorClass) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.classes[errorKey{status, code}] = class
+}
+
+// Classify returns the class of an error response
+func (m *ErrorMap) Classify(status int, code string) ErrorClass {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range []errorKey{{status, code}, {0, code}, {status, ""}} {
+		if key.code == "" && key.status == 0 {
+			continue
+		}
+		if class, ok := m.classes[key]; ok {
+			return class
+		}
+	}
+
+	switch {
+	case status >= 500:
+		return ErrorClass{Kind: ErrorServer, Retryable: true}
+	case status >= 400:
+		return ErrorClass{Kind: ErrorInvalidRequest}
+	}
+	return ErrorClass{Kind: ErrorUnknown}
+}
+
+func (c *Client) errorMap() *ErrorMap {
+	if c.ErrorMap != nil {
+		return c.ErrorMap
+	}
+	return DefaultErrorMap
+}
+
+// ClassOf returns the class of an error returned by the client, errors not
+// coming from Okta such as network failures are of kind ErrorUnknown
+func ClassOf(err error) ErrorClass {
+	if e, ok := err.(*errorResponse); ok {
+		return e.Class
+	}
+	return ErrorClass{Kind: ErrorUnknown}
+}
+
+// RequestIDOf returns the X-Okta-Request-Id of the response an error
+// returned by the client came from, empty for other errors
+func RequestIDOf(err error) string {
+	if e, ok := err.(*errorResponse); ok {
+		return e.RequestID
+	}
+	return ""
+}
+
+// IsRetryable reports whether the request failing with err may succeed
+// when sent again
+func IsRetryable(err error) bool {
+	return ClassOf(err).Retryable
+}
+
+// ErrorCode returns the Okta error code of err, e.g. E0000007, or an empty
+// string if err didn't come from Okta
+func ErrorCode(err error) string {
+	if e, ok := err.(*errorResponse); ok {
+		return e.Response.ErrorCode
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorMapClassify(t *testing.T) {
+	m := NewErrorMap()
+
+	if class := m.Classify(http.StatusTooManyRequests, "E0000047"); class.Kind != ErrorRateLimited || !class.Retryable {
+		t.Errorf("Expected retryable rate limit, got %+v", class)
+	}
+	if class := m.Classify(http.StatusNotFound, "E0000007"); class.Kind != ErrorNotFound || class.Retryable {
+		t.Errorf("Expected not found, got %+v", class)
+	}
+	if class := m.Classify(http.StatusTeapot, ""); class.Kind != ErrorInvalidRequest {
+		t.Errorf("Expected unmapped 4xx to be an invalid request, got %+v", class)
+	}
+
+	// E0000006 is forbidden by default, pretend this org needs it retried
+	m.Set(http.StatusForbidden, "E0000006", ErrorClass{Kind: ErrorForbidden, Retryable: true})
+	if class := m.Classify(http.StatusForbidden, "E0000006"); !class.Retryable {
+		t.Errorf("Expected override to win, got %+v", class)
+	}
+	if class := m.Classify(http.StatusForbidden, "E0000005"); class.Retryable {
+		t.Errorf("Expected override to be limited to its code, got %+v", class)
+	}
+}
+
+func TestErrorClassOfResponse(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"errorCode":"E0000009","errorSummary":"Internal Server Error"}`))
+	}))
+	defer done()
+
+	_, err := client.User("00u1")
+	if !IsRetryable(err) || ErrorCode(err) != "E0000009" || ClassOf(err).Kind != ErrorServer {
+		t.Errorf("Expected retryable server error, got %+v", ClassOf(err))
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	var correlation string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlation = r.Header.Get("X-Correlation-Id")
+		w.Header().Set("X-Okta-Request-Id", "XkbvTKzLB0ZmjPJdhqGAHQAABqA")
+		if r.URL.Path == "/api/v1/users/00u404" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"00u1"}`))
+	}))
+	defer done()
+
+	_, err := client.User("00u404")
+	if RequestIDOf(err) != "XkbvTKzLB0ZmjPJdhqGAHQAABqA" || !strings.Contains(err.Error(), "XkbvTKzLB0ZmjPJdhqGAHQAABqA") {
+		t.Error("Expected the request id in the error, got ", err)
+	}
+
+	meta := &ResponseMeta{}
+	ctx := WithCallOptions(context.Background(), WithResponseMeta(meta), WithCorrelationID("job-42"))
+	if _, err := client.UserContext(ctx, "00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if meta.RequestID != "XkbvTKzLB0ZmjPJdhqGAHQAABqA" || meta.StatusCode != http.StatusOK {
+		t.Errorf("Expected the response metadata, got %+v", meta)
+	}
+	if correlation != "job-42" {
+		t.Error("Expected the correlation id to be sent, got ", correlation)
+	}
+}
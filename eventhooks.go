@@ -0,0 +1,318 @@
+package okta
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type EventHook struct {
+	ID                 string     `json:"id,omitempty"`
+	Name               string     `json:"name"`
+	Status             string     `json:"status,omitempty"`
+	VerificationStatus string     `json:"verificationStatus,omitempty"`
+	Created            *time.Time `json:"created,omitempty"`
+	CreatedBy          string     `json:"createdBy,omitempty"`
+	LastUpdated        *time.Time `json:"lastUpdated,omitempty"`
+	Events             struct {
+		Type  string   `json:"type"`
+		Items []string `json:"items"`
+	} `json:"events"`
+	Channel struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+		Config  struct {
+			URI     string `json:"uri"`
+			Headers []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"headers,omitempty"`
+			AuthScheme *EventHookAuthScheme `json:"authScheme,omitempty"`
+		} `json:"config"`
+	} `json:"channel"`
+	// Unknown are the attributes of the hook without a field, see
+	// UnknownFields
+	Unknown UnknownFields `json:"-"`
+}
+
+// EventHookAuthScheme is the header Okta sends with every delivery, the
+// value is write only and never returned by the API
+type EventHookAuthScheme struct {
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// NewEventHook returns an event hook subscribed to the given event types
+// that delivers to uri, authenticated with secret in the Authorization header.
+func NewEventHook(name, uri, secret string, events ...string) *EventHook {
+	hook := &EventHook{Name: name}
+	hook.Events.Type = "EVENT_TYPE"
+	hook.Events.Items = events
+	hook.Channel.Type = "HTTP"
+	hook.Channel.Version = "1.0.0"
+	hook.Channel.Config.URI = uri
+	if secret != "" {
+		hook.Channel.Config.AuthScheme = &EventHookAuthScheme{
+			Type:  "HEADER",
+			Key:   "Authorization",
+			Value: secret,
+		}
+	}
+	return hook
+}
+
+// ListEventHooks returns all event hooks registered in the org
+func (c *Client) ListEventHooks() (*[]EventHook, error) {
+	var response = &[]EventHook{}
+	err := c.listAll("eventHooks", response)
+	return response, err
+}
+
+// EventHook takes an event hook id and returns it
+func (c *Client) EventHook(hookID string) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID), "GET", nil, response)
+	return response, err
+}
+
+// CreateEventHook registers a new event hook, it must be verified before
+// Okta starts delivering events to it
+func (c *Client) CreateEventHook(hook *EventHook) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks", "POST", hook, response)
+	return response, err
+}
+
+// UpdateEventHook replaces the event hook with the given id
+func (c *Client) UpdateEventHook(hookID string, hook *EventHook) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID), "PUT", hook, response)
+	return response, err
+}
+
+// DeleteEventHook deletes a deactivated event hook
+func (c *Client) DeleteEventHook(hookID string) error {
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID), "DELETE", nil, nil)
+	return err
+}
+
+// ActivateEventHook resumes event delivery to the hook
+func (c *Client) ActivateEventHook(hookID string) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID)+"/lifecycle/activate", "POST", nil, response)
+	return response, err
+}
+
+// DeactivateEventHook stops event delivery to the hook
+func (c *Client) DeactivateEventHook(hookID string) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID)+"/lifecycle/deactivate", "POST", nil, response)
+	return response, err
+}
+
+// VerifyEventHook asks Okta to send the one-time verification challenge to
+// the hook endpoint, see EventHookHandler for the receiving side
+func (c *Client) VerifyEventHook(hookID string) (*EventHook, error) {
+	var response = &EventHook{}
+	err, _ := c.call("eventHooks/"+url.PathEscape(hookID)+"/lifecycle/verify", "POST", nil, response)
+	return response, err
+}
+
+// EventHookHandler is an http.Handler for an event hook endpoint. It answers
+// Okta's one-time verification challenge and only passes deliveries carrying
+// the configured secret on to Handler. Events of every delivery are also
+// published to watchers when it is set as Client.HookReceiver.
+type EventHookHandler struct {
+	// Header carrying the secret, Authorization unless changed
+	Header  string
+	Secret  string
+	Handler http.Handler
+
+	mu          sync.Mutex
+	subscribers map[*hookSubscriber]struct{}
+}
+
+type hookSubscriber struct {
+	events chan LogEvent
+	done   chan struct{}
+}
+
+// NewEventHookHandler returns an EventHookHandler checking the Authorization
+// header against secret before calling next
+func NewEventHookHandler(secret string, next http.Handler) *EventHookHandler {
+	return &EventHookHandler{
+		Header:  "Authorization",
+		Secret:  secret,
+		Handler: next,
+	}
+}
+
+func (h *EventHookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := VerifyEventHookSecret(r, h.Header, h.Secret); err != nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// https://developer.okta.com/docs/concepts/event-hooks/#one-time-verification-request
+	if challenge := r.Header.Get("X-Okta-Verification-Challenge"); r.Method == http.MethodGet && challenge != "" {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"verification": challenge,
+		})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.hasSubscribers() {
+		delivery, err := ParseEventHookDelivery(r)
+		if err == errReadingDelivery {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if err == nil {
+			h.publish(r, delivery.Data.Events)
+		}
+	}
+
+	if h.Handler == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	h.Handler.ServeHTTP(w, r)
+}
+
+// EventHookDelivery is the body of a request of Okta to an event hook, a
+// batch of System Log events
+type EventHookDelivery struct {
+	// EventType is com.okta.event_hook
+	EventType          string     `json:"eventType"`
+	EventTypeVersion   string     `json:"eventTypeVersion"`
+	CloudEventsVersion string     `json:"cloudEventsVersion"`
+	Source             string     `json:"source"`
+	EventID            string     `json:"eventId"`
+	EventTime          *time.Time `json:"eventTime"`
+	ContentType        string     `json:"contentType"`
+	Data               struct {
+		Events []LogEvent `json:"events"`
+	} `json:"data"`
+}
+
+var errReadingDelivery = errors.New("okta: reading the event hook delivery failed")
+
+// ParseEventHookDelivery decodes the delivery in the body of r, the body is
+// left for handlers to read again. It doesn't check the secret, see
+// VerifyEventHookSecret.
+func ParseEventHookDelivery(r *http.Request) (*EventHookDelivery, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errReadingDelivery
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	delivery := &EventHookDelivery{}
+	if err := json.Unmarshal(body, delivery); err != nil {
+		return nil, fmt.Errorf("okta: event hook delivery: %v", err)
+	}
+	return delivery, nil
+}
+
+// VerifyEventHookSecret checks in constant time that r carries secret in
+// header, Authorization when empty. Okta doesn't sign event hook
+// deliveries, the secret configured in the auth scheme of the hook is all
+// there is to authenticate them. An empty secret never matches.
+func VerifyEventHookSecret(r *http.Request, header, secret string) error {
+	if header == "" {
+		header = "Authorization"
+	}
+	if secret == "" {
+		return errors.New("okta: no event hook secret configured")
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(header)), []byte(secret)) != 1 {
+		return fmt.Errorf("okta: the %s header of the event hook delivery doesn't match", header)
+	}
+	return nil
+}
+
+// EventHookFunc is an http.Handler decoding deliveries for a function, e.g.
+// the Handler of an EventHookHandler. Okta retries a delivery once when
+// the function fails.
+type EventHookFunc func(r *http.Request, delivery *EventHookDelivery) error
+
+func (f EventHookFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	delivery, err := ParseEventHookDelivery(r)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if err := f(r, delivery); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *EventHookHandler) hasSubscribers() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers) > 0
+}
+
+// subscribe registers for the events of future deliveries until the
+// returned function is called
+func (h *EventHookHandler) subscribe() (<-chan LogEvent, func()) {
+	sub := &hookSubscriber{
+		events: make(chan LogEvent, 64),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	if h.subscribers == nil {
+		h.subscribers = map[*hookSubscriber]struct{}{}
+	}
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, sub)
+			h.mu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+// publish hands events to every subscriber, giving up on a subscriber when
+// it unsubscribes or Okta abandons the delivery
+func (h *EventHookHandler) publish(r *http.Request, events []LogEvent) {
+	h.mu.Lock()
+	subscribers := make([]*hookSubscriber, 0, len(h.subscribers))
+	for sub := range h.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	h.mu.Unlock()
+
+	for _, sub := range subscribers {
+		for _, event := range events {
+			select {
+			case sub.events <- event:
+			case <-sub.done:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
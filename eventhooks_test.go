@@ -0,0 +1,123 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventHookHandlerVerification(t *testing.T) {
+	handler := NewEventHookHandler("secret", nil)
+
+	req := httptest.NewRequest("GET", "/hook", nil)
+	req.Header.Set("Authorization", "secret")
+	req.Header.Set("X-Okta-Verification-Challenge", "challenge-value")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Error("Expected 200, got ", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `"verification":"challenge-value"`) {
+		t.Error("Expected verification echo, got ", rec.Body.String())
+	}
+}
+
+func TestEventHookHandlerAuthorization(t *testing.T) {
+	called := false
+	handler := NewEventHookHandler("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "wrong")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || called {
+		t.Error("Expected 401 without calling handler, got ", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !called {
+		t.Error("Expected handler to be called for an authorized delivery")
+	}
+}
+
+func TestWatchEventHooks(t *testing.T) {
+	client := NewClient("organization")
+	client.HookReceiver = NewEventHookHandler("secret", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher := client.Watch(ctx, "User", "user.lifecycle")
+
+	// Watch subscribes asynchronously
+	for !client.HookReceiver.hasSubscribers() {
+		time.Sleep(time.Millisecond)
+	}
+
+	go func() {
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{"data":{"events":[
+			{"uuid":"1","eventType":"group.user_membership.add","target":[{"id":"00u1","type":"User"}]},
+			{"uuid":"2","eventType":"user.lifecycle.create","target":[{"id":"00u2","type":"User"}]}
+		]}}`))
+		req.Header.Set("Authorization", "secret")
+		client.HookReceiver.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	change := <-watcher.C
+	if change.ResourceID != "00u2" || change.EventType != "user.lifecycle.create" {
+		t.Errorf("Unexpected change %+v", change)
+	}
+
+	cancel()
+	for range watcher.C {
+	}
+	if watcher.Err() != context.Canceled {
+		t.Error("Expected context.Canceled, got ", watcher.Err())
+	}
+}
+
+func TestEventHookFunc(t *testing.T) {
+	var received *EventHookDelivery
+	handler := NewEventHookHandler("secret", EventHookFunc(func(r *http.Request, delivery *EventHookDelivery) error {
+		received = delivery
+		return nil
+	}))
+
+	req := httptest.NewRequest("POST", "/hook", strings.NewReader(`{
+		"eventType": "com.okta.event_hook",
+		"eventTypeVersion": "1.0",
+		"eventId": "b5a188b9-5ece-4636-b041-482ffda96311",
+		"eventTime": "2024-08-13T15:58:20.000Z",
+		"data": {"events": [{"uuid": "e1", "eventType": "user.session.start", "actor": {"alternateId": "jane@example.com"}}]}
+	}`))
+	req.Header.Set("Authorization", "secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || received == nil {
+		t.Fatal("Expected the delivery to be handled, got ", rec.Code)
+	}
+	if received.EventID != "b5a188b9-5ece-4636-b041-482ffda96311" || len(received.Data.Events) != 1 || !received.Data.Events[0].IsLogin() {
+		t.Errorf("Expected the typed delivery, got %+v", received)
+	}
+}
+
+func TestVerifyEventHookSecret(t *testing.T) {
+	req := httptest.NewRequest("POST", "/hook", nil)
+	if err := VerifyEventHookSecret(req, "", ""); err == nil {
+		t.Error("Expected an empty secret to never match")
+	}
+	req.Header.Set("X-Hook-Secret", "secret")
+	if err := VerifyEventHookSecret(req, "X-Hook-Secret", "secret"); err != nil {
+		t.Error(err)
+	}
+	if err := VerifyEventHookSecret(req, "", "secret"); err == nil {
+		t.Error("Expected a delivery without an Authorization header to be rejected")
+	}
+}
@@ -11,14 +11,61 @@ import (
 	"time"
 )
 
+// FactorType is the kind of a factor
+type FactorType string
+
+// https://developer.okta.com/docs/reference/api/factors/#factor-type
+const (
+	FactorCall          FactorType = "call"
+	FactorEmail         FactorType = "email"
+	FactorPush          FactorType = "push"
+	FactorQuestion      FactorType = "question"
+	FactorSMS           FactorType = "sms"
+	FactorToken         FactorType = "token"
+	FactorHardwareToken FactorType = "token:hardware"
+	FactorHOTP          FactorType = "token:hotp"
+	FactorTOTP          FactorType = "token:software:totp"
+	FactorU2F           FactorType = "u2f"
+	FactorWeb           FactorType = "web"
+	FactorWebAuthn      FactorType = "webauthn"
+)
+
+// IsToken tells whether factors of the type are verified with a passcode
+func (t FactorType) IsToken() bool {
+	return strings.HasPrefix(string(t), string(FactorToken))
+}
+
+// FactorProvider is the vendor of a factor
+type FactorProvider string
+
+// https://developer.okta.com/docs/reference/api/factors/#provider-type
+const (
+	FactorProviderOkta     FactorProvider = "OKTA"
+	FactorProviderGoogle   FactorProvider = "GOOGLE"
+	FactorProviderRSA      FactorProvider = "RSA"
+	FactorProviderSymantec FactorProvider = "SYMANTEC"
+	FactorProviderYubico   FactorProvider = "YUBICO"
+	FactorProviderDuo      FactorProvider = "DUO"
+	FactorProviderFIDO     FactorProvider = "FIDO"
+	FactorProviderCustom   FactorProvider = "CUSTOM"
+)
+
 type Factor struct {
-	ID         string `json:"id"`
-	FactorType string `json:"factorType"`
-	Provider   string `json:"provider"`
-	VendorName string `json:"vendorName"`
+	ID         string         `json:"id"`
+	FactorType FactorType     `json:"factorType"`
+	Provider   FactorProvider `json:"provider"`
+	VendorName string         `json:"vendorName"`
 	Profile    struct {
 		CredentialID string `json:"credentialId"`
+		// AuthenticatorName is set for WebAuthn factors
+		AuthenticatorName string `json:"authenticatorName"`
 	} `json:"profile"`
+	Embedded struct {
+		// Activation is set for WebAuthn factors in MFA_ENROLL_ACTIVATE
+		Activation *WebAuthnActivation `json:"activation"`
+		// TOTP is set for TOTP factors in MFA_ENROLL_ACTIVATE
+		TOTP *TOTPActivation `json:"-"`
+	} `json:"_embedded"`
 	Links struct {
 		Verify struct {
 			Href  string `json:"href"`
@@ -26,6 +73,9 @@ type Factor struct {
 				Allow []string `json:"allow"`
 			} `json:"hints"`
 		} `json:"verify"`
+		Enroll struct {
+			Href string `json:"href"`
+		} `json:"enroll"`
 	} `json:"_links"`
 }
 
@@ -34,7 +84,7 @@ func (r *AuthnResponse) GetSupportedFactors() []Factor {
 
 	for _, v := range r.Embedded.Factors {
 		postAllowed := false
-		if strings.HasPrefix(v.FactorType, "token") || v.FactorType == "push" {
+		if v.FactorType.IsToken() || v.FactorType == FactorPush || v.FactorType == FactorWebAuthn {
 			for _, verb := range v.Links.Verify.Hints.Allow {
 				if verb == "POST" {
 					postAllowed = true
@@ -53,52 +103,15 @@ func (r *AuthnResponse) GetSupportedFactors() []Factor {
 // https://developer.okta.com/docs/api/resources/factors#verify-totp-factor
 // https://developer.okta.com/docs/api/resources/factors#verify-token-factor
 func (f Factor) VerifyOTP(stateToken string, code string) (*AuthnResponse, error) {
-	if !strings.HasPrefix(f.FactorType, "token") {
+	if !f.FactorType.IsToken() {
 		return nil, fmt.Errorf(
 			"can not VerifyOTP on a factor type of %s", f.FactorType)
 	}
 
-	data, _ := json.Marshal(map[string]string{
+	return postAuthn(f.Links.Verify.Href, map[string]interface{}{
 		"passCode":   code,
 		"stateToken": stateToken,
 	})
-	req, err := http.NewRequest("POST",
-		f.Links.Verify.Href, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-
-	client := http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errors ErrorResponse
-		_ = json.Unmarshal(body, &errors)
-		return nil, &errorResponse{
-			HTTPCode: resp.StatusCode,
-			Response: errors,
-		}
-	}
-
-	var verifyResp AuthnResponse
-	err = json.Unmarshal(body, &verifyResp)
-	if err != nil {
-		return nil, err
-	}
-
-	return &verifyResp, nil
 }
 
 // https://developer.okta.com/docs/api/resources/factors#verify-push-factor
@@ -109,7 +122,7 @@ func (f Factor) VerifyPush(
 	userAgent string,
 	pollInterval time.Duration,
 	pollTimeout time.Duration) (*AuthnResponse, error) {
-	if f.FactorType != "push" {
+	if f.FactorType != FactorPush {
 		return nil, fmt.Errorf(
 			"can not VerifyPush on a factor type of %s", f.FactorType)
 	}
@@ -147,8 +160,10 @@ func (f Factor) VerifyPush(
 		var errors ErrorResponse
 		_ = json.Unmarshal(body, &errors)
 		return nil, &errorResponse{
-			HTTPCode: resp.StatusCode,
-			Response: errors,
+			HTTPCode:  resp.StatusCode,
+			RequestID: resp.Header.Get("X-Okta-Request-Id"),
+			Response:  errors,
+			Class:     DefaultErrorMap.Classify(resp.StatusCode, errors.ErrorCode),
 		}
 	}
 
@@ -205,8 +220,10 @@ func pollPushResult(
 			var errors ErrorResponse
 			_ = json.Unmarshal(body, &errors)
 			return nil, &errorResponse{
-				HTTPCode: resp.StatusCode,
-				Response: errors,
+				HTTPCode:  resp.StatusCode,
+				RequestID: resp.Header.Get("X-Okta-Request-Id"),
+				Response:  errors,
+				Class:     DefaultErrorMap.Classify(resp.StatusCode, errors.ErrorCode),
 			}
 		}
 
@@ -218,3 +235,145 @@ func pollPushResult(
 
 	return &pushResult, nil
 }
+
+// PushResult is the outcome of a push challenge
+type PushResult string
+
+const (
+	PushApproved PushResult = "APPROVED"
+	PushRejected PushResult = "REJECTED"
+	PushTimeout  PushResult = "TIMEOUT"
+)
+
+// PushVerification is the outcome of a push challenge together with the
+// transaction state it left, Response.SessionToken is set when approved
+type PushVerification struct {
+	Result   PushResult
+	Response *AuthnResponse
+}
+
+// VerifyPush sends a push challenge to the first Okta Verify factor of an
+// MFA_REQUIRED transaction and polls every interval until the user approves
+// or rejects it, or the challenge times out. Timeouts of Okta and of the
+// given timeout are both returned as PushTimeout.
+func (r *AuthnResponse) VerifyPush(
+	userAgent string,
+	pollInterval time.Duration,
+	pollTimeout time.Duration) (*PushVerification, error) {
+	var push *Factor
+	for i, factor := range r.Embedded.Factors {
+		if factor.FactorType == FactorPush {
+			push = &r.Embedded.Factors[i]
+			break
+		}
+	}
+	if push == nil {
+		return nil, errors.New("no push factor enrolled")
+	}
+
+	resp, err := push.VerifyPush(r.StateToken, userAgent, pollInterval, pollTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &PushVerification{Response: resp}
+	switch {
+	case resp.Status == AuthnSuccess:
+		verification.Result = PushApproved
+	case resp.FactorResult == string(PushRejected):
+		verification.Result = PushRejected
+	default:
+		// TIMEOUT from Okta or still WAITING when pollTimeout passed
+		verification.Result = PushTimeout
+	}
+	return verification, nil
+}
+
+// FactorResendCooldown is how long Okta makes a user wait before the code
+// of an SMS or voice call challenge can be sent again
+const FactorResendCooldown = 30 * time.Second
+
+// ResendCooldownError is returned by ResendFactorChallenge for a challenge
+// sent less than FactorResendCooldown ago
+type ResendCooldownError struct {
+	// RetryAfter is when the challenge can be resent
+	RetryAfter time.Time
+	// Err is the error of Okta when it rejected the resend, nil when the
+	// request wasn't sent
+	Err error
+}
+
+func (e *ResendCooldownError) Error() string {
+	return fmt.Sprintf("okta: the challenge can be resent after %s", e.RetryAfter.Format(time.RFC3339))
+}
+
+func (e *ResendCooldownError) Unwrap() error {
+	return e.Err
+}
+
+// Wait is how long until the challenge can be resent, e.g. for a countdown
+func (e *ResendCooldownError) Wait() time.Duration {
+	if wait := time.Until(e.RetryAfter); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// resendTooSoon are the errors of Okta for challenges resent before
+// FactorResendCooldown passed, for SMS and voice calls
+var resendTooSoon = map[string]bool{
+	"E0000109": true,
+	"E0000133": true,
+}
+
+// Challenge sends the code of an SMS, voice call or email factor, the
+// returned MFA_CHALLENGE transaction takes the code with Next
+// https://developer.okta.com/docs/reference/api/authn/#verify-sms-factor
+func (f Factor) Challenge(stateToken string) (*AuthnResponse, error) {
+	if f.FactorType != FactorSMS && f.FactorType != FactorCall && f.FactorType != FactorEmail {
+		return nil, fmt.Errorf(
+			"can not Challenge a factor type of %s", f.FactorType)
+	}
+
+	resp, err := postAuthn(f.Links.Verify.Href, map[string]interface{}{
+		"stateToken": stateToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.challenged = time.Now()
+	return resp, nil
+}
+
+// ResendAfter is when the challenge of an MFA_CHALLENGE transaction can be
+// resent, the zero time when it can be resent right away or it is not
+// known when it was sent
+func (r *AuthnResponse) ResendAfter() time.Time {
+	if r.challenged.IsZero() {
+		return time.Time{}
+	}
+	return r.challenged.Add(FactorResendCooldown)
+}
+
+// ResendFactorChallenge sends the code of the challenge of an
+// MFA_CHALLENGE transaction again. Before ResendAfter it returns a
+// ResendCooldownError without calling Okta, as it does when Okta rejects
+// the resend as too early.
+func (r *AuthnResponse) ResendFactorChallenge() (*AuthnResponse, error) {
+	if r.Status != AuthnMFAChallenge || len(r.Links.Resend) == 0 {
+		return nil, fmt.Errorf("can not resend a challenge in %s", r.Status)
+	}
+	if after := r.ResendAfter(); time.Now().Before(after) {
+		return nil, &ResendCooldownError{RetryAfter: after}
+	}
+
+	resp, err := r.follow(r.Links.Resend[0].Href, nil)
+	if err != nil {
+		if resendTooSoon[ErrorCode(err)] || ClassOf(err).Kind == ErrorRateLimited {
+			return nil, &ResendCooldownError{RetryAfter: time.Now().Add(FactorResendCooldown), Err: err}
+		}
+		return nil, err
+	}
+	resp.challenged = time.Now()
+	return resp, nil
+}
@@ -0,0 +1,261 @@
+package okta
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyPush(t *testing.T) {
+	polls := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			w.Write([]byte(`{"stateToken":"state","status":"MFA_CHALLENGE","factorResult":"WAITING",
+				"_links":{"next":{"name":"poll","href":"` + server.URL + `/poll"}}}`))
+			return
+		}
+		w.Write([]byte(`{"stateToken":"state","status":"MFA_CHALLENGE","factorResult":"REJECTED"}`))
+	}))
+	defer server.Close()
+
+	transaction := &AuthnResponse{StateToken: "state", Status: AuthnMFARequired}
+	if _, err := transaction.VerifyPush("test", time.Millisecond, time.Second); err == nil {
+		t.Error("Expected an error without a push factor")
+	}
+
+	factor := Factor{FactorType: "push"}
+	factor.Links.Verify.Href = server.URL + "/verify"
+	transaction.Embedded.Factors = []Factor{factor}
+
+	verification, err := transaction.VerifyPush("test", time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.Result != PushRejected || polls != 3 {
+		t.Error("Expected the push to be rejected on the second poll, got ", verification.Result, polls)
+	}
+
+	polls = -100
+	verification, err = transaction.VerifyPush("test", time.Millisecond, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.Result != PushTimeout {
+		t.Error("Expected the push to time out, got ", verification.Result)
+	}
+}
+
+func TestResendFactorChallenge(t *testing.T) {
+	var requests []string
+	tooSoon := true
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Path == "/verify/resend" && tooSoon {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errorCode":"E0000109","errorSummary":"An SMS message was recently sent. Please wait 30 seconds before trying again."}`))
+			return
+		}
+		w.Write([]byte(`{"stateToken":"state","status":"MFA_CHALLENGE",
+			"_links":{"next":{"name":"verify","href":"` + server.URL + `/verify"},"resend":[{"name":"sms","href":"` + server.URL + `/verify/resend"}]}}`))
+	}))
+	defer server.Close()
+
+	factor := Factor{FactorType: FactorSMS}
+	factor.Links.Verify.Href = server.URL + "/verify"
+	if _, err := (Factor{FactorType: FactorPush}).Challenge("state"); err == nil {
+		t.Error("Expected an error for a push factor")
+	}
+	challenge, err := factor.Challenge("state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait := time.Until(challenge.ResendAfter()); wait < 29*time.Second || wait > FactorResendCooldown {
+		t.Error("Expected the challenge to be resendable in 30 seconds, got ", wait)
+	}
+
+	// a wrong passcode doesn't send the challenge again
+	retry, err := challenge.Next(map[string]interface{}{"passCode": "000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = retry.ResendFactorChallenge()
+	cooldown, ok := err.(*ResendCooldownError)
+	if !ok || cooldown.Err != nil || cooldown.Wait() == 0 || len(requests) != 2 {
+		t.Fatal("Expected the cooldown without calling Okta, got ", err, requests)
+	}
+
+	retry.challenged = time.Now().Add(-FactorResendCooldown)
+	_, err = retry.ResendFactorChallenge()
+	if cooldown, ok := err.(*ResendCooldownError); !ok || ErrorCode(cooldown.Err) != "E0000109" || cooldown.Wait() < 29*time.Second {
+		t.Fatal("Expected the cooldown of Okta, got ", err)
+	}
+
+	tooSoon = false
+	resent, err := retry.ResendFactorChallenge()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resent.ResendAfter().After(time.Now()) || len(requests) != 4 {
+		t.Error("Expected the challenge to be resent, got ", requests)
+	}
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	var bodies []map[string]interface{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/factors" {
+			w.Write([]byte(`{"stateToken":"state","status":"MFA_ENROLL_ACTIVATE",
+				"_embedded":{"factor":{"id":"ostf1","factorType":"token:software:totp","provider":"GOOGLE",
+					"_embedded":{"activation":{"timeStep":30,"sharedSecret":"JBSWY3DPEHPK3PXP","encoding":"base32","keyLength":16,
+						"_links":{"qrcode":{"href":"` + server.URL + `/qr.png","type":"image/png"}}}}}},
+				"_links":{"next":{"name":"activate","href":"` + server.URL + `/activate"}}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"SUCCESS","sessionToken":"token"}`))
+	}))
+	defer server.Close()
+
+	transaction := &AuthnResponse{StateToken: "state", Status: AuthnMFAEnroll}
+	okta := Factor{FactorType: FactorTOTP, Provider: FactorProviderOkta}
+	okta.Links.Enroll.Href = server.URL + "/factors"
+	transaction.Embedded.Factors = []Factor{okta}
+	if _, err := transaction.EnrollTOTP(FactorProviderGoogle); err == nil {
+		t.Error("Expected an error without a Google Authenticator factor")
+	}
+
+	google := okta
+	google.Provider = FactorProviderGoogle
+	transaction.Embedded.Factors = append(transaction.Embedded.Factors, google)
+	activate, err := transaction.EnrollTOTP(FactorProviderGoogle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bodies[0]["provider"] != "GOOGLE" || bodies[0]["factorType"] != "token:software:totp" || bodies[0]["stateToken"] != "state" {
+		t.Error("Unexpected enrollment ", bodies[0])
+	}
+	totp := activate.Embedded.Factor.Embedded.TOTP
+	if totp == nil || totp.SharedSecret != "JBSWY3DPEHPK3PXP" || totp.Links.QRCode.Href != server.URL+"/qr.png" {
+		t.Fatal("Expected the shared secret and QR code, got ", totp)
+	}
+	if activate.Embedded.Factor.Embedded.Activation != nil {
+		t.Error("Expected no WebAuthn activation for a TOTP factor")
+	}
+	if uri := totp.URI("Example", "jane@example.com"); uri != "otpauth://totp/Example:jane@example.com?issuer=Example&period=30&secret=JBSWY3DPEHPK3PXP" {
+		t.Error("Unexpected key URI ", uri)
+	}
+
+	success, err := activate.ActivateTOTP("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success.Status != AuthnSuccess || bodies[1]["passCode"] != "123456" || bodies[1]["stateToken"] != "state" {
+		t.Error("Unexpected activation ", success.Status, bodies[1])
+	}
+}
+
+func TestEnrollWebAuthn(t *testing.T) {
+	var bodies []map[string]interface{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/factors" {
+			w.Write([]byte(`{"stateToken":"state","status":"MFA_ENROLL_ACTIVATE",
+				"_embedded":{"factor":{"id":"fwf1","factorType":"webauthn","provider":"FIDO",
+					"_embedded":{"activation":{"challenge":"cdsZ1V10E0BGE9GcG3IK","rp":{"name":"Example"},
+						"user":{"id":"00u1","name":"jane@example.com","displayName":"Jane Doe"},
+						"pubKeyCredParams":[{"type":"public-key","alg":-7}],"attestation":"direct",
+						"authenticatorSelection":{"userVerification":"preferred","requireResidentKey":false},
+						"excludeCredentials":[{"type":"public-key","id":"cred1"}]}}}},
+				"_links":{"next":{"name":"activate","href":"` + server.URL + `/activate"}}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"SUCCESS","sessionToken":"token"}`))
+	}))
+	defer server.Close()
+
+	transaction := &AuthnResponse{StateToken: "state", Status: AuthnMFAEnroll}
+	if _, err := transaction.EnrollWebAuthn(); err == nil {
+		t.Error("Expected an error without a WebAuthn factor")
+	}
+	webauthn := Factor{FactorType: FactorWebAuthn, Provider: "FIDO"}
+	webauthn.Links.Enroll.Href = server.URL + "/factors"
+	transaction.Embedded.Factors = []Factor{webauthn}
+
+	activate, err := transaction.EnrollWebAuthn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bodies[0]["factorType"] != "webauthn" || bodies[0]["provider"] != "FIDO" || bodies[0]["stateToken"] != "state" {
+		t.Error("Unexpected enrollment ", bodies[0])
+	}
+	options := activate.Embedded.Factor.Embedded.Activation
+	if options == nil || options.Challenge != "cdsZ1V10E0BGE9GcG3IK" || options.User.Name != "jane@example.com" ||
+		len(options.PubKeyCredParams) != 1 || options.PubKeyCredParams[0].Alg != -7 || len(options.ExcludeCredentials) != 1 {
+		t.Fatalf("Expected the creation options, got %+v", options)
+	}
+
+	success, err := activate.ActivateWebAuthn(WebAuthnAttestation{Attestation: "o2NmbXRm", ClientData: "eyJjaGFsbGVuZ2Ui"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success.Status != AuthnSuccess || bodies[1]["attestation"] != "o2NmbXRm" || bodies[1]["clientData"] != "eyJjaGFsbGVuZ2Ui" {
+		t.Error("Unexpected activation ", success.Status, bodies[1])
+	}
+	if _, err := success.ActivateWebAuthn(WebAuthnAttestation{}); err == nil {
+		t.Error("Expected an error activating in SUCCESS")
+	}
+}
+
+func TestVerifyWebAuthn(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		if body["signatureData"] == nil {
+			w.Write([]byte(`{"stateToken":"state","status":"MFA_CHALLENGE",
+				"_embedded":{"challenge":{"challenge":"vygOogy8zMNZWLdTiSOz","extensions":{"appid":"https://example.okta.com"}}}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"SUCCESS","sessionToken":"token"}`))
+	}))
+	defer server.Close()
+
+	factor := Factor{FactorType: FactorWebAuthn}
+	factor.Links.Verify.Href = server.URL + "/verify"
+	challenge, err := factor.ChallengeWebAuthn("state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.Embedded.Challenge == nil || challenge.Embedded.Challenge.Challenge != "vygOogy8zMNZWLdTiSOz" {
+		t.Fatal("Expected the challenge, got ", challenge.Embedded.Challenge)
+	}
+
+	success, err := factor.VerifyWebAuthn("state", WebAuthnAssertion{ClientData: "client", AuthenticatorData: "authenticator", SignatureData: "signature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success.Status != AuthnSuccess || bodies[1]["stateToken"] != "state" || bodies[1]["authenticatorData"] != "authenticator" || bodies[1]["signatureData"] != "signature" {
+		t.Error("Unexpected verification ", success.Status, bodies[1])
+	}
+
+	sms := Factor{FactorType: "sms"}
+	if _, err := sms.ChallengeWebAuthn("state"); err == nil {
+		t.Error("Expected an error challenging an SMS factor")
+	}
+}
@@ -0,0 +1,78 @@
+package okta
+
+import (
+	"net/url"
+)
+
+// Feature states
+const (
+	FeatureEnabled  = "ENABLED"
+	FeatureDisabled = "DISABLED"
+)
+
+// Feature is a self-service Early Access or Beta feature of the org
+type Feature struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	Stage       struct {
+		// Value is EA or BETA, State OPEN or CLOSED
+		Value string `json:"value"`
+		State string `json:"state"`
+	} `json:"stage"`
+}
+
+// ListFeatures returns the self-service features of the org
+func (c *Client) ListFeatures() (*[]Feature, error) {
+	var response = &[]Feature{}
+	err := c.listAll("features", response)
+	return response, err
+}
+
+// GetFeature returns the feature with the given id
+func (c *Client) GetFeature(featureID string) (*Feature, error) {
+	var response = &Feature{}
+	err, _ := c.call("features/"+url.PathEscape(featureID), "GET", nil, response)
+	return response, err
+}
+
+// FeatureDependencies returns the features that have to be enabled for the
+// feature with the given id
+func (c *Client) FeatureDependencies(featureID string) (*[]Feature, error) {
+	var response = &[]Feature{}
+	err, _ := c.call("features/"+url.PathEscape(featureID)+"/dependencies", "GET", nil, response)
+	return response, err
+}
+
+// FeatureDependents returns the features depending on the feature with the
+// given id
+func (c *Client) FeatureDependents(featureID string) (*[]Feature, error) {
+	var response = &[]Feature{}
+	err, _ := c.call("features/"+url.PathEscape(featureID)+"/dependents", "GET", nil, response)
+	return response, err
+}
+
+// EnableFeature enables a feature, with force its dependencies are enabled
+// too rather than the call failing
+func (c *Client) EnableFeature(featureID string, force bool) (*Feature, error) {
+	return c.featureLifecycle(featureID, "enable", force)
+}
+
+// DisableFeature disables a feature, with force its dependents are
+// disabled too rather than the call failing
+func (c *Client) DisableFeature(featureID string, force bool) (*Feature, error) {
+	return c.featureLifecycle(featureID, "disable", force)
+}
+
+func (c *Client) featureLifecycle(featureID, lifecycle string, force bool) (*Feature, error) {
+	endpoint := "features/" + url.PathEscape(featureID) + "/" + lifecycle
+	if force {
+		endpoint += "?mode=force"
+	}
+
+	var response = &Feature{}
+	err, _ := c.call(endpoint, "POST", nil, response)
+	return response, err
+}
@@ -0,0 +1,39 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFeatureLifecycle(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/features":
+			w.Write([]byte(`[{"id":"ftrZooGyvfKjHJ42mmad","name":"Event Hooks","type":"self-service","status":"DISABLED","stage":{"value":"EA"}}]`))
+		case "/api/v1/features/ftrZooGyvfKjHJ42mmad/enable":
+			w.Write([]byte(`{"id":"ftrZooGyvfKjHJ42mmad","status":"ENABLED"}`))
+		default:
+			w.Write([]byte(`{"id":"ftrZooGyvfKjHJ42mmad","status":"DISABLED"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	features, err := client.ListFeatures()
+	if err != nil || len(*features) != 1 || (*features)[0].Stage.Value != "EA" {
+		t.Fatal("Expected the feature, got ", features, err)
+	}
+	feature, err := client.EnableFeature("ftrZooGyvfKjHJ42mmad", true)
+	if err != nil || feature.Status != FeatureEnabled {
+		t.Error("Expected the feature to be enabled, got ", feature, err)
+	}
+	if _, err := client.DisableFeature("ftrZooGyvfKjHJ42mmad", false); err != nil {
+		t.Error(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/features",
+		"POST /api/v1/features/ftrZooGyvfKjHJ42mmad/enable?mode=force",
+		"POST /api/v1/features/ftrZooGyvfKjHJ42mmad/disable",
+	)
+}
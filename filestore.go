@@ -0,0 +1,172 @@
+package okta
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fileStoreAAD binds the ciphertext to this file format
+var fileStoreAAD = []byte("go-okta encrypted store v1")
+
+// KeyProvider supplies the 32 byte key of an EncryptedFileStore
+type KeyProvider interface {
+	Key() ([]byte, error)
+}
+
+// StaticKey is a KeyProvider for a key managed by the caller
+type StaticKey []byte
+
+func (k StaticKey) Key() ([]byte, error) {
+	return k, nil
+}
+
+// EncryptedFileStore is a Store keeping all values in a single file
+// encrypted with AES-256-GCM, meant for CLI session and refresh tokens on
+// shared machines. The key should come from the OS keychain, see
+// KeychainKey. It is safe for concurrent use within one process.
+type EncryptedFileStore struct {
+	path string
+	keys KeyProvider
+	mu   sync.Mutex
+}
+
+// NewEncryptedFileStore returns a store at path, the file and its directory
+// are created on the first Set with permissions for the current user only
+func NewEncryptedFileStore(path string, keys KeyProvider) *EncryptedFileStore {
+	return &EncryptedFileStore{path: path, keys: keys}
+}
+
+func (s *EncryptedFileStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := values[key]
+	if !ok {
+		return nil, ErrNotStored
+	}
+	return value, nil
+}
+
+func (s *EncryptedFileStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	values[key] = value
+	return s.save(values)
+}
+
+func (s *EncryptedFileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+	delete(values, key)
+	return s.save(values)
+}
+
+func (s *EncryptedFileStore) aead() (cipher.AEAD, error) {
+	key, err := s.keys.Key()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, errors.New("okta: encrypted store key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *EncryptedFileStore) load() (map[string][]byte, error) {
+	values := map[string][]byte{}
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("okta: encrypted store is corrupt")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], fileStoreAAD)
+	if err != nil {
+		return nil, errors.New("okta: encrypted store can't be decrypted with this key")
+	}
+
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// save replaces the file atomically so a crash never leaves a torn store
+func (s *EncryptedFileStore) save(values map[string][]byte) error {
+	aead, err := s.aead()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := aead.Seal(nonce, nonce, plaintext, fileStoreAAD)
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
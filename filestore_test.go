@@ -0,0 +1,53 @@
+package okta
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptedFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "okta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cli", "sessions")
+	key := StaticKey(bytes.Repeat([]byte{1}, 32))
+	store := NewEncryptedFileStore(path, key)
+
+	if _, err := store.Get("session"); err != ErrNotStored {
+		t.Fatal("Expected ErrNotStored, got ", err)
+	}
+	if err := store.Set("session", []byte("102ABCsessiontoken")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := ioutil.ReadFile(path)
+	if bytes.Contains(data, []byte("102ABCsessiontoken")) {
+		t.Error("Expected token to be encrypted on disk")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Error("Expected file to be private, got ", info.Mode())
+	}
+
+	value, err := NewEncryptedFileStore(path, key).Get("session")
+	if err != nil || string(value) != "102ABCsessiontoken" {
+		t.Error("Expected token to be read back, got ", string(value), err)
+	}
+
+	wrongKey := StaticKey(bytes.Repeat([]byte{2}, 32))
+	if _, err := NewEncryptedFileStore(path, wrongKey).Get("session"); err == nil {
+		t.Error("Expected store to be unreadable with another key")
+	}
+
+	if err := store.Delete("session"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("session"); err != ErrNotStored {
+		t.Error("Expected ErrNotStored after Delete, got ", err)
+	}
+}
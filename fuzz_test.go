@@ -0,0 +1,109 @@
+//go:build go1.18
+
+package okta
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func FuzzHeaderLinks(f *testing.F) {
+	f.Add(`<https://example.okta.com/api/v1/users?after=00u2&limit=200>; rel="next"`)
+	f.Add(`<https://example.okta.com/api/v1/users?limit=200>; rel="self", <https://example.okta.com/api/v1/users?after=00u2>; rel="next"`)
+	f.Add(`<https://example.okta.com/api/v1/users?limit=200>;rel=self`)
+	f.Add(`<>; rel="next"`)
+	f.Add(`; rel=`)
+	f.Add(`<https://example.okta.com/governance/api/v1/campaigns?after=icicamp2>; rel="next"`)
+	f.Add(`<https://example.okta.com/api/v1/logs?since=2026-10-14T10%3A00%3A00.000Z>; rel="next"; title="next page"`)
+
+	f.Fuzz(func(t *testing.T, value string) {
+		header := http.Header{"Link": {value}}
+		for rel, target := range headerLinks(header) {
+			if strings.Contains(rel, ",") || strings.Contains(rel, ";") {
+				t.Errorf("Relation %q of %q spans several links", rel, value)
+			}
+			// the endpoints derived from links must never panic
+			apiEndpoint(target)
+			linkEndpoint(target)
+		}
+		nextLink(header)
+	})
+}
+
+func FuzzHeaderLinksRoundTrip(f *testing.F) {
+	f.Add("https://example.okta.com/api/v1/users?after=00u2&limit=200", "next")
+	f.Add("/api/v1/groups?after=00g2", "self")
+
+	f.Fuzz(func(t *testing.T, target, rel string) {
+		if strings.ContainsAny(target, "<>,; \t\r\n") || strings.ContainsAny(rel, `<>,;" `+"\t\r\n") || rel == "" {
+			return
+		}
+		header := http.Header{"Link": {"<" + target + `>; rel="` + rel + `"`}}
+		if parsed := linkRel(header, rel); parsed != target {
+			t.Errorf("Expected %q for %s, got %q", target, rel, parsed)
+		}
+	})
+}
+
+func FuzzRateLimitHeaders(f *testing.F) {
+	f.Add("600", "599", "1760436000")
+	f.Add("", "", "")
+	f.Add("-1", "9223372036854775807", "-9223372036854775808")
+	f.Add("1e3", "0x10", "1760436000.5")
+
+	f.Fuzz(func(t *testing.T, limit, remaining, reset string) {
+		header := http.Header{}
+		header.Set("X-Rate-Limit-Limit", limit)
+		header.Set("X-Rate-Limit-Remaining", remaining)
+		header.Set("X-Rate-Limit-Reset", reset)
+		parseRateLimit(header)
+		if wait := rateLimitWait(header); wait < 0 {
+			t.Errorf("Expected no negative wait for reset %q, got %s", reset, wait)
+		}
+	})
+}
+
+func FuzzErrorResponse(f *testing.F) {
+	f.Add([]byte(`{"errorCode":"E0000001","errorSummary":"Api validation failed: login","errorCauses":[{"errorSummary":"login: already exists"}]}`))
+	f.Add([]byte(`{"errorCode":"E0000047","errorSummary":"API call exceeded rate limit due to too many requests."}`))
+	f.Add([]byte(`{"errorCode":null,"errorCauses":{}}`))
+	f.Add([]byte(`<html><body>Bad gateway</body></html>`))
+	f.Add([]byte(``))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		var errors ErrorResponse
+		_ = json.Unmarshal(body, &errors)
+		for _, status := range []int{400, 401, 403, 404, 429, 500, 502} {
+			err := &errorResponse{
+				HTTPCode: status,
+				Response: errors,
+				Endpoint: "https://example.okta.com/api/v1/users?q=" + string(body),
+				Class:    DefaultErrorMap.Classify(status, errors.ErrorCode),
+			}
+			if err.Error() == "" {
+				t.Error("Expected an error message")
+			}
+			ClassOf(err)
+			ErrorCode(err)
+		}
+	})
+}
+
+func FuzzDecodeResponse(f *testing.F) {
+	f.Add([]byte(`{"id":"00u1","status":"ACTIVE","profile":{"login":"jane@example.com","badge":7},"_links":{"self":{"href":"https://example.okta.com/api/v1/users/00u1"}}}`))
+	f.Add([]byte(`{"_links":{"logo":[{"name":"medium","href":"https://example.com/logo.png"}],"users":{"href":"x"}}}`))
+	f.Add([]byte(`{"profile":null,"_links":null,"created":"not a time"}`))
+	f.Add([]byte(`{"_links":{"self":[]}}`))
+
+	client := NewClient("example")
+	f.Fuzz(func(t *testing.T, body []byte) {
+		client.decode(bytes.NewReader(body), &User{})
+		client.decode(bytes.NewReader(body), &Group{})
+		client.decode(bytes.NewReader(body), &AuthnResponse{})
+		client.decode(bytes.NewReader(body), &[]LogEvent{})
+		preserveUnknown(body, &App{})
+	})
+}
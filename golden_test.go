@@ -0,0 +1,61 @@
+package okta
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the decoding tests")
+
+// TestDecodeGolden decodes the sanitized Okta responses of
+// testdata/responses and compares the result with testdata/golden, a
+// change of the golden file shows what a change of the types or the
+// responses did. go test -run TestDecodeGolden -update rewrites them.
+func TestDecodeGolden(t *testing.T) {
+	cases := map[string]func() interface{}{
+		"user.json":               func() interface{} { return &User{} },
+		"group.json":              func() interface{} { return &Group{} },
+		"authn_mfa_required.json": func() interface{} { return &AuthnResponse{} },
+		"session.json":            func() interface{} { return &SessionResponse{} },
+		"logs.json":               func() interface{} { return &[]LogEvent{} },
+		"error.json":              func() interface{} { return &ErrorResponse{} },
+	}
+
+	client := NewClient("example")
+	for name, newValue := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", "responses", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := newValue()
+			if err := client.decode(bytes.NewReader(data), value); err != nil {
+				t.Fatal("Expected the response to decode, got ", err)
+			}
+			decoded, err := json.MarshalIndent(value, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded = append(decoded, '\n')
+
+			golden := filepath.Join("testdata", "golden", name[:len(name)-len(".json")]+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, decoded, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err, ", run with -update to create it")
+			}
+			if !bytes.Equal(decoded, expected) {
+				t.Errorf("Decoded %s differs from %s, run with -update if the change is intended:\n%s", name, golden, decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// governanceAPI is the base of the Okta Identity Governance API, outside
+// of the management API
+const governanceAPI = "/governance/api/v1/"
+
+// Access certification campaign states
+const (
+	CampaignReady     = "READY"
+	CampaignScheduled = "SCHEDULED"
+	CampaignLaunching = "LAUNCHING"
+	CampaignActive    = "ACTIVE"
+	CampaignCompleted = "COMPLETED"
+	CampaignCanceled  = "CANCELED"
+	CampaignError     = "ERROR"
+)
+
+// Decisions of a review
+const (
+	ReviewUnreviewed = "UNREVIEWED"
+	ReviewApprove    = "APPROVE"
+	ReviewRevoke     = "REVOKE"
+)
+
+// Campaign is an access certification campaign of Okta Identity Governance
+type Campaign struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	// CampaignType is RESOURCE or USER
+	CampaignType     string     `json:"campaignType"`
+	Created          *time.Time `json:"created"`
+	CreatedBy        string     `json:"createdBy"`
+	LastUpdated      *time.Time `json:"lastUpdated"`
+	LastUpdatedBy    string     `json:"lastUpdatedBy"`
+	LaunchedDate     *time.Time `json:"launchedDate"`
+	EndedDate        *time.Time `json:"endedDate"`
+	ScheduleSettings struct {
+		Type           string     `json:"type"`
+		StartDate      *time.Time `json:"startDate"`
+		DurationInDays int        `json:"durationInDays"`
+		TimeZone       string     `json:"timeZone"`
+	} `json:"scheduleSettings"`
+	ReviewerSettings struct {
+		Type string `json:"type"`
+	} `json:"reviewerSettings"`
+}
+
+// Review is the decision of a reviewer on the access of a user to a
+// resource in a campaign
+type Review struct {
+	ID               string     `json:"id"`
+	CampaignID       string     `json:"campaignId"`
+	ResourceID       string     `json:"resourceId"`
+	Decision         string     `json:"decision"`
+	Decided          *time.Time `json:"decided"`
+	Note             string     `json:"note"`
+	ReviewerType     string     `json:"reviewerType"`
+	Created          *time.Time `json:"created"`
+	LastUpdated      *time.Time `json:"lastUpdated"`
+	PrincipalProfile struct {
+		ID        string `json:"id"`
+		Login     string `json:"login"`
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+		Email     string `json:"email"`
+		Status    string `json:"status"`
+	} `json:"principalProfile"`
+	ReviewerProfile struct {
+		ID    string `json:"id"`
+		Login string `json:"login"`
+		Email string `json:"email"`
+	} `json:"reviewerProfile"`
+}
+
+// ReviewSummary counts the reviews of a campaign by decision
+type ReviewSummary struct {
+	Total      int
+	Unreviewed int
+	Approved   int
+	Revoked    int
+}
+
+// Done is the fraction of reviews decided, 1 for a campaign without
+// reviews
+func (s ReviewSummary) Done() float64 {
+	if s.Total == 0 {
+		return 1
+	}
+	return float64(s.Total-s.Unreviewed) / float64(s.Total)
+}
+
+// ListCampaigns returns the access certification campaigns matching a
+// filter expression such as status eq "ACTIVE", all of them when empty
+func (c *Client) ListCampaigns(filter string) (*[]Campaign, error) {
+	v := url.Values{}
+	setNonEmpty(v, "filter", filter)
+
+	var response = &[]Campaign{}
+	err := c.listGovernance(withQuery(governanceAPI+"campaigns", v), response)
+	return response, err
+}
+
+// Campaign returns the access certification campaign with the given id
+func (c *Client) Campaign(campaignID string) (*Campaign, error) {
+	var response = &Campaign{}
+	err, _ := c.call(governanceAPI+"campaigns/"+url.PathEscape(campaignID), "GET", nil, response)
+	return response, err
+}
+
+// ListReviews returns the reviews of a campaign, filter narrows them down
+// further, e.g. decision eq "UNREVIEWED"
+func (c *Client) ListReviews(campaignID, filter string) (*[]Review, error) {
+	expr := filterExpr("campaignId", "eq", campaignID)
+	if filter != "" {
+		expr += " and " + filter
+	}
+	v := url.Values{}
+	v.Set("filter", expr)
+
+	var response = &[]Review{}
+	err := c.listGovernance(withQuery(governanceAPI+"reviews", v), response)
+	return response, err
+}
+
+// Review returns the review with the given id
+func (c *Client) Review(reviewID string) (*Review, error) {
+	var response = &Review{}
+	err, _ := c.call(governanceAPI+"reviews/"+url.PathEscape(reviewID), "GET", nil, response)
+	return response, err
+}
+
+// CampaignReviewSummary counts the reviews of a campaign by decision
+func (c *Client) CampaignReviewSummary(campaignID string) (*ReviewSummary, error) {
+	reviews, err := c.ListReviews(campaignID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ReviewSummary{Total: len(*reviews)}
+	for _, review := range *reviews {
+		switch review.Decision {
+		case ReviewApprove:
+			summary.Approved++
+		case ReviewRevoke:
+			summary.Revoked++
+		default:
+			summary.Unreviewed++
+		}
+	}
+	return summary, nil
+}
+
+// listGovernance follows the next links in the body of governance list
+// pages, which wrap their items in data, and appends every page to
+// response
+func (c *Client) listGovernance(endpoint string, response interface{}) error {
+	all := reflect.ValueOf(response).Elem()
+
+	for endpoint != "" {
+		var page struct {
+			Data  json.RawMessage `json:"data"`
+			Links Links           `json:"_links"`
+		}
+		if err, _ := c.call(endpoint, "GET", nil, &page); err != nil {
+			return err
+		}
+
+		items := reflect.New(all.Type())
+		if len(page.Data) > 0 {
+			if err := json.Unmarshal(page.Data, items.Interface()); err != nil {
+				return err
+			}
+		}
+		all.Set(reflect.AppendSlice(all, items.Elem()))
+
+		next := ""
+		if link, ok := page.Links.Get("next"); ok {
+			next = linkEndpoint(link.Href)
+		}
+		if next == endpoint {
+			break
+		}
+		endpoint = next
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCampaignReviewSummary(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/governance/api/v1/reviews" {
+			http.NotFound(w, r)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("after") == "" {
+			w.Write([]byte(`{
+				"data": [{"id":"icr1","campaignId":"icicamp1","decision":"APPROVE"},{"id":"icr2","campaignId":"icicamp1","decision":"UNREVIEWED"}],
+				"_links": {"next": {"href": "https://` + r.Host + `/governance/api/v1/reviews?after=icr2&filter=campaignId+eq+%22icicamp1%22"}}
+			}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id":"icr3","campaignId":"icicamp1","decision":"REVOKE","principalProfile":{"login":"jane@example.com"}}], "_links": {}}`))
+	}))
+	defer done()
+
+	summary, err := client.CampaignReviewSummary("icicamp1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *summary != (ReviewSummary{Total: 3, Unreviewed: 1, Approved: 1, Revoked: 1}) {
+		t.Errorf("Expected a review of every decision, got %+v", summary)
+	}
+	if done := summary.Done(); done < 0.66 || done > 0.67 {
+		t.Error("Expected two thirds done, got ", done)
+	}
+	if len(queries) != 2 || queries[0] != "filter=campaignId+eq+%22icicamp1%22" {
+		t.Error("Expected both pages of the campaign, got ", queries)
+	}
+}
@@ -0,0 +1,103 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Grant is the consent of a user to an OAuth scope of an app
+type Grant struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	Issuer      string     `json:"issuer"`
+	ClientID    string     `json:"clientId"`
+	UserID      string     `json:"userId"`
+	ScopeID     string     `json:"scopeId"`
+	Source      string     `json:"source"`
+	CreatedBy   struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"createdBy"`
+}
+
+// OAuthClient is an OAuth app a user has granted access to or holds
+// tokens of
+type OAuthClient struct {
+	ClientID   string `json:"client_id"`
+	ClientName string `json:"client_name"`
+	ClientURI  string `json:"client_uri"`
+	LogoURI    string `json:"logo_uri"`
+}
+
+// OAuthToken is a refresh token issued to a user for an app, the token
+// value itself is never returned
+type OAuthToken struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	Issuer      string     `json:"issuer"`
+	ClientID    string     `json:"clientId"`
+	UserID      string     `json:"userId"`
+	Scopes      []string   `json:"scopes"`
+}
+
+// ListGrants returns the scope grants of a user for every app
+func (c *Client) ListGrants(userID string) (*[]Grant, error) {
+	var response = &[]Grant{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/grants?limit=200", response)
+	return response, err
+}
+
+// RevokeGrant revokes one scope grant of a user
+func (c *Client) RevokeGrant(userID, grantID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/grants/"+url.PathEscape(grantID), "DELETE", nil, nil)
+	return err
+}
+
+// RevokeGrants revokes every scope grant of a user
+func (c *Client) RevokeGrants(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/grants", "DELETE", nil, nil)
+	return err
+}
+
+// ListUserClients returns the OAuth apps a user has grants or tokens for
+func (c *Client) ListUserClients(userID string) (*[]OAuthClient, error) {
+	var response = &[]OAuthClient{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/clients", response)
+	return response, err
+}
+
+// ListUserOAuthTokens returns the refresh tokens issued to a user for the
+// app with the given client id
+func (c *Client) ListUserOAuthTokens(userID, clientID string) (*[]OAuthToken, error) {
+	var response = &[]OAuthToken{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/clients/"+url.PathEscape(clientID)+"/tokens?limit=200", response)
+	return response, err
+}
+
+// RevokeUserTokensForApp revokes every refresh token issued to a user for
+// the app with the given client id, access tokens stay valid until they
+// expire
+func (c *Client) RevokeUserTokensForApp(userID, clientID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/clients/"+url.PathEscape(clientID)+"/tokens", "DELETE", nil, nil)
+	return err
+}
+
+// RevokeUserAccess revokes the grants of a user and the refresh tokens of
+// every app the user holds tokens for, e.g. when offboarding
+func (c *Client) RevokeUserAccess(userID string) error {
+	clients, err := c.ListUserClients(userID)
+	if err != nil {
+		return err
+	}
+	for _, client := range *clients {
+		if err := c.RevokeUserTokensForApp(userID, client.ClientID); err != nil && ClassOf(err).Kind != ErrorNotFound {
+			return err
+		}
+	}
+	return c.RevokeGrants(userID)
+}
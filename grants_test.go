@@ -0,0 +1,40 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRevokeUserAccess(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/users/00u1/clients":
+			w.Write([]byte(`[{"client_id":"0oa1","client_name":"Portal"},{"client_id":"0oa2","client_name":"CLI"}]`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/users/00u1/grants":
+			w.Write([]byte(`[{"id":"oag1","status":"ACTIVE","clientId":"0oa1","scopeId":"okta.users.read.self","createdBy":{"id":"00u1","type":"User"}}]`))
+		case r.URL.Path == "/api/v1/users/00u1/clients/0oa2/tokens":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	grants, err := client.ListGrants("00u1")
+	if err != nil || len(*grants) != 1 || (*grants)[0].ScopeID != "okta.users.read.self" || (*grants)[0].CreatedBy.Type != "User" {
+		t.Fatal("Expected the grant, got ", grants, err)
+	}
+	recorder.reset()
+	if err := client.RevokeUserAccess("00u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/users/00u1/clients",
+		"DELETE /api/v1/users/00u1/clients/0oa1/tokens",
+		"DELETE /api/v1/users/00u1/clients/0oa2/tokens",
+		"DELETE /api/v1/users/00u1/grants",
+	)
+}
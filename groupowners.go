@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Group owner types
+const (
+	GroupOwnerUser  = "USER"
+	GroupOwnerGroup = "GROUP"
+)
+
+// GroupOwner is a user or group owning a group, owners of groups imported
+// from apps are only resolved once the app pushed them
+type GroupOwner struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	DisplayName string `json:"displayName"`
+	// OriginType is OKTA_DIRECTORY or APPLICATION, OriginID is the id of
+	// the app for the latter
+	OriginType  string     `json:"originType"`
+	OriginID    string     `json:"originId"`
+	Resolved    bool       `json:"resolved"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+}
+
+func groupOwners(groupID string) string {
+	return "groups/" + url.PathEscape(groupID) + "/owners"
+}
+
+// ListGroupOwners returns the owners of a group
+func (c *Client) ListGroupOwners(groupID string) (*[]GroupOwner, error) {
+	var response = &[]GroupOwner{}
+	err := c.listAll(groupOwners(groupID)+"?limit=200", response)
+	return response, err
+}
+
+// AddGroupOwner makes a user or group, ownerType GroupOwnerUser or
+// GroupOwnerGroup, an owner of a group
+func (c *Client) AddGroupOwner(groupID, ownerID, ownerType string) (*GroupOwner, error) {
+	var request = map[string]string{
+		"id":   ownerID,
+		"type": ownerType,
+	}
+
+	var response = &GroupOwner{}
+	err, _ := c.call(groupOwners(groupID), "POST", request, response)
+	return response, err
+}
+
+// RemoveGroupOwner removes an owner of a group
+func (c *Client) RemoveGroupOwner(groupID, ownerID string) error {
+	err, _ := c.call(groupOwners(groupID)+"/"+url.PathEscape(ownerID), "DELETE", nil, nil)
+	return err
+}
@@ -0,0 +1,42 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGroupOwners(t *testing.T) {
+	var added map[string]string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`[{"id":"00u1","type":"USER","displayName":"Jane Doe","originType":"OKTA_DIRECTORY","resolved":true}]`))
+		case "POST":
+			json.NewDecoder(r.Body).Decode(&added)
+			w.Write([]byte(`{"id":"00g2","type":"GROUP","displayName":"Access reviewers","originType":"OKTA_DIRECTORY","resolved":true}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	owners, err := client.ListGroupOwners("00g1")
+	if err != nil || len(*owners) != 1 || (*owners)[0].DisplayName != "Jane Doe" || !(*owners)[0].Resolved {
+		t.Fatal("Expected the owner, got ", owners, err)
+	}
+	owner, err := client.AddGroupOwner("00g1", "00g2", GroupOwnerGroup)
+	if err != nil || owner.Type != GroupOwnerGroup || added["id"] != "00g2" || added["type"] != "GROUP" {
+		t.Fatal("Expected the group to be added as owner, got ", owner, added, err)
+	}
+	if err := client.RemoveGroupOwner("00g1", "00u1"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/groups/00g1/owners?limit=200",
+		"POST /api/v1/groups/00g1/owners",
+		"DELETE /api/v1/groups/00g1/owners/00u1",
+	)
+}
@@ -0,0 +1,537 @@
+package okta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GroupRule assigns the users matching an expression to groups
+type GroupRule struct {
+	ID          string     `json:"id,omitempty"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status,omitempty"`
+	Name        string     `json:"name"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Conditions  struct {
+		People *struct {
+			Users struct {
+				Exclude []string `json:"exclude"`
+			} `json:"users"`
+			Groups struct {
+				Exclude []string `json:"exclude"`
+			} `json:"groups"`
+		} `json:"people,omitempty"`
+		Expression struct {
+			Value string `json:"value"`
+			Type  string `json:"type"`
+		} `json:"expression"`
+	} `json:"conditions"`
+	Actions struct {
+		AssignUserToGroups struct {
+			GroupIDs []string `json:"groupIds"`
+		} `json:"assignUserToGroups"`
+	} `json:"actions"`
+	// Unknown keeps the attributes without a field through UpdateGroupRule,
+	// see Client.PreserveUnknownFields
+	Unknown UnknownFields `json:"-"`
+}
+
+// NewGroupRule returns a group rule assigning the users matching
+// expression to the given groups
+func NewGroupRule(name, expression string, groupIDs ...string) *GroupRule {
+	rule := &GroupRule{Type: "group_rule", Name: name}
+	rule.Conditions.Expression.Value = expression
+	rule.Conditions.Expression.Type = "urn:okta:expression:1.0"
+	rule.Actions.AssignUserToGroups.GroupIDs = groupIDs
+	return rule
+}
+
+// GroupRules returns every group rule of the org
+func (c *Client) GroupRules() (*[]GroupRule, error) {
+	var response = &[]GroupRule{}
+	err := c.listAll("groups/rules?limit=200", response)
+	return response, err
+}
+
+// CreateGroupRule creates an INACTIVE group rule, see ActivateGroupRule
+func (c *Client) CreateGroupRule(rule *GroupRule) (*GroupRule, error) {
+	var response = &GroupRule{}
+	err, _ := c.call("groups/rules", "POST", rule, response)
+	return response, err
+}
+
+// UpdateGroupRule replaces an INACTIVE group rule
+func (c *Client) UpdateGroupRule(ruleID string, rule *GroupRule) (*GroupRule, error) {
+	var response = &GroupRule{}
+	err, _ := c.call("groups/rules/"+url.PathEscape(ruleID), "PUT", rule, response)
+	return response, err
+}
+
+// ActivateGroupRule activates a group rule, Okta then assigns the matching
+// users to its groups
+func (c *Client) ActivateGroupRule(ruleID string) error {
+	err, _ := c.call("groups/rules/"+url.PathEscape(ruleID)+"/lifecycle/activate", "POST", nil, nil)
+	return err
+}
+
+// DeactivateGroupRule deactivates a group rule, the memberships it made
+// are kept
+func (c *Client) DeactivateGroupRule(ruleID string) error {
+	err, _ := c.call("groups/rules/"+url.PathEscape(ruleID)+"/lifecycle/deactivate", "POST", nil, nil)
+	return err
+}
+
+// DeleteGroupRule deletes a group rule, the memberships it made are removed
+func (c *Client) DeleteGroupRule(ruleID string) error {
+	err, _ := c.call("groups/rules/"+url.PathEscape(ruleID), "DELETE", nil, nil)
+	return err
+}
+
+// ListUsersByGroupRule previews which existing users a group rule with the
+// given expression would assign, without creating the rule. The parts of
+// the expression that Okta's user search understands narrow the users
+// fetched, the whole expression is then evaluated locally on them.
+//
+// A subset of the Okta Expression Language is supported: == and !=
+// comparisons of user attributes, AND/OR (or && and ||), parentheses,
+// String.startsWith, String.stringContains and isMemberOfAnyGroup.
+func (c *Client) ListUsersByGroupRule(expression string) (*[]User, error) {
+	rule, err := parseGroupRule(expression)
+	if err != nil {
+		return &[]User{}, err
+	}
+
+	candidates, err := c.ListUsers(&ListUsersOptions{Search: rule.search()})
+	if err != nil {
+		return &[]User{}, err
+	}
+
+	eval := &ruleEvaluation{client: c, members: map[string]map[string]bool{}}
+	var response = &[]User{}
+	for i := range *candidates {
+		user := &(*candidates)[i]
+		matched, err := eval.matches(rule, user)
+		if err != nil {
+			return response, err
+		}
+		if matched {
+			*response = append(*response, *user)
+		}
+	}
+	return response, nil
+}
+
+// ruleExpr is a node of a parsed group rule expression
+type ruleExpr interface {
+	// search returns a user search expression matching a superset of the
+	// users matched by the node, empty if it can't be narrowed
+	search() string
+}
+
+type ruleAnd struct{ left, right ruleExpr }
+type ruleOr struct{ left, right ruleExpr }
+
+type ruleCompare struct {
+	attribute string
+	negate    bool
+	value     string
+}
+
+type ruleFunc struct {
+	name      string
+	attribute string
+	value     string
+}
+
+type ruleMemberOf struct {
+	groupIDs []string
+}
+
+func (e ruleAnd) search() string {
+	left, right := e.left.search(), e.right.search()
+	switch {
+	case left == "":
+		return right
+	case right == "":
+		return left
+	}
+	return "(" + left + ") and (" + right + ")"
+}
+
+func (e ruleOr) search() string {
+	left, right := e.left.search(), e.right.search()
+	if left == "" || right == "" {
+		return ""
+	}
+	return "(" + left + ") or (" + right + ")"
+}
+
+func (e ruleCompare) search() string {
+	if e.negate {
+		return ""
+	}
+	return filterExpr("profile."+e.attribute, "eq", e.value)
+}
+
+func (e ruleFunc) search() string {
+	if e.name != "String.startsWith" {
+		return ""
+	}
+	return filterExpr("profile."+e.attribute, "sw", e.value)
+}
+
+func (e ruleMemberOf) search() string {
+	return ""
+}
+
+// ruleEvaluation evaluates an expression against users, fetching group
+// memberships once per group
+type ruleEvaluation struct {
+	client  *Client
+	members map[string]map[string]bool
+}
+
+func (r *ruleEvaluation) matches(expr ruleExpr, user *User) (bool, error) {
+	switch e := expr.(type) {
+	case ruleAnd:
+		left, err := r.matches(e.left, user)
+		if err != nil || !left {
+			return false, err
+		}
+		return r.matches(e.right, user)
+	case ruleOr:
+		left, err := r.matches(e.left, user)
+		if err != nil || left {
+			return left, err
+		}
+		return r.matches(e.right, user)
+	case ruleCompare:
+		value, ok := profileAttribute(user, e.attribute)
+		return ok && (value == e.value) != e.negate, nil
+	case ruleFunc:
+		value, ok := profileAttribute(user, e.attribute)
+		if !ok {
+			return false, nil
+		}
+		if e.name == "String.startsWith" {
+			return strings.HasPrefix(value, e.value), nil
+		}
+		return strings.Contains(value, e.value), nil
+	case ruleMemberOf:
+		for _, groupID := range e.groupIDs {
+			members, err := r.groupMembers(groupID)
+			if err != nil {
+				return false, err
+			}
+			if members[user.ID] {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("okta: unsupported group rule node %T", expr)
+}
+
+func (r *ruleEvaluation) groupMembers(groupID string) (map[string]bool, error) {
+	if members, ok := r.members[groupID]; ok {
+		return members, nil
+	}
+
+	users, err := r.client.GroupMembers(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members := map[string]bool{}
+	for _, user := range *users {
+		members[user.ID] = true
+	}
+	r.members[groupID] = members
+	return members, nil
+}
+
+// profileAttribute returns a profile attribute formatted the way the
+// expression language compares it
+func profileAttribute(user *User, name string) (string, bool) {
+	data, err := json.Marshal(user.Profile)
+	if err != nil {
+		return "", false
+	}
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return "", false
+	}
+
+	value, ok := attributes[name]
+	if !ok || value == nil {
+		return "", false
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	return fmt.Sprint(value), true
+}
+
+// parseGroupRule parses the supported subset of the expression language:
+//
+//	expr    = and { ("OR" | "||") and }
+//	and     = primary { ("AND" | "&&") primary }
+//	primary = "(" expr ")" | compare | call
+func parseGroupRule(expression string) (ruleExpr, error) {
+	tokens, err := tokenizeGroupRule(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ruleParser{tokens: tokens}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("okta: unexpected %q in group rule", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type ruleToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeGroupRule(expression string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	for i := 0; i < len(expression); {
+		ch := expression[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '"' || ch == '\'':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(expression) && expression[j] != ch; j++ {
+				if expression[j] == '\\' && j+1 < len(expression) {
+					j++
+				}
+				value.WriteByte(expression[j])
+			}
+			if j >= len(expression) {
+				return nil, fmt.Errorf("okta: unterminated string in group rule")
+			}
+			tokens = append(tokens, ruleToken{text: value.String(), quoted: true})
+			i = j + 1
+		case strings.HasPrefix(expression[i:], "==") ||
+			strings.HasPrefix(expression[i:], "!=") ||
+			strings.HasPrefix(expression[i:], "&&") ||
+			strings.HasPrefix(expression[i:], "||"):
+			tokens = append(tokens, ruleToken{text: expression[i : i+2]})
+			i += 2
+		case ch == '(' || ch == ')' || ch == ',':
+			tokens = append(tokens, ruleToken{text: string(ch)})
+			i++
+		case ch == '.' || ch == '_' || ch == '$' ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9'):
+			j := i
+			for j < len(expression) {
+				c := expression[j]
+				if c != '.' && c != '_' && c != '$' &&
+					!(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+					break
+				}
+				j++
+			}
+			tokens = append(tokens, ruleToken{text: expression[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("okta: unexpected %q in group rule", ch)
+		}
+	}
+	return tokens, nil
+}
+
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+}
+
+func (p *ruleParser) peek() (ruleToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return ruleToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *ruleParser) next() (ruleToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return token, fmt.Errorf("okta: unexpected end of group rule")
+	}
+	p.pos++
+	return token, nil
+}
+
+func (p *ruleParser) expect(text string) error {
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if token.quoted || token.text != text {
+		return fmt.Errorf("okta: expected %q in group rule, got %q", text, token.text)
+	}
+	return nil
+}
+
+func (p *ruleParser) keyword(words ...string) bool {
+	token, ok := p.peek()
+	if !ok || token.quoted {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(token.text, word) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ruleParser) or() (ruleExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR", "||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = ruleOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) and() (ruleExpr, error) {
+	left, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND", "&&") {
+		right, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		left = ruleAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) primary() (ruleExpr, error) {
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if token.quoted {
+		return nil, fmt.Errorf("okta: unexpected string %q in group rule", token.text)
+	}
+
+	switch {
+	case token.text == "(":
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+
+	case strings.HasPrefix(token.text, "user."):
+		op, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if op.quoted || (op.text != "==" && op.text != "!=") {
+			return nil, fmt.Errorf("okta: unsupported operator %q in group rule", op.text)
+		}
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		return ruleCompare{
+			attribute: strings.TrimPrefix(token.text, "user."),
+			negate:    op.text == "!=",
+			value:     value,
+		}, nil
+
+	case token.text == "String.startsWith" || token.text == "String.stringContains":
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 2 || args[0].quoted || !strings.HasPrefix(args[0].text, "user.") || !args[1].quoted {
+			return nil, fmt.Errorf("okta: %s expects a user attribute and a string", token.text)
+		}
+		return ruleFunc{
+			name:      token.text,
+			attribute: strings.TrimPrefix(args[0].text, "user."),
+			value:     args[1].text,
+		}, nil
+
+	case token.text == "isMemberOfAnyGroup":
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		member := ruleMemberOf{}
+		for _, arg := range args {
+			if !arg.quoted {
+				return nil, fmt.Errorf("okta: isMemberOfAnyGroup expects group ids")
+			}
+			member.groupIDs = append(member.groupIDs, arg.text)
+		}
+		return member, nil
+	}
+
+	return nil, fmt.Errorf("okta: unsupported %q in group rule", token.text)
+}
+
+// literal reads a string or a bare value such as true or 42
+func (p *ruleParser) literal() (string, error) {
+	token, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if !token.quoted && (token.text == "(" || token.text == ")" || token.text == ",") {
+		return "", fmt.Errorf("okta: expected a value in group rule, got %q", token.text)
+	}
+	return token.text, nil
+}
+
+func (p *ruleParser) arguments() ([]ruleToken, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []ruleToken
+	for {
+		token, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !token.quoted && token.text == ")" && len(args) == 0 {
+			return args, nil
+		}
+		args = append(args, token)
+
+		sep, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if sep.quoted || (sep.text != "," && sep.text != ")") {
+			return nil, fmt.Errorf("okta: expected , or ) in group rule, got %q", sep.text)
+		}
+		if sep.text == ")" {
+			return args, nil
+		}
+	}
+}
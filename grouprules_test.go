@@ -0,0 +1,60 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGroupRuleSearch(t *testing.T) {
+	cases := map[string]string{
+		`user.department == "Engineering"`:                                 `profile.department eq "Engineering"`,
+		`user.department=="Eng" AND String.startsWith(user.title, "Sr")`:   `(profile.department eq "Eng") and (profile.title sw "Sr")`,
+		`user.city == "Atlanta" && String.stringContains(user.title, "x")`: `profile.city eq "Atlanta"`,
+		`user.city == "Atlanta" OR isMemberOfAnyGroup("00g1")`:             ``,
+		`(user.a == "1" || user.b == "2") AND user.c != "3"`:               `(profile.a eq "1") or (profile.b eq "2")`,
+	}
+	for expression, expected := range cases {
+		rule, err := parseGroupRule(expression)
+		if err != nil {
+			t.Errorf("Expected %s to parse, got %v", expression, err)
+			continue
+		}
+		if got := rule.search(); got != expected {
+			t.Errorf("Expected search %q for %s, got %q", expected, expression, got)
+		}
+	}
+
+	for _, expression := range []string{`user.a > 3`, `user.a == "1" AND`, `unknown(user.a)`, `user.a == "1`} {
+		if _, err := parseGroupRule(expression); err == nil {
+			t.Errorf("Expected %s to be rejected", expression)
+		}
+	}
+}
+
+func TestListUsersByGroupRule(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users":
+			if r.URL.Query().Get("search") != `profile.department eq "Eng"` {
+				t.Error("Unexpected search ", r.URL.Query().Get("search"))
+			}
+			w.Write([]byte(`[
+				{"id":"00u1","profile":{"department":"Eng","title":"Senior Engineer"}},
+				{"id":"00u2","profile":{"department":"Eng","title":"Manager"}},
+				{"id":"00u3","profile":{"department":"Eng","title":"Engineer"}}]`))
+		case "/api/v1/groups/00gA/users":
+			w.Write([]byte(`[{"id":"00u3"}]`))
+		default:
+			t.Error("Unexpected request ", r.URL.Path)
+		}
+	}))
+	defer done()
+
+	users, err := client.ListUsersByGroupRule(`user.department == "Eng" AND (String.stringContains(user.title, "Senior") OR isMemberOfAnyGroup("00gA"))`)
+	if err != nil {
+		t.Fatal("Expected nil, got ", err.Error())
+	}
+	if len(*users) != 2 || (*users)[0].ID != "00u1" || (*users)[1].ID != "00u3" {
+		t.Errorf("Expected 00u1 and 00u3, got %+v", *users)
+	}
+}
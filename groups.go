@@ -0,0 +1,72 @@
+package okta
+
+import (
+	"net/url"
+)
+
+// GroupProfile is the profile of an OKTA_GROUP
+type GroupProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Group takes a group id and returns the group
+//
+// Deprecated: use Client.Groups.Get of github.com/Cox-Automotive/go-okta/v2
+func (c *Client) Group(groupID string) (*Group, error) {
+	var response = &Group{}
+	err, _ := c.call("groups/"+url.PathEscape(groupID), "GET", nil, response)
+	return response, err
+}
+
+// CreateGroup creates an OKTA_GROUP
+func (c *Client) CreateGroup(profile GroupProfile) (*Group, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
+
+	var response = &Group{}
+	err, _ := c.call("groups", "POST", request, response)
+	return response, err
+}
+
+// UpdateGroup replaces the profile of an OKTA_GROUP, groups imported from
+// apps can't be changed
+func (c *Client) UpdateGroup(groupID string, profile GroupProfile) (*Group, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
+
+	var response = &Group{}
+	err, _ := c.call("groups/"+url.PathEscape(groupID), "PUT", request, response)
+	return response, err
+}
+
+// DeleteGroup deletes an OKTA_GROUP, its members stay
+func (c *Client) DeleteGroup(groupID string) error {
+	err, _ := c.call("groups/"+url.PathEscape(groupID), "DELETE", nil, nil)
+	return err
+}
+
+// ListGroups returns the groups of the org matching opts, all of them if
+// opts is nil
+func (c *Client) ListGroups(opts *ListGroupsOptions) (*[]Group, error) {
+	var response = &[]Group{}
+	err := c.listAll(withQuery("groups", opts.values()), response)
+	return response, err
+}
+
+// GroupByName returns the group with exactly the given name or nil, the
+// q search of ListGroups matches the start of names
+func (c *Client) GroupByName(name string) (*Group, error) {
+	groups, err := c.ListGroups(&ListGroupsOptions{Q: name})
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range *groups {
+		if group.Profile.Name == name {
+			return &group, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,172 @@
+package okta
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const defaultHedgeReserve = 0.5
+
+// HedgePolicy sends a second GET when the first one hasn't been answered
+// after Delay and uses whichever response arrives first, cutting the tail
+// latency of reads on sign in paths. The other request is cancelled. The
+// second request counts against the rate limit like any other, it goes
+// through the Limiter and is not sent once Reserve of the bucket is used.
+type HedgePolicy struct {
+	// Delay is how long the first request may take before the second one
+	// is sent, e.g. the P95 latency of the endpoints hedged
+	Delay time.Duration
+	// Reserve is the utilization of the rate limit bucket of an endpoint
+	// above which no second request is sent, between 0 and 1, 0.5 when
+	// zero. Endpoints without a known rate limit are hedged.
+	Reserve float64
+}
+
+// WithHedging hedges the GET calls made with the context after delay, see
+// HedgePolicy. It replaces Client.Hedge, a delay of zero turns hedging off.
+func WithHedging(delay time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.hedge = &HedgePolicy{Delay: delay}
+	}
+}
+
+// hedgePolicy returns the policy of the call options of ctx or else
+// Client.Hedge, nil when calls are not hedged
+func (c *Client) hedgePolicy(ctx context.Context) *HedgePolicy {
+	policy := c.Hedge
+	if hedge := callOptionsOf(ctx).hedge; hedge != nil {
+		policy = hedge
+	}
+	if policy == nil || policy.Delay <= 0 {
+		return nil
+	}
+	return policy
+}
+
+// hedgeAllowed reports whether the rate limit bucket of an endpoint has
+// room for a second request
+func (c *Client) hedgeAllowed(policy *HedgePolicy, method, endpoint string) bool {
+	reserve := policy.Reserve
+	if reserve <= 0 {
+		reserve = defaultHedgeReserve
+	}
+	limit, ok := c.RateLimit(method, endpoint)
+	if !ok || time.Now().After(limit.Reset) {
+		return true
+	}
+	return limit.Utilization() < reserve
+}
+
+type hedgeResult struct {
+	hedge bool
+	resp  *http.Response
+	err   error
+}
+
+// send sends req, hedging GET requests when a HedgePolicy applies
+func (c *Client) send(ctx context.Context, req *http.Request, endpoint string) (*http.Response, error) {
+	policy := c.hedgePolicy(ctx)
+	if policy == nil || req.Method != "GET" {
+		return c.client.Do(req)
+	}
+
+	results := make(chan hedgeResult, 2)
+	cancels := map[bool]context.CancelFunc{}
+	start := func(hedge bool) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels[hedge] = cancel
+		go func() {
+			resp, err := c.hedgeDo(ctx, policy, req, endpoint, hedge)
+			results <- hedgeResult{hedge, resp, err}
+		}()
+	}
+	start(false)
+
+	timer := time.NewTimer(policy.Delay)
+	defer timer.Stop()
+	pending := 1
+	var errs = map[bool]error{}
+	for {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 && c.hedgeAllowed(policy, req.Method, endpoint) {
+				start(true)
+				pending++
+			}
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				for hedge, cancel := range cancels {
+					if hedge != result.hedge {
+						cancel()
+					}
+				}
+				// cancelled once the body is read
+				result.resp.Body = &cancelBody{result.resp.Body, cancels[result.hedge]}
+				go drainHedge(results, pending)
+				return result.resp, nil
+			}
+			cancels[result.hedge]()
+			errs[result.hedge] = result.err
+			if pending == 0 {
+				if err, ok := errs[false]; ok {
+					return nil, err
+				}
+				return nil, result.err
+			}
+		}
+	}
+}
+
+// hedgeDo sends req with ctx, the second request of a hedge gets through
+// the Limiter and a DPoP proof of its own first
+func (c *Client) hedgeDo(ctx context.Context, policy *HedgePolicy, req *http.Request, endpoint string, hedge bool) (*http.Response, error) {
+	if !hedge {
+		return c.client.Do(req.WithContext(ctx))
+	}
+	if err := c.limit(ctx, req.Method, endpoint); err != nil {
+		return nil, err
+	}
+	clone := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	if req.Header.Get("DPoP") != "" {
+		// a DPoP proof is only accepted once
+		if err := c.authorize(ctx, clone); err != nil {
+			return nil, err
+		}
+	}
+	return c.client.Do(clone)
+}
+
+// drainHedge closes the responses of the requests that lost the race
+func drainHedge(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		result := <-results
+		if result.resp != nil {
+			io.Copy(ioutil.Discard, result.resp.Body)
+			result.resp.Body.Close()
+		}
+	}
+}
+
+// cancelBody cancels the context of a hedged request once its response
+// is read
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,86 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	var requests int64
+	cancelled := make(chan struct{}, 1)
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Limit", "100")
+		w.Header().Set("X-Rate-Limit-Reset", "4102444800")
+		if r.URL.Path == "/api/v1/users/slow" && atomic.AddInt64(&requests, 1) == 1 {
+			// the first request hangs until it is cancelled
+			select {
+			case <-r.Context().Done():
+				cancelled <- struct{}{}
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		if r.URL.Path == "/api/v1/users/limited" {
+			atomic.AddInt64(&requests, 1)
+			w.Header().Set("X-Rate-Limit-Remaining", "10")
+			time.Sleep(100 * time.Millisecond)
+		} else {
+			w.Header().Set("X-Rate-Limit-Remaining", "90")
+		}
+		w.Write([]byte(`{"id":"00u2"}`))
+	}))
+	defer done()
+	client.Hedge = &HedgePolicy{Delay: 20 * time.Millisecond}
+
+	started := time.Now()
+	user, err := client.User("slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "00u2" || time.Since(started) > 2*time.Second {
+		t.Error("Expected the hedged response, got ", user.ID, time.Since(started))
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the slow request to be cancelled")
+	}
+
+	// a bucket above the reserve is not hedged
+	atomic.StoreInt64(&requests, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := client.User("limited"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt64(&requests); n != 3 {
+		t.Error("Expected only the first call to be hedged, got requests ", n)
+	}
+}
+
+func TestWithHedging(t *testing.T) {
+	var requests int64
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"id":"00u1"}`))
+	}))
+	defer done()
+
+	if _, err := client.UserContext(context.Background(), "00u1"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithCallOptions(context.Background(), WithHedging(10*time.Millisecond))
+	if _, err := client.UserContext(ctx, "00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if err, _ := client.callContext(ctx, "users", "POST", &CreateUserRequest{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt64(&requests); n != 4 {
+		t.Error("Expected only the GET of the hedging context to be sent twice, got requests ", n)
+	}
+}
@@ -0,0 +1,344 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Inline hook types supported by this client
+const (
+	TokenInlineHook          = "com.okta.oauth2.tokens.transform"
+	RegistrationInlineHook   = "com.okta.user.pre-registration"
+	PasswordImportInlineHook = "com.okta.user.credential.password.import"
+)
+
+type InlineHook struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Version     string     `json:"version"`
+	Status      string     `json:"status,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Channel     struct {
+		Type    string `json:"type"`
+		Version string `json:"version"`
+		Config  struct {
+			URI     string `json:"uri"`
+			Method  string `json:"method,omitempty"`
+			Headers []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"headers,omitempty"`
+			// same scheme as event hooks
+			AuthScheme *EventHookAuthScheme `json:"authScheme,omitempty"`
+		} `json:"config"`
+	} `json:"channel"`
+}
+
+// NewInlineHook returns an inline hook of hookType calling uri with secret
+// in the Authorization header
+func NewInlineHook(name, hookType, uri, secret string) *InlineHook {
+	hook := &InlineHook{
+		Name:    name,
+		Type:    hookType,
+		Version: "1.0.0",
+	}
+	hook.Channel.Type = "HTTP"
+	hook.Channel.Version = "1.0.0"
+	hook.Channel.Config.URI = uri
+	hook.Channel.Config.Method = "POST"
+	if secret != "" {
+		hook.Channel.Config.AuthScheme = &EventHookAuthScheme{
+			Type:  "HEADER",
+			Key:   "Authorization",
+			Value: secret,
+		}
+	}
+	return hook
+}
+
+// InlineHooks returns the inline hooks of the org, limited to hookType
+// unless it is empty
+func (c *Client) InlineHooks(hookType string) (*[]InlineHook, error) {
+	u := "inlineHooks"
+	if len(hookType) > 0 {
+		v := &url.Values{}
+		v.Add("type", hookType)
+		u += "?" + v.Encode()
+	}
+
+	var response = &[]InlineHook{}
+	err := c.listAll(u, response)
+	return response, err
+}
+
+// InlineHook takes an inline hook id and returns it
+func (c *Client) InlineHook(hookID string) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID), "GET", nil, response)
+	return response, err
+}
+
+// CreateInlineHook registers a new inline hook
+func (c *Client) CreateInlineHook(hook *InlineHook) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err, _ := c.call("inlineHooks", "POST", hook, response)
+	return response, err
+}
+
+// UpdateInlineHook replaces the inline hook with the given id
+func (c *Client) UpdateInlineHook(hookID string, hook *InlineHook) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID), "PUT", hook, response)
+	return response, err
+}
+
+// DeleteInlineHook deletes a deactivated inline hook
+func (c *Client) DeleteInlineHook(hookID string) error {
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID), "DELETE", nil, nil)
+	return err
+}
+
+// ActivateInlineHook makes the inline hook available to policies
+func (c *Client) ActivateInlineHook(hookID string) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID)+"/lifecycle/activate", "POST", nil, response)
+	return response, err
+}
+
+// DeactivateInlineHook stops Okta from calling the inline hook
+func (c *Client) DeactivateInlineHook(hookID string) (*InlineHook, error) {
+	var response = &InlineHook{}
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID)+"/lifecycle/deactivate", "POST", nil, response)
+	return response, err
+}
+
+// ExecuteInlineHook sends a sample request to the hook endpoint and returns
+// the hook's response, useful to test a hook before activating it
+func (c *Client) ExecuteInlineHook(hookID string, request interface{}) (*InlineHookResponse, error) {
+	var response = &InlineHookResponse{}
+	err, _ := c.call("inlineHooks/"+url.PathEscape(hookID)+"/execute", "POST", request, response)
+	return response, err
+}
+
+// InlineHookRequest is the envelope common to every request Okta sends to
+// an inline hook endpoint
+type InlineHookRequest struct {
+	Source            string     `json:"source"`
+	EventID           string     `json:"eventId"`
+	EventTime         *time.Time `json:"eventTime"`
+	EventType         string     `json:"eventType"`
+	EventTypeVersion  string     `json:"eventTypeVersion"`
+	CloudEventVersion string     `json:"cloudEventVersion"`
+	ContentType       string     `json:"contentType"`
+}
+
+// TokenInlineHookRequest is sent before Okta mints ID and access tokens
+type TokenInlineHookRequest struct {
+	InlineHookRequest
+	Data struct {
+		Context struct {
+			Request struct {
+				ID     string `json:"id"`
+				Method string `json:"method"`
+				URL    struct {
+					Value string `json:"value"`
+				} `json:"url"`
+				IPAddress string `json:"ipAddress"`
+			} `json:"request"`
+			Protocol struct {
+				Type    string `json:"type"`
+				Request struct {
+					Scope        string `json:"scope"`
+					State        string `json:"state"`
+					RedirectURI  string `json:"redirect_uri"`
+					ResponseMode string `json:"response_mode"`
+					ResponseType string `json:"response_type"`
+					ClientID     string `json:"client_id"`
+				} `json:"request"`
+				Issuer struct {
+					URI string `json:"uri"`
+				} `json:"issuer"`
+				Client struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+					Type string `json:"type"`
+				} `json:"client"`
+			} `json:"protocol"`
+			Session struct {
+				ID     string `json:"id"`
+				UserID string `json:"userId"`
+				Login  string `json:"login"`
+				Status string `json:"status"`
+			} `json:"session"`
+			User struct {
+				ID              string     `json:"id"`
+				PasswordChanged *time.Time `json:"passwordChanged"`
+				Profile         struct {
+					Login     string `json:"login"`
+					FirstName string `json:"firstName"`
+					LastName  string `json:"lastName"`
+					Locale    string `json:"locale"`
+					TimeZone  string `json:"timeZone"`
+				} `json:"profile"`
+			} `json:"user"`
+			Policy struct {
+				ID   string `json:"id"`
+				Rule struct {
+					ID string `json:"id"`
+				} `json:"rule"`
+			} `json:"policy"`
+		} `json:"context"`
+		Identity struct {
+			Claims map[string]interface{} `json:"claims"`
+			Token  struct {
+				Lifetime struct {
+					Expiration int `json:"expiration"`
+				} `json:"lifetime"`
+			} `json:"token"`
+		} `json:"identity"`
+		Access struct {
+			Claims map[string]interface{} `json:"claims"`
+			Token  struct {
+				Lifetime struct {
+					Expiration int `json:"expiration"`
+				} `json:"lifetime"`
+			} `json:"token"`
+			Scopes map[string]struct {
+				ID     string `json:"id"`
+				Action string `json:"action"`
+			} `json:"scopes"`
+		} `json:"access"`
+	} `json:"data"`
+}
+
+// RegistrationInlineHookRequest is sent when a user self-registers
+type RegistrationInlineHookRequest struct {
+	InlineHookRequest
+	RequestType string `json:"requestType"`
+	Data        struct {
+		Context struct {
+			Request struct {
+				ID        string `json:"id"`
+				Method    string `json:"method"`
+				IPAddress string `json:"ipAddress"`
+			} `json:"request"`
+		} `json:"context"`
+		UserProfile map[string]interface{} `json:"userProfile"`
+		Action      string                 `json:"action"`
+	} `json:"data"`
+}
+
+// PasswordImportInlineHookRequest is sent when a user whose password was
+// not imported signs in for the first time, the hook verifies the
+// credential against the legacy store
+type PasswordImportInlineHookRequest struct {
+	InlineHookRequest
+	Data struct {
+		Context struct {
+			Request struct {
+				ID        string `json:"id"`
+				Method    string `json:"method"`
+				IPAddress string `json:"ipAddress"`
+			} `json:"request"`
+			Credential struct {
+				Username string `json:"username"`
+				Password string `json:"password"`
+			} `json:"credential"`
+		} `json:"context"`
+		Action struct {
+			Credential string `json:"credential"`
+		} `json:"action"`
+	} `json:"data"`
+}
+
+// InlineHookResponse is what an inline hook endpoint answers with
+type InlineHookResponse struct {
+	Commands     []InlineHookCommand    `json:"commands,omitempty"`
+	Error        *InlineHookError       `json:"error,omitempty"`
+	DebugContext map[string]interface{} `json:"debugContext,omitempty"`
+}
+
+type InlineHookCommand struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// InlineHookPatch is a JSON patch operation used by token hook commands
+type InlineHookPatch struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type InlineHookError struct {
+	ErrorSummary string `json:"errorSummary"`
+	ErrorCauses  []struct {
+		ErrorSummary string `json:"errorSummary"`
+		Reason       string `json:"reason"`
+		LocationType string `json:"locationType"`
+		Location     string `json:"location"`
+		Domain       string `json:"domain"`
+	} `json:"errorCauses,omitempty"`
+}
+
+// AddAccessTokenClaim adds a claim to the access token minted by Okta
+func (r *InlineHookResponse) AddAccessTokenClaim(name string, value interface{}) {
+	r.patch("com.okta.access.patch", "/claims/"+name, value)
+}
+
+// AddIDTokenClaim adds a claim to the ID token minted by Okta
+func (r *InlineHookResponse) AddIDTokenClaim(name string, value interface{}) {
+	r.patch("com.okta.identity.patch", "/claims/"+name, value)
+}
+
+func (r *InlineHookResponse) patch(commandType, path string, value interface{}) {
+	op := InlineHookPatch{Op: "add", Path: path, Value: value}
+	for i := range r.Commands {
+		if r.Commands[i].Type == commandType {
+			if patches, ok := r.Commands[i].Value.([]InlineHookPatch); ok {
+				r.Commands[i].Value = append(patches, op)
+				return
+			}
+		}
+	}
+	r.Commands = append(r.Commands, InlineHookCommand{
+		Type:  commandType,
+		Value: []InlineHookPatch{op},
+	})
+}
+
+// AllowRegistration allows or denies a self-registration
+func (r *InlineHookResponse) AllowRegistration(allow bool) {
+	registration := "DENY"
+	if allow {
+		registration = "ALLOW"
+	}
+	r.Commands = append(r.Commands, InlineHookCommand{
+		Type:  "com.okta.action.update",
+		Value: map[string]string{"registration": registration},
+	})
+}
+
+// UpdateRegistrationProfile changes attributes of the registering user's
+// profile before the user is created
+func (r *InlineHookResponse) UpdateRegistrationProfile(profile map[string]interface{}) {
+	r.Commands = append(r.Commands, InlineHookCommand{
+		Type:  "com.okta.user.profile.update",
+		Value: profile,
+	})
+}
+
+// VerifyPasswordImport tells Okta whether the imported credential is valid,
+// a verified password is stored and the hook is not called again for the user
+func (r *InlineHookResponse) VerifyPasswordImport(verified bool) {
+	credential := "UNVERIFIED"
+	if verified {
+		credential = "VERIFIED"
+	}
+	r.Commands = append(r.Commands, InlineHookCommand{
+		Type:  "com.okta.action.update",
+		Value: map[string]string{"credential": credential},
+	})
+}
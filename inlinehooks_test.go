@@ -0,0 +1,170 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestInlineHooks(t *testing.T) {
+	var created InlineHook
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/inlineHooks":
+			w.Write([]byte(`[{"id":"cal1","name":"Claims","type":"com.okta.oauth2.tokens.transform","version":"1.0.0","status":"ACTIVE",
+				"channel":{"type":"HTTP","version":"1.0.0","config":{"uri":"https://hooks.example.com/token","method":"POST"}}}]`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/inlineHooks":
+			json.NewDecoder(r.Body).Decode(&created)
+			created.ID = "cal2"
+			json.NewEncoder(w).Encode(created)
+		case r.URL.Path == "/api/v1/inlineHooks/cal2/execute":
+			w.Write([]byte(`{"commands":[{"type":"com.okta.action.update","value":{"credential":"VERIFIED"}}]}`))
+		case r.URL.Path == "/api/v1/inlineHooks/cal2/lifecycle/deactivate":
+			w.Write([]byte(`{"id":"cal2","status":"INACTIVE"}`))
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"id":"cal2","status":"ACTIVE"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	hooks, err := client.InlineHooks(TokenInlineHook)
+	if err != nil || len(*hooks) != 1 || (*hooks)[0].Channel.Config.URI != "https://hooks.example.com/token" {
+		t.Fatal("Expected the token hook, got ", hooks, err)
+	}
+	hook, err := client.CreateInlineHook(NewInlineHook("Import", PasswordImportInlineHook, "https://hooks.example.com/import", "secret"))
+	if err != nil || hook.ID != "cal2" {
+		t.Fatal("Expected the created hook, got ", hook, err)
+	}
+	if created.Channel.Config.AuthScheme == nil || created.Channel.Config.AuthScheme.Value != "secret" || created.Channel.Config.Method != "POST" {
+		t.Error("Expected the secret in the Authorization header, got ", created.Channel.Config)
+	}
+	if _, err := client.InlineHook("cal2"); err != nil {
+		t.Fatal(err)
+	}
+	hook.Name = "Legacy import"
+	if _, err := client.UpdateInlineHook("cal2", hook); err != nil {
+		t.Fatal(err)
+	}
+	if hook, err := client.ActivateInlineHook("cal2"); err != nil || hook.Status != "ACTIVE" {
+		t.Fatal("Expected the hook to be activated, got ", hook, err)
+	}
+	response, err := client.ExecuteInlineHook("cal2", &PasswordImportInlineHookRequest{})
+	if err != nil || len(response.Commands) != 1 || response.Commands[0].Type != "com.okta.action.update" {
+		t.Fatal("Expected the response of the hook, got ", response, err)
+	}
+	if hook, err := client.DeactivateInlineHook("cal2"); err != nil || hook.Status != "INACTIVE" {
+		t.Fatal("Expected the hook to be deactivated, got ", hook, err)
+	}
+	if err := client.DeleteInlineHook("cal2"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/inlineHooks?type=com.okta.oauth2.tokens.transform",
+		"POST /api/v1/inlineHooks",
+		"GET /api/v1/inlineHooks/cal2",
+		"PUT /api/v1/inlineHooks/cal2",
+		"POST /api/v1/inlineHooks/cal2/lifecycle/activate",
+		"POST /api/v1/inlineHooks/cal2/execute",
+		"POST /api/v1/inlineHooks/cal2/lifecycle/deactivate",
+		"DELETE /api/v1/inlineHooks/cal2",
+	)
+}
+
+func TestTokenInlineHookRequest(t *testing.T) {
+	data := []byte(`{
+		"source":"https://example.okta.com/oauth2/default/v1/authorize",
+		"eventId":"3OWo4oo-QQ-rBWfRyTmQYw",
+		"eventTime":"2019-01-15T23:20:47.000Z",
+		"eventTypeVersion":"1.0",
+		"cloudEventVersion":"0.1",
+		"contentType":"application/json",
+		"eventType":"com.okta.oauth2.tokens.transform",
+		"data":{
+			"context":{
+				"request":{"id":"reqv66CbCaCStGEFc8AdfS0ng","method":"GET","ipAddress":"127.0.0.1",
+					"url":{"value":"https://example.okta.com/oauth2/default/v1/authorize?scope=openid"}},
+				"protocol":{"type":"OAUTH2.0",
+					"request":{"scope":"openid profile","state":"foo","redirect_uri":"https://app.example.com/callback","response_mode":"form_post","response_type":"id_token token","client_id":"0oabskvc6442nkvQO0h7"},
+					"issuer":{"uri":"https://example.okta.com/oauth2/default"},
+					"client":{"id":"0oabskvc6442nkvQO0h7","name":"Portal","type":"PUBLIC"}},
+				"session":{"id":"102Qoe7t5PcRnSxr8j3I8I6pA","userId":"00u1","login":"jane@example.com","status":"ACTIVE"},
+				"user":{"id":"00u1","passwordChanged":"2018-09-11T23:19:12.000Z",
+					"profile":{"login":"jane@example.com","firstName":"Jane","lastName":"Doe","locale":"en","timeZone":"America/Los_Angeles"}},
+				"policy":{"id":"00p1","rule":{"id":"0pr1"}}
+			},
+			"identity":{"claims":{"sub":"00u1","email":"jane@example.com"},"token":{"lifetime":{"expiration":3600}}},
+			"access":{"claims":{"ver":1,"sub":"jane@example.com"},"token":{"lifetime":{"expiration":3600}},
+				"scopes":{"openid":{"id":"scp1","action":"GRANT"}}}
+		}
+	}`)
+
+	var request TokenInlineHookRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatal(err)
+	}
+	context := request.Data.Context
+	if request.EventType != TokenInlineHook || request.EventTime == nil || request.EventTime.Year() != 2019 {
+		t.Error("Expected the envelope, got ", request.InlineHookRequest)
+	}
+	if context.Protocol.Client.ID != "0oabskvc6442nkvQO0h7" || context.Protocol.Request.RedirectURI != "https://app.example.com/callback" ||
+		context.User.Profile.Login != "jane@example.com" || context.Policy.Rule.ID != "0pr1" {
+		t.Errorf("Unexpected context %+v", context)
+	}
+	if request.Data.Access.Scopes["openid"].Action != "GRANT" || request.Data.Identity.Claims["email"] != "jane@example.com" ||
+		request.Data.Access.Token.Lifetime.Expiration != 3600 {
+		t.Errorf("Unexpected tokens %+v %+v", request.Data.Identity, request.Data.Access)
+	}
+
+	var response InlineHookResponse
+	response.AddAccessTokenClaim("groups", []string{"Everyone"})
+	response.AddAccessTokenClaim("tier", "gold")
+	response.AddIDTokenClaim("tier", "gold")
+	if len(response.Commands) != 2 {
+		t.Fatal("Expected a command per token, got ", response.Commands)
+	}
+	if patches := response.Commands[0].Value.([]InlineHookPatch); response.Commands[0].Type != "com.okta.access.patch" || len(patches) != 2 || patches[1].Path != "/claims/tier" {
+		t.Error("Expected both access token claims in one command, got ", response.Commands[0])
+	}
+}
+
+func TestPasswordImportInlineHookRequest(t *testing.T) {
+	data := []byte(`{
+		"eventId":"3OWo4oo-QQ-rBWfRyTmQYw",
+		"eventTime":"2020-01-17T21:23:56.000Z",
+		"eventType":"com.okta.user.credential.password.import",
+		"eventTypeVersion":"1.0",
+		"contentType":"application/json",
+		"cloudEventVersion":"0.1",
+		"source":"https://example.okta.com/api/v1/inlineHooks/cal2",
+		"data":{
+			"context":{
+				"request":{"id":"reqv66CbCaCStGEFc8AdfS0ng","method":"POST","ipAddress":"127.0.0.1"},
+				"credential":{"username":"jane@example.com","password":"Legacy1234"}
+			},
+			"action":{"credential":"UNVERIFIED"}
+		}
+	}`)
+
+	var request PasswordImportInlineHookRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatal(err)
+	}
+	if request.EventType != PasswordImportInlineHook || request.Data.Context.Credential.Username != "jane@example.com" ||
+		request.Data.Context.Credential.Password != "Legacy1234" || request.Data.Action.Credential != "UNVERIFIED" {
+		t.Errorf("Unexpected request %+v", request)
+	}
+
+	var response InlineHookResponse
+	response.VerifyPasswordImport(true)
+	body, err := json.Marshal(&response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"commands":[{"type":"com.okta.action.update","value":{"credential":"VERIFIED"}}]}` {
+		t.Error("Unexpected response ", string(body))
+	}
+}
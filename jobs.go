@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultJobPollInterval = time.Second
+	maxJobPollInterval     = 30 * time.Second
+)
+
+// Job states, the last four are final
+const (
+	JobQueued     = "QUEUED"
+	JobInProgress = "IN_PROGRESS"
+	JobCompleted  = "COMPLETED"
+	JobFailed     = "FAILED"
+	JobCancelled  = "CANCELLED"
+	JobExpired    = "EXPIRED"
+)
+
+// Job is an asynchronous operation of the org such as a bulk import or
+// delete, referenced by id by the operation that started it
+type Job struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Created     *time.Time `json:"created"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+	// Progress is the percentage done, if the job reports it
+	Progress int `json:"progress"`
+	Error    *struct {
+		ErrorCode    string `json:"errorCode"`
+		ErrorSummary string `json:"errorSummary"`
+	} `json:"error"`
+}
+
+// Done tells whether the job reached a final state
+func (j *Job) Done() bool {
+	switch j.Status {
+	case JobCompleted, JobFailed, JobCancelled, JobExpired:
+		return true
+	}
+	return false
+}
+
+// JobError is returned by PollJob for a job that didn't complete
+type JobError struct {
+	Job *Job
+}
+
+func (e *JobError) Error() string {
+	if e.Job.Error != nil && e.Job.Error.ErrorSummary != "" {
+		return fmt.Sprintf("okta: job %s %s: %s", e.Job.ID, e.Job.Status, e.Job.Error.ErrorSummary)
+	}
+	return fmt.Sprintf("okta: job %s %s", e.Job.ID, e.Job.Status)
+}
+
+// GetJob returns the job with the given id
+func (c *Client) GetJob(jobID string) (*Job, error) {
+	return c.GetJobContext(context.Background(), jobID)
+}
+
+// GetJobContext is GetJob honouring the deadline and call options of ctx
+func (c *Client) GetJobContext(ctx context.Context, jobID string) (*Job, error) {
+	var response = &Job{}
+	err, _ := c.callContext(ctx, "jobs/"+url.PathEscape(jobID), "GET", nil, response)
+	return response, err
+}
+
+// PollJob waits until the job with the given id is done and returns it,
+// polling every interval at first, 1 second when zero, and backing off to
+// 30 seconds for long running jobs. Failed polls are retried when their
+// error is retryable. A job that doesn't complete is returned with a
+// JobError.
+func (c *Client) PollJob(ctx context.Context, jobID string, interval time.Duration) (*Job, error) {
+	if interval == 0 {
+		interval = defaultJobPollInterval
+	}
+	policy := &RetryPolicy{Backoff: interval}
+
+	for attempt := 1; ; attempt++ {
+		job, err := c.GetJobContext(ctx, jobID)
+		switch {
+		case err != nil && (ctx.Err() != nil || !ClassOf(err).Retryable):
+			return nil, err
+		case err == nil && job.Done():
+			if job.Status != JobCompleted {
+				return job, &JobError{Job: job}
+			}
+			return job, nil
+		}
+
+		wait := maxJobPollInterval
+		if attempt < 16 {
+			if backoff := policy.backoff(attempt); backoff < wait {
+				wait = backoff
+			}
+		}
+		if err := (RetryEvent{Sleep: wait}).wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPollJob(t *testing.T) {
+	polls := 0
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch {
+		case r.URL.Path == "/api/v1/jobs/job2":
+			w.Write([]byte(`{"id":"job2","status":"FAILED","error":{"errorCode":"E0000001","errorSummary":"Api validation failed"}}`))
+		case polls == 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errorCode":"E0000009"}`))
+		case polls < 4:
+			w.Write([]byte(`{"id":"job1","status":"IN_PROGRESS","progress":40}`))
+		default:
+			w.Write([]byte(`{"id":"job1","status":"COMPLETED","progress":100}`))
+		}
+	}))
+	defer done()
+
+	job, err := client.PollJob(context.Background(), "job1", time.Millisecond)
+	if err != nil || job.Status != JobCompleted || polls != 4 {
+		t.Fatal("Expected the job to complete after 4 polls, got ", job, err, polls)
+	}
+
+	job, err = client.PollJob(context.Background(), "job2", time.Millisecond)
+	if jobErr, ok := err.(*JobError); !ok || jobErr.Job.Status != JobFailed || job == nil {
+		t.Fatal("Expected a JobError, got ", err)
+	}
+	if expected := "okta: job job2 FAILED: Api validation failed"; err.Error() != expected {
+		t.Error("Expected ", expected, ", got ", err)
+	}
+}
@@ -0,0 +1,153 @@
+package okta
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// KeychainKey is a KeyProvider keeping a random key in the OS keychain,
+// the key is generated on first use. The macOS Keychain is used through
+// security(1) and the Secret Service on Linux through secret-tool(1), the
+// key is never passed on a command line.
+type KeychainKey struct {
+	Service string
+	Account string
+
+	mu  sync.Mutex
+	key []byte
+
+	// goos and run are runtime.GOOS and runCommand when unset, for tests
+	goos string
+	run  func(stdin, name string, args ...string) (stdout, stderr []byte, status int, err error)
+}
+
+// NewKeychainKey returns a KeychainKey for the given service and account
+func NewKeychainKey(service, account string) *KeychainKey {
+	return &KeychainKey{Service: service, Account: account}
+}
+
+func (k *KeychainKey) Key() ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.key != nil {
+		return k.key, nil
+	}
+
+	encoded, err := k.read()
+	if err == errKeychainItemNotFound {
+		key := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, err
+		}
+		encoded = base64.StdEncoding.EncodeToString(key)
+		if err := k.write(encoded); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("okta: keychain item %s/%s is not a key", k.Service, k.Account)
+	}
+	k.key = key
+	return key, nil
+}
+
+var errKeychainItemNotFound = errors.New("okta: keychain item not found")
+
+func (k *KeychainKey) read() (string, error) {
+	var name string
+	var args []string
+	goos := k.platform()
+	switch goos {
+	case "darwin":
+		name, args = "security", []string{"find-generic-password", "-s", k.Service, "-a", k.Account, "-w"}
+	case "linux":
+		name, args = "secret-tool", []string{"lookup", "service", k.Service, "account", k.Account}
+	default:
+		return "", fmt.Errorf("okta: no keychain support on %s, use StaticKey", goos)
+	}
+
+	out, stderr, status, err := k.command("", name, args...)
+	if err != nil {
+		return "", fmt.Errorf("okta: reading key from keychain: %v", err)
+	}
+	empty := len(bytes.TrimSpace(out)) == 0
+	switch {
+	case status == 0 && !empty:
+		return string(out), nil
+	case status == 0,
+		// errSecItemNotFound
+		goos == "darwin" && status == 44,
+		// secret-tool has no distinct status, a missing item prints nothing
+		goos == "linux" && status == 1 && empty && len(bytes.TrimSpace(stderr)) == 0:
+		return "", errKeychainItemNotFound
+	}
+	return "", fmt.Errorf("okta: reading key from keychain: exit status %d: %s", status, bytes.TrimSpace(stderr))
+}
+
+func (k *KeychainKey) write(encoded string) error {
+	var stdin, name string
+	var args []string
+	goos := k.platform()
+	switch goos {
+	case "darwin":
+		// commands read from stdin keep the secret out of the process list
+		name, args = "security", []string{"-i"}
+		stdin = fmt.Sprintf("add-generic-password -U -s %q -a %q -w %q\n", k.Service, k.Account, encoded)
+	case "linux":
+		name, args = "secret-tool", []string{"store", "--label", k.Service, "service", k.Service, "account", k.Account}
+		stdin = encoded
+	default:
+		return fmt.Errorf("okta: no keychain support on %s, use StaticKey", goos)
+	}
+
+	out, stderr, status, err := k.command(stdin, name, args...)
+	if err != nil {
+		return fmt.Errorf("okta: storing key in keychain: %v", err)
+	}
+	if status != 0 {
+		return fmt.Errorf("okta: storing key in keychain: exit status %d: %s", status, bytes.TrimSpace(append(out, stderr...)))
+	}
+	return nil
+}
+
+func (k *KeychainKey) platform() string {
+	if k.goos != "" {
+		return k.goos
+	}
+	return runtime.GOOS
+}
+
+func (k *KeychainKey) command(stdin, name string, args ...string) ([]byte, []byte, int, error) {
+	if k.run != nil {
+		return k.run(stdin, name, args...)
+	}
+	return runCommand(stdin, name, args...)
+}
+
+// runCommand runs name with stdin, err is only set when it could not be
+// run, a failure is reported by the exit status
+func runCommand(stdin, name string, args ...string) (stdout, stderr []byte, status int, err error) {
+	var out, errOut bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err = cmd.Run()
+	if exit, ok := err.(*exec.ExitError); ok {
+		return out.Bytes(), errOut.Bytes(), exit.ExitCode(), nil
+	}
+	return out.Bytes(), errOut.Bytes(), 0, err
+}
@@ -0,0 +1,67 @@
+package okta
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+type fakeKeychain struct {
+	goos   string
+	stdout string
+	stderr string
+	status int
+	writes []string
+}
+
+func (f *fakeKeychain) key() *KeychainKey {
+	return &KeychainKey{
+		Service: "okta",
+		Account: "cache",
+		goos:    f.goos,
+		run: func(stdin, name string, args ...string) ([]byte, []byte, int, error) {
+			if args[0] == "find-generic-password" || args[0] == "lookup" {
+				return []byte(f.stdout), []byte(f.stderr), f.status, nil
+			}
+			f.writes = append(f.writes, stdin)
+			return nil, nil, 0, nil
+		},
+	}
+}
+
+func TestKeychainKey(t *testing.T) {
+	stored := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	tests := []struct {
+		name     string
+		keychain fakeKeychain
+		created  bool
+		err      bool
+	}{
+		{"darwin found", fakeKeychain{goos: "darwin", stdout: stored + "\n"}, false, false},
+		{"darwin not found", fakeKeychain{goos: "darwin", status: 44, stderr: "security: SecKeychainSearchCopyNext: The specified item could not be found in the keychain."}, true, false},
+		{"darwin locked", fakeKeychain{goos: "darwin", status: 36, stderr: "security: User interaction is not allowed."}, false, true},
+		{"linux found", fakeKeychain{goos: "linux", stdout: stored}, false, false},
+		{"linux not found", fakeKeychain{goos: "linux", status: 1}, true, false},
+		{"linux no secret service", fakeKeychain{goos: "linux", status: 1, stderr: "secret-tool: Cannot autolaunch D-Bus without X11 $DISPLAY"}, false, true},
+		{"linux failure", fakeKeychain{goos: "linux", status: 2}, false, true},
+	}
+	for _, test := range tests {
+		keychain := test.keychain
+		key, err := keychain.key().Key()
+		if test.err {
+			if err == nil || len(keychain.writes) != 0 {
+				t.Error(test.name, ": expected an error without storing a key, got ", err, keychain.writes)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(test.name, ": ", err)
+			continue
+		}
+		if len(key) != 32 || (len(keychain.writes) == 1) != test.created {
+			t.Error(test.name, ": unexpected key ", key, " stored ", keychain.writes)
+		}
+		if !test.created && string(key) != "0123456789abcdef0123456789abcdef" {
+			t.Error(test.name, ": expected the stored key, got ", key)
+		}
+	}
+}
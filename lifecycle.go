@@ -0,0 +1,128 @@
+package okta
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// ActivationLink is returned when activating a user without sending the
+// activation email, the link has to be handed to the user instead
+type ActivationLink struct {
+	ActivationURL   string `json:"activationUrl"`
+	ActivationToken string `json:"activationToken"`
+}
+
+// ResetPasswordLink is returned when resetting a password without sending
+// the email
+type ResetPasswordLink struct {
+	ResetPasswordURL string `json:"resetPasswordUrl"`
+}
+
+// TempPassword is the one-time password of a user whose password expired
+// with ExpirePasswordWithTempPassword
+type TempPassword struct {
+	TempPassword string `json:"tempPassword"`
+}
+
+// ActivateUser activates a STAGED or DEPROVISIONED user. The activation
+// email is sent if sendEmail is true, otherwise the returned link has to be
+// handed to the user.
+func (c *Client) ActivateUser(userID string, sendEmail bool) (*ActivationLink, error) {
+	v := &url.Values{}
+	v.Add("sendEmail", strconv.FormatBool(sendEmail))
+
+	var response = &ActivationLink{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/activate?"+v.Encode(), "POST", nil, response)
+	return response, err
+}
+
+// ReactivateUser sends a new activation email to a PROVISIONED user, or
+// returns the link if sendEmail is false
+func (c *Client) ReactivateUser(userID string, sendEmail bool) (*ActivationLink, error) {
+	v := &url.Values{}
+	v.Add("sendEmail", strconv.FormatBool(sendEmail))
+
+	var response = &ActivationLink{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/reactivate?"+v.Encode(), "POST", nil, response)
+	return response, err
+}
+
+// CreateUserWithActivationLink creates a user and activates it without
+// sending the activation email, so that the returned link can be sent in
+// an onboarding email of your own. ReactivateUser returns a new link once
+// it expired. The user is returned as created, STAGED, and with the error
+// when activating it failed.
+func (c *Client) CreateUserWithActivationLink(user *CreateUserRequest) (*User, *ActivationLink, error) {
+	created, err := c.CreateUser(user, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	link, err := c.ActivateUser(created.ID, false)
+	if err != nil {
+		return created, nil, err
+	}
+	return created, link, nil
+}
+
+// DeactivateUser deactivates a user, it has to be deactivated before it can
+// be deleted
+func (c *Client) DeactivateUser(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/deactivate", "POST", nil, nil)
+	return err
+}
+
+// SuspendUser suspends an ACTIVE user, the user can't sign in until
+// unsuspended
+func (c *Client) SuspendUser(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/suspend", "POST", nil, nil)
+	return err
+}
+
+// UnsuspendUser returns a SUSPENDED user to ACTIVE
+func (c *Client) UnsuspendUser(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/unsuspend", "POST", nil, nil)
+	return err
+}
+
+// UnlockUser unlocks a LOCKED_OUT user, the user keeps their password
+func (c *Client) UnlockUser(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/unlock", "POST", nil, nil)
+	return err
+}
+
+// ExpirePassword expires the password of a user, who has to change it on
+// the next sign in
+func (c *Client) ExpirePassword(userID string) (*User, error) {
+	var response = &User{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/expire_password", "POST", nil, response)
+	return response, err
+}
+
+// ExpirePasswordWithTempPassword expires the password of a user and
+// replaces it with a temporary one to hand to the user
+func (c *Client) ExpirePasswordWithTempPassword(userID string) (*TempPassword, error) {
+	var response = &TempPassword{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/expire_password?tempPassword=true", "POST", nil, response)
+	return response, err
+}
+
+// ResetPasswordLink expires the password of a user like ResetPassword
+// without sending the email, the returned link has to be handed to the user
+func (c *Client) ResetPasswordLink(userID string) (*ResetPasswordLink, error) {
+	var response = &ResetPasswordLink{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/reset_password?sendEmail=false", "POST", nil, response)
+	return response, err
+}
+
+// ResetFactors resets every factor of a user, who has to enroll them again
+func (c *Client) ResetFactors(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/lifecycle/reset_factors", "POST", nil, nil)
+	return err
+}
+
+// DeleteUser deletes a DEPROVISIONED user, see DeactivateUser. Deleting an
+// active user deactivates it instead.
+func (c *Client) DeleteUser(userID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID), "DELETE", nil, nil)
+	return err
+}
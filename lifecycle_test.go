@@ -0,0 +1,72 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLifecycleResults(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users/00u1/lifecycle/activate":
+			w.Write([]byte(`{"activationUrl":"https://example.okta.com/welcome/XE6wE17zmphl3KqAPFxO","activationToken":"XE6wE17zmphl3KqAPFxO"}`))
+		case "/api/v1/users/00u1/lifecycle/expire_password":
+			w.Write([]byte(`{"tempPassword":"HR076gb6"}`))
+		case "/api/v1/users/00u1/lifecycle/reset_password":
+			w.Write([]byte(`{"resetPasswordUrl":"https://example.okta.com/reset_password/XE6wE17zmphl3KqAPFxO"}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	activation, err := client.ActivateUser("00u1", false)
+	if err != nil || activation.ActivationToken != "XE6wE17zmphl3KqAPFxO" {
+		t.Error("Expected the activation link, got ", activation, err)
+	}
+	temp, err := client.ExpirePasswordWithTempPassword("00u1")
+	if err != nil || temp.TempPassword != "HR076gb6" {
+		t.Error("Expected the temporary password, got ", temp, err)
+	}
+	reset, err := client.ResetPasswordLink("00u1")
+	if err != nil || reset.ResetPasswordURL == "" {
+		t.Error("Expected the reset password link, got ", reset, err)
+	}
+	if err := client.UnlockUser("00u1"); err != nil {
+		t.Error(err)
+	}
+
+	recorder.expect(t,
+		"POST /api/v1/users/00u1/lifecycle/activate?sendEmail=false",
+		"POST /api/v1/users/00u1/lifecycle/expire_password?tempPassword=true",
+		"POST /api/v1/users/00u1/lifecycle/reset_password?sendEmail=false",
+		"POST /api/v1/users/00u1/lifecycle/unlock",
+	)
+}
+
+func TestCreateUserWithActivationLink(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users":
+			w.Write([]byte(`{"id":"00u1","status":"STAGED"}`))
+		case "/api/v1/users/00u1/lifecycle/activate":
+			w.Write([]byte(`{"activationUrl":"https://example.okta.com/welcome/XE6wE17zmphl3KqAPFxO","activationToken":"XE6wE17zmphl3KqAPFxO"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	user, link, err := client.CreateUserWithActivationLink(&CreateUserRequest{Profile: map[string]string{"login": "jane@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "00u1" || link.ActivationURL != "https://example.okta.com/welcome/XE6wE17zmphl3KqAPFxO" {
+		t.Error("Expected the user and the activation link, got ", user, link)
+	}
+
+	recorder.expect(t,
+		"POST /api/v1/users?activate=false",
+		"POST /api/v1/users/00u1/lifecycle/activate?sendEmail=false",
+	)
+}
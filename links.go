@@ -0,0 +1,114 @@
+package okta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Link is a HAL link of an Okta resource
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Method is set on lifecycle links such as deactivate
+	Method string `json:"method,omitempty"`
+	Hints  struct {
+		Allow []string `json:"allow,omitempty"`
+	} `json:"hints,omitempty"`
+}
+
+// Links are the _links of a resource by relation, most relations hold a
+// single link but some such as logo hold several
+type Links map[string][]Link
+
+// UnmarshalJSON accepts both single links and arrays of links
+func (l *Links) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	links := Links{}
+	for rel, value := range raw {
+		value = bytes.TrimSpace(value)
+		if len(value) > 0 && value[0] == '[' {
+			var many []Link
+			if err := json.Unmarshal(value, &many); err != nil {
+				return err
+			}
+			links[rel] = many
+			continue
+		}
+		var one Link
+		if err := json.Unmarshal(value, &one); err != nil {
+			return err
+		}
+		links[rel] = []Link{one}
+	}
+	*l = links
+	return nil
+}
+
+// Get returns the first link of a relation
+func (l Links) Get(rel string) (Link, bool) {
+	if len(l[rel]) == 0 {
+		return Link{}, false
+	}
+	return l[rel][0], true
+}
+
+// method is the HTTP method the link expects, GET unless it says otherwise
+func (l Link) method() string {
+	if l.Method != "" {
+		return strings.ToUpper(l.Method)
+	}
+	if len(l.Hints.Allow) > 0 {
+		return strings.ToUpper(l.Hints.Allow[0])
+	}
+	return "GET"
+}
+
+// FollowLink sends request to a link of a resource and decodes the result
+// into response, either may be nil. The method is the one advertised by the
+// link, so that workflows can be driven by the links Okta returns:
+//
+//	if link, ok := user.Links.All.Get("deactivate"); ok {
+//		err = client.FollowLink(link, nil, nil)
+//	}
+func (c *Client) FollowLink(link Link, request, response interface{}) error {
+	endpoint := linkEndpoint(link.Href)
+	if endpoint == "" {
+		return fmt.Errorf("okta: can't follow link %q", link.Href)
+	}
+	err, _ := c.call(endpoint, link.method(), request, response)
+	return err
+}
+
+// FollowRel follows the first link of a relation, see FollowLink
+func (c *Client) FollowRel(links Links, rel string, request, response interface{}) error {
+	link, ok := links.Get(rel)
+	if !ok {
+		return fmt.Errorf("okta: no %s link", rel)
+	}
+	return c.FollowLink(link, request, response)
+}
+
+// linkEndpoint returns the endpoint of an absolute link, relative to the
+// management API or starting with / for other APIs of the org
+func linkEndpoint(href string) string {
+	if endpoint := apiEndpoint(href); endpoint != "" {
+		return endpoint
+	}
+	u, err := url.Parse(href)
+	if err != nil || !strings.HasPrefix(u.EscapedPath(), "/") {
+		return ""
+	}
+	endpoint := u.EscapedPath()
+	if u.RawQuery != "" {
+		endpoint += "?" + u.RawQuery
+	}
+	return endpoint
+}
@@ -0,0 +1,58 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestFollowLink(t *testing.T) {
+	var server string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/users/00u1" {
+			w.Write([]byte(`{"id":"00u1","_links":{
+				"deactivate":{"href":"` + server + `/api/v1/users/00u1/lifecycle/deactivate","method":"POST"},
+				"suspend":{"href":"` + server + `/api/v1/users/00u1/lifecycle/suspend","method":"POST"},
+				"self":{"href":"` + server + `/api/v1/users/00u1"}
+			}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+	server = client.BaseURL
+
+	user, err := client.User("00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Links.Deactivate.Method != "POST" {
+		t.Error("Expected the typed deactivate link, got ", user.Links.Deactivate)
+	}
+	if err := client.FollowRel(user.Links.All, "suspend", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.FollowRel(user.Links.All, "unlock", nil, nil); err == nil {
+		t.Error("Expected an error for a missing relation")
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/users/00u1",
+		"POST /api/v1/users/00u1/lifecycle/suspend",
+	)
+}
+
+func TestLinksArrays(t *testing.T) {
+	var group Group
+	data := []byte(`{"_links":{"logo":[{"name":"medium","href":"https://example.com/medium.png"},{"name":"large","href":"https://example.com/large.png"}],"users":{"href":"https://example.com/api/v1/groups/00g1/users"}}}`)
+	if err := json.Unmarshal(data, &group); err != nil {
+		t.Fatal(err)
+	}
+	if len(group.Links["logo"]) != 2 || group.Links["logo"][1].Name != "large" {
+		t.Error("Expected both logos, got ", group.Links["logo"])
+	}
+	if users, ok := group.Links.Get("users"); !ok || linkEndpoint(users.Href) != "groups/00g1/users" {
+		t.Error("Expected the users link, got ", users)
+	}
+}
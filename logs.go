@@ -0,0 +1,203 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// logTimeFormat is the timestamp format expected by the since and until
+// parameters of the System Log API
+const logTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// LogEvent is an event of the System Log
+type LogEvent struct {
+	UUID            string     `json:"uuid"`
+	Published       *time.Time `json:"published"`
+	EventType       string     `json:"eventType"`
+	LegacyEventType string     `json:"legacyEventType"`
+	Version         string     `json:"version"`
+	Severity        string     `json:"severity"`
+	DisplayMessage  string     `json:"displayMessage"`
+	Actor           LogActor   `json:"actor"`
+	Client          LogClient  `json:"client"`
+	Outcome         struct {
+		Result string `json:"result"`
+		Reason string `json:"reason"`
+	} `json:"outcome"`
+	Target      []LogTarget `json:"target"`
+	Transaction struct {
+		// Type is WEB for requests of a browser or JOB for jobs
+		Type   string                 `json:"type"`
+		ID     string                 `json:"id"`
+		Detail map[string]interface{} `json:"detail"`
+	} `json:"transaction"`
+	DebugContext struct {
+		DebugData map[string]interface{} `json:"debugData"`
+	} `json:"debugContext"`
+	AuthenticationContext struct {
+		AuthenticationProvider string `json:"authenticationProvider"`
+		CredentialProvider     string `json:"credentialProvider"`
+		CredentialType         string `json:"credentialType"`
+		Issuer                 *struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"issuer"`
+		Interface          string `json:"interface"`
+		AuthenticationStep int    `json:"authenticationStep"`
+		ExternalSessionID  string `json:"externalSessionId"`
+	} `json:"authenticationContext"`
+	SecurityContext struct {
+		ASNumber int    `json:"asNumber"`
+		ASOrg    string `json:"asOrg"`
+		ISP      string `json:"isp"`
+		Domain   string `json:"domain"`
+		IsProxy  bool   `json:"isProxy"`
+	} `json:"securityContext"`
+	Request struct {
+		IPChain []LogIPAddress `json:"ipChain"`
+	} `json:"request"`
+}
+
+// LogActor is who performed the action of an event
+type LogActor struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	AlternateID string                 `json:"alternateId"`
+	DisplayName string                 `json:"displayName"`
+	DetailEntry map[string]interface{} `json:"detailEntry"`
+}
+
+// LogTarget is an entity an event acted on, e.g. a User or an AppInstance
+type LogTarget struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	AlternateID string                 `json:"alternateId"`
+	DisplayName string                 `json:"displayName"`
+	DetailEntry map[string]interface{} `json:"detailEntry"`
+}
+
+// LogClient is the client the actor used
+type LogClient struct {
+	ID        string `json:"id"`
+	Zone      string `json:"zone"`
+	Device    string `json:"device"`
+	IPAddress string `json:"ipAddress"`
+	UserAgent struct {
+		RawUserAgent string `json:"rawUserAgent"`
+		OS           string `json:"os"`
+		Browser      string `json:"browser"`
+	} `json:"userAgent"`
+	GeographicalContext *LogGeographicalContext `json:"geographicalContext"`
+}
+
+// LogGeographicalContext is where an IP address is located
+type LogGeographicalContext struct {
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Country     string `json:"country"`
+	PostalCode  string `json:"postalCode"`
+	Geolocation struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"geolocation"`
+}
+
+// LogIPAddress is an IP address the request of an event passed
+type LogIPAddress struct {
+	IP                  string                  `json:"ip"`
+	Version             string                  `json:"version"`
+	Source              string                  `json:"source"`
+	GeographicalContext *LogGeographicalContext `json:"geographicalContext"`
+}
+
+// Outcome results of an event
+const (
+	OutcomeSuccess   = "SUCCESS"
+	OutcomeFailure   = "FAILURE"
+	OutcomeSkipped   = "SKIPPED"
+	OutcomeAllow     = "ALLOW"
+	OutcomeDeny      = "DENY"
+	OutcomeChallenge = "CHALLENGE"
+	OutcomeUnknown   = "UNKNOWN"
+)
+
+// Event types of signing in
+const (
+	EventSessionStart   = "user.session.start"
+	EventAuthSSO        = "user.authentication.sso"
+	EventAuthVerify     = "user.authentication.verify"
+	EventSessionEnd     = "user.session.end"
+	EventAccountLocked  = "user.account.lock"
+	EventMFAVerify      = "user.authentication.auth_via_mfa"
+	EventPolicyEvaluate = "policy.evaluate_sign_on"
+)
+
+// IsLogin tells whether the event is a user signing in to Okta, successful
+// or not
+func (e *LogEvent) IsLogin() bool {
+	return e.EventType == EventSessionStart
+}
+
+// IsSSO tells whether the event is a user signing in to an app
+func (e *LogEvent) IsSSO() bool {
+	return e.EventType == EventAuthSSO
+}
+
+// Succeeded tells whether the outcome of the event is SUCCESS or ALLOW
+func (e *LogEvent) Succeeded() bool {
+	return e.Outcome.Result == OutcomeSuccess || e.Outcome.Result == OutcomeAllow
+}
+
+// Failed tells whether the outcome of the event is FAILURE or DENY
+func (e *LogEvent) Failed() bool {
+	return e.Outcome.Result == OutcomeFailure || e.Outcome.Result == OutcomeDeny
+}
+
+// TargetsOfType returns the targets of the event of a type such as User or
+// AppInstance, compared case-insensitively
+func (e *LogEvent) TargetsOfType(targetType string) []LogTarget {
+	var targets []LogTarget
+	for _, target := range e.Target {
+		if strings.EqualFold(target.Type, targetType) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// DebugValue returns the debug data of the event with the given key as a
+// string, or "" if there is none
+func (e *LogEvent) DebugValue(key string) string {
+	switch value := e.DebugContext.DebugData[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Logs returns System Log events published at or after since, oldest first.
+// filter is an optional System Log filter expression such as
+// eventType eq "user.session.start"
+func (c *Client) Logs(since time.Time, filter string) (*[]LogEvent, error) {
+	return c.ListLogs(&ListLogsOptions{Since: since, Filter: filter})
+}
+
+// ListLogs returns the System Log events matching opts. Unlike the other
+// list calls it returns a single page, the System Log keeps a next link
+// open for polling.
+func (c *Client) ListLogs(opts *ListLogsOptions) (*[]LogEvent, error) {
+	return c.ListLogsContext(context.Background(), opts)
+}
+
+// ListLogsContext is ListLogs honouring the deadline and call options of
+// ctx
+func (c *Client) ListLogsContext(ctx context.Context, opts *ListLogsOptions) (*[]LogEvent, error) {
+	var response = &[]LogEvent{}
+	err, _ := c.listContext(ctx, withQuery("logs", opts.values()), response)
+	return response, err
+}
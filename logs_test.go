@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testLoginEvent = `{
+	"uuid": "dc9fd3c0-598c-11ef-8478-2b7584bf8d5a",
+	"published": "2024-08-13T15:58:20.353Z",
+	"eventType": "user.session.start",
+	"version": "0",
+	"severity": "INFO",
+	"displayMessage": "User login to Okta",
+	"actor": {"id": "00u1", "type": "User", "alternateId": "jane@example.com", "displayName": "Jane Doe"},
+	"client": {
+		"ipAddress": "198.51.100.7",
+		"zone": "null",
+		"device": "Computer",
+		"userAgent": {"rawUserAgent": "Mozilla/5.0", "os": "Mac OS X", "browser": "CHROME"},
+		"geographicalContext": {"city": "Atlanta", "state": "Georgia", "country": "United States", "postalCode": "30301", "geolocation": {"lat": 33.749, "lon": -84.388}}
+	},
+	"outcome": {"result": "FAILURE", "reason": "INVALID_CREDENTIALS"},
+	"target": [{"id": "00u1", "type": "User", "alternateId": "jane@example.com"}, {"id": "0oa1", "type": "AppInstance"}],
+	"transaction": {"type": "WEB", "id": "ZrkDDJvO2n1MTVmXsGGrsAAAA8g", "detail": {}},
+	"debugContext": {"debugData": {"requestUri": "/api/v1/authn", "threatSuspected": "false", "risk": 3}},
+	"authenticationContext": {"authenticationStep": 0, "externalSessionId": "unknown", "credentialType": "PASSWORD"},
+	"securityContext": {"asNumber": 64496, "asOrg": "example", "isp": "example", "domain": "example.net", "isProxy": true},
+	"request": {"ipChain": [{"ip": "198.51.100.7", "version": "V4", "geographicalContext": {"city": "Atlanta"}}]}
+}`
+
+func TestLogEvent(t *testing.T) {
+	var event LogEvent
+	if err := json.Unmarshal([]byte(testLoginEvent), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	if !event.IsLogin() || event.IsSSO() || event.Succeeded() || !event.Failed() {
+		t.Error("Expected a failed login, got ", event.EventType, event.Outcome)
+	}
+	if geo := event.Client.GeographicalContext; geo == nil || geo.City != "Atlanta" || geo.Geolocation.Lon != -84.388 {
+		t.Errorf("Expected the geographical context, got %+v", geo)
+	}
+	if targets := event.TargetsOfType("appinstance"); len(targets) != 1 || targets[0].ID != "0oa1" {
+		t.Error("Expected the app target, got ", targets)
+	}
+	if uri, risk := event.DebugValue("requestUri"), event.DebugValue("risk"); uri != "/api/v1/authn" || risk != "3" {
+		t.Error("Expected the debug data, got ", uri, risk)
+	}
+	if event.DebugValue("missing") != "" {
+		t.Error("Expected no value for missing debug data")
+	}
+	if !event.SecurityContext.IsProxy || len(event.Request.IPChain) != 1 || event.AuthenticationContext.CredentialType != "PASSWORD" {
+		t.Errorf("Expected the security and request context, got %+v", event)
+	}
+}
@@ -0,0 +1,228 @@
+package okta
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogStreamKey      = "okta.logs.cursor"
+	defaultLogMaxBackoff     = 5 * time.Minute
+	logStreamBackoff         = time.Second
+	logStreamPageSize        = 1000
+	maxLogStreamBackoffs     = 16
+	defaultLogStreamInterval = 10 * time.Second
+)
+
+// LogStreamer delivers System Log events on a channel with at-least-once
+// semantics. The position after every page of events whose events were all
+// acknowledged is checkpointed in Store, a restarted streamer resumes
+// there and delivers the events that weren't acknowledged again. Failed
+// polls are retried with exponential backoff.
+type LogStreamer struct {
+	Client *Client
+	// Filter is a System Log filter expression, it is part of the
+	// checkpoint and only applies when there is none yet
+	Filter string
+	// Store keeps the checkpoint, nil starts from Since on every run
+	Store Store
+	// Key is the key of the checkpoint in Store, okta.logs.cursor when
+	// empty
+	Key string
+	// Since is where to start without a checkpoint, now when zero
+	Since time.Time
+	// Interval is the wait between polls once there are no new events, 10
+	// seconds when zero
+	Interval time.Duration
+	// MaxBackoff bounds the wait after failed polls, 5 minutes when zero
+	MaxBackoff time.Duration
+	// OnError is called with every error that is retried
+	OnError func(error)
+}
+
+// NewLogStreamer returns a streamer of the events of c checkpointing in
+// store
+func NewLogStreamer(c *Client, store Store) *LogStreamer {
+	return &LogStreamer{Client: c, Store: store}
+}
+
+// LogStream delivers events on C until its context is cancelled or polling
+// failed permanently, Err returns the reason once C is closed
+type LogStream struct {
+	C   <-chan LogEvent
+	err error
+
+	store Store
+	key   string
+	mu    sync.Mutex
+	saved string
+	// pages with events not acknowledged yet, oldest first
+	pages []*logPage
+}
+
+type logPage struct {
+	next    string
+	pending map[string]bool
+}
+
+// Err returns why C was closed, it must only be called after C is drained
+func (s *LogStream) Err() error {
+	return s.err
+}
+
+// Ack confirms that an event was processed. Once every event of a page is
+// acknowledged the position after the page is saved, the error of saving
+// it is returned.
+func (s *LogStream) Ack(event LogEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, page := range s.pages {
+		delete(page.pending, event.UUID)
+	}
+	return s.checkpoint()
+}
+
+// add registers the events of a page before they are sent
+func (s *LogStream) add(events []LogEvent, next string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	page := &logPage{next: next, pending: map[string]bool{}}
+	for _, event := range events {
+		page.pending[event.UUID] = true
+	}
+	s.pages = append(s.pages, page)
+	return s.checkpoint()
+}
+
+// checkpoint saves the position after the newest page all of whose events,
+// and those of the pages before it, were acknowledged
+func (s *LogStream) checkpoint() error {
+	cursor := ""
+	for len(s.pages) > 0 && len(s.pages[0].pending) == 0 {
+		cursor = s.pages[0].next
+		s.pages = s.pages[1:]
+	}
+	if cursor == "" || cursor == s.saved || s.store == nil {
+		return nil
+	}
+	if err := s.store.Set(s.key, []byte(cursor)); err != nil {
+		return err
+	}
+	s.saved = cursor
+	return nil
+}
+
+// Stream starts polling the System Log, the events are delivered on C of
+// the returned stream
+func (s *LogStreamer) Stream(ctx context.Context) *LogStream {
+	events := make(chan LogEvent)
+	key := s.Key
+	if key == "" {
+		key = defaultLogStreamKey
+	}
+	stream := &LogStream{C: events, store: s.Store, key: key}
+
+	go func() {
+		stream.err = s.run(ctx, stream, events)
+		close(events)
+	}()
+	return stream
+}
+
+func (s *LogStreamer) run(ctx context.Context, stream *LogStream, events chan<- LogEvent) error {
+	endpoint, err := s.cursor(stream.key)
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for {
+		var page []LogEvent
+		err, next := s.Client.listContext(ctx, endpoint, &page)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if kind := ClassOf(err).Kind; kind == ErrorInvalidRequest || kind == ErrorForbidden {
+				return err
+			}
+			if s.OnError != nil {
+				s.OnError(err)
+			}
+			if failures < maxLogStreamBackoffs {
+				failures++
+			}
+			if err := (RetryEvent{Sleep: s.backoff(failures)}).wait(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		failures = 0
+
+		if next == "" {
+			// the System Log only ends a poll when it is bounded by until
+			next = endpoint
+		}
+		if err := stream.add(page, next); err != nil && s.OnError != nil {
+			s.OnError(err)
+		}
+		for _, event := range page {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		// keep reading while there is a backlog
+		if len(page) < logStreamPageSize {
+			if err := (RetryEvent{Sleep: s.interval()}).wait(ctx); err != nil {
+				return err
+			}
+		}
+		endpoint = next
+	}
+}
+
+// cursor returns the checkpointed endpoint or the one of the first poll
+func (s *LogStreamer) cursor(key string) (string, error) {
+	if s.Store != nil {
+		cursor, err := s.Store.Get(key)
+		if err == nil {
+			return string(cursor), nil
+		}
+		if err != ErrNotStored {
+			return "", err
+		}
+	}
+
+	since := s.Since
+	if since.IsZero() {
+		since = time.Now()
+	}
+	opts := &ListLogsOptions{Since: since, Filter: s.Filter}
+	opts.Limit = logStreamPageSize
+	return withQuery("logs", opts.values()), nil
+}
+
+func (s *LogStreamer) interval() time.Duration {
+	if s.Interval == 0 {
+		return defaultLogStreamInterval
+	}
+	return s.Interval
+}
+
+func (s *LogStreamer) backoff(failures int) time.Duration {
+	max := s.MaxBackoff
+	if max == 0 {
+		max = defaultLogMaxBackoff
+	}
+	backoff := (&RetryPolicy{Backoff: logStreamBackoff}).backoff(failures)
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
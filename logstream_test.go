@@ -0,0 +1,100 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogStreamer(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	failed := false
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.URL.RawQuery)
+
+		next := func(after string) {
+			w.Header().Set("Link", `<https://`+r.Host+`/api/v1/logs?after=`+after+`>; rel="next"`)
+		}
+		switch r.URL.Query().Get("after") {
+		case "":
+			next("2")
+			w.Write([]byte(`[{"uuid":"e1","eventType":"user.session.start"},{"uuid":"e2","eventType":"user.session.end"}]`))
+		case "2":
+			if !failed {
+				failed = true
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"errorCode":"E0000009"}`))
+				return
+			}
+			next("3")
+			w.Write([]byte(`[{"uuid":"e3","eventType":"user.session.start"}]`))
+		default:
+			next("3")
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer done()
+
+	store := NewMemoryStore()
+	var retried []error
+	streamer := NewLogStreamer(client, store)
+	streamer.Filter = `eventType sw "user.session"`
+	streamer.Interval = time.Millisecond
+	streamer.MaxBackoff = time.Millisecond
+	streamer.OnError = func(err error) { retried = append(retried, err) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := streamer.Stream(ctx)
+	cursor := func() string {
+		value, _ := store.Get(defaultLogStreamKey)
+		return string(value)
+	}
+
+	e1, e2 := <-stream.C, <-stream.C
+	if err := stream.Ack(e1); err != nil || cursor() != "" {
+		t.Fatal("Expected no checkpoint before the page is acknowledged, got ", cursor(), err)
+	}
+	if err := stream.Ack(e2); err != nil || cursor() != "logs?after=2" {
+		t.Fatal("Expected the checkpoint after the first page, got ", cursor(), err)
+	}
+	e3 := <-stream.C
+	if e3.UUID != "e3" || len(retried) != 1 {
+		t.Fatal("Expected the third event after a retry, got ", e3.UUID, retried)
+	}
+	stream.Ack(e3)
+	if cursor() != "logs?after=3" {
+		t.Error("Expected the checkpoint after the second page, got ", cursor())
+	}
+	cancel()
+	for range stream.C {
+	}
+	if stream.Err() != context.Canceled {
+		t.Error("Expected the stream to stop with the context, got ", stream.Err())
+	}
+
+	mu.Lock()
+	first := requests[0]
+	requests = nil
+	mu.Unlock()
+	if expected := "filter=eventType+sw+%22user.session%22&limit=1000"; len(first) < len(expected) || first[:len(expected)] != expected {
+		t.Error("Expected the first poll to filter, got ", first)
+	}
+
+	// a new streamer resumes at the checkpoint
+	ctx, cancel = context.WithCancel(context.Background())
+	stream = NewLogStreamer(client, store).Stream(ctx)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	for range stream.C {
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) == 0 || requests[0] != "after=3" {
+		t.Error("Expected to resume after the checkpoint, got ", requests)
+	}
+}
@@ -0,0 +1,139 @@
+package okta
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// MigrateOptions controls how MigrateUser copies a user between orgs
+type MigrateOptions struct {
+	// GroupMapping maps source group names to destination group names,
+	// groups missing from it are looked up by their source name
+	GroupMapping map[string]string
+	// Activate creates the user ACTIVE instead of STAGED
+	Activate bool
+	// ResetPassword sends the user a password reset email from the
+	// destination org, passwords can't be copied. It requires Activate, the
+	// password of a STAGED user can't be reset.
+	ResetPassword bool
+}
+
+// MigrationReport describes what MigrateUser copied and what it skipped
+type MigrationReport struct {
+	SourceUserID      string
+	DestinationUserID string
+	// Groups are the destination groups the user was added to
+	Groups []string
+	// SkippedGroups are source groups without a destination group
+	SkippedGroups []string
+	// SkippedAttributes are custom profile attributes the destination
+	// schema doesn't define
+	SkippedAttributes []string
+	PasswordReset     bool
+}
+
+// MigrateUser copies a user from the org of src to the org of dst: the
+// profile, limited to attributes the destination schema knows about, and
+// group memberships of groups found by name in the destination org. The
+// report is returned even when a later step fails.
+func MigrateUser(src, dst *Client, userID string, opts *MigrateOptions) (*MigrationReport, error) {
+	if opts == nil {
+		opts = &MigrateOptions{}
+	}
+	report := &MigrationReport{SourceUserID: userID}
+	if opts.ResetPassword && !opts.Activate {
+		return report, errors.New("okta: ResetPassword requires Activate, the password of a STAGED user can't be reset")
+	}
+
+	user, err := src.User(userID)
+	if err != nil {
+		return report, err
+	}
+	groups, err := src.Groups(userID)
+	if err != nil {
+		return report, err
+	}
+	schema, err := dst.UserSchema()
+	if err != nil {
+		return report, err
+	}
+
+	profile, skipped, err := migratableProfile(user.Profile, schema)
+	if err != nil {
+		return report, err
+	}
+	report.SkippedAttributes = skipped
+
+	created, err := dst.CreateUser(&CreateUserRequest{Profile: profile}, opts.Activate)
+	if err != nil {
+		return report, err
+	}
+	report.DestinationUserID = created.ID
+
+	for _, group := range *groups {
+		// Everyone is assigned automatically and can't be managed
+		if group.Type == "BUILT_IN" {
+			continue
+		}
+
+		name := group.Profile.Name
+		if mapped, ok := opts.GroupMapping[name]; ok {
+			name = mapped
+		}
+
+		target, err := dst.GroupByName(name)
+		if err != nil {
+			return report, err
+		}
+		if target == nil {
+			report.SkippedGroups = append(report.SkippedGroups, group.Profile.Name)
+			continue
+		}
+
+		if err := dst.AddUserToGroup(target.ID, created.ID); err != nil {
+			return report, err
+		}
+		report.Groups = append(report.Groups, target.Profile.Name)
+	}
+
+	if opts.ResetPassword {
+		if err := dst.ResetPassword(created.ID, true); err != nil {
+			return report, err
+		}
+		report.PasswordReset = true
+	}
+
+	return report, nil
+}
+
+// migratableProfile returns the non-empty attributes of profile without
+// the custom ones missing from schema, and the names of those dropped
+func migratableProfile(profile UserProfile, schema *UserSchema) (map[string]interface{}, []string, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, nil, err
+	}
+
+	var skipped []string
+	for name, value := range attributes {
+		if value == nil || value == "" {
+			delete(attributes, name)
+			continue
+		}
+		if _, custom := profile.Custom[name]; !custom {
+			continue
+		}
+		if schema.Definitions.Custom == nil || schema.Definitions.Custom.Properties[name] == nil {
+			delete(attributes, name)
+			skipped = append(skipped, name)
+		}
+	}
+	sort.Strings(skipped)
+
+	return attributes, skipped, nil
+}
@@ -0,0 +1,81 @@
+package okta
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestMigrateUser(t *testing.T) {
+	src, srcDone := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users/00u1":
+			w.Write([]byte(`{"id":"00u1","profile":{"login":"jane@example.com","email":"jane@example.com","badge":"7","legacyId":"x"}}`))
+		case "/api/v1/users/00u1/groups":
+			w.Write([]byte(`[{"id":"00g0","type":"BUILT_IN","profile":{"name":"Everyone"}},
+				{"id":"00g1","type":"OKTA_GROUP","profile":{"name":"Engineering"}},
+				{"id":"00g2","type":"OKTA_GROUP","profile":{"name":"Contractors"}}]`))
+		default:
+			t.Error("Unexpected source request ", r.URL.Path)
+		}
+	}))
+	defer srcDone()
+
+	var created map[string]interface{}
+	var added []string
+	dst, dstDone := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/meta/schemas/user/default":
+			w.Write([]byte(`{"definitions":{"custom":{"id":"#custom","properties":{"badge":{"title":"Badge","type":"string"}}}}}`))
+		case r.URL.Path == "/api/v1/users" && r.Method == "POST":
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &created)
+			w.Write([]byte(`{"id":"00uA"}`))
+		case r.URL.Path == "/api/v1/groups":
+			if r.URL.Query().Get("q") == "Eng" {
+				w.Write([]byte(`[{"id":"00gE","profile":{"name":"Eng"}}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case r.Method == "PUT":
+			added = append(added, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Error("Unexpected destination request ", r.Method, r.URL.Path)
+		}
+	}))
+	defer dstDone()
+
+	report, err := MigrateUser(src, dst, "00u1", &MigrateOptions{
+		GroupMapping: map[string]string{"Engineering": "Eng"},
+	})
+	if err != nil {
+		t.Fatal("Expected nil, got ", err.Error())
+	}
+
+	profile := created["profile"].(map[string]interface{})
+	if profile["badge"] != "7" || profile["legacyId"] != nil || profile["nickName"] != nil {
+		t.Errorf("Expected only known, non-empty attributes, got %v", profile)
+	}
+	if len(added) != 1 || added[0] != "/api/v1/groups/00gE/users/00uA" {
+		t.Errorf("Expected membership in mapped group, got %v", added)
+	}
+	if report.DestinationUserID != "00uA" ||
+		len(report.SkippedGroups) != 1 || report.SkippedGroups[0] != "Contractors" ||
+		len(report.SkippedAttributes) != 1 || report.SkippedAttributes[0] != "legacyId" {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestMigrateUserResetStaged(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request, got ", r.Method, r.URL.Path)
+	}))
+	defer done()
+
+	report, err := MigrateUser(client, client, "00u1", &MigrateOptions{ResetPassword: true})
+	if err == nil || report.DestinationUserID != "" {
+		t.Error("Expected ResetPassword without Activate to fail before creating the user, got ", err)
+	}
+}
@@ -0,0 +1,80 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// myAccountHeader selects the version of the MyAccount API
+var myAccountHeader = http.Header{"Accept": {"application/json; okta-version=1.0.0"}}
+
+// MyAccountProfile is the profile of the signed in user
+type MyAccountProfile struct {
+	CreatedAt  *time.Time             `json:"createdAt,omitempty"`
+	ModifiedAt *time.Time             `json:"modifiedAt,omitempty"`
+	Profile    map[string]interface{} `json:"profile"`
+}
+
+// MyAccountEmail is an email address of the signed in user
+type MyAccountEmail struct {
+	ID      string   `json:"id"`
+	Status  string   `json:"status"`
+	Roles   []string `json:"roles"`
+	Profile struct {
+		Email string `json:"email"`
+	} `json:"profile"`
+}
+
+// MyAccountPhone is a phone number of the signed in user
+type MyAccountPhone struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// Me returns the user of the session or access token the client uses, no
+// admin API token is needed
+func (c *Client) Me() (*User, error) {
+	return c.User("me")
+}
+
+// UpdateMe merges profile into the profile of the signed in user, the
+// attributes must be writable by the user in the profile schema
+func (c *Client) UpdateMe(profile interface{}) (*User, error) {
+	return c.UpdateUserProfilePartial("me", profile)
+}
+
+// MyAccountProfile returns the profile of the signed in user through the
+// MyAccount API, the access token needs the okta.myAccount.profile.read scope
+func (c *Client) MyAccountProfile() (*MyAccountProfile, error) {
+	var response = &MyAccountProfile{}
+	err, _, _ := c.callHeader(context.Background(), "/idp/myaccount/profile", "GET", myAccountHeader, nil, response)
+	return response, err
+}
+
+// UpdateMyAccountProfile replaces the profile of the signed in user, the
+// access token needs the okta.myAccount.profile.manage scope
+func (c *Client) UpdateMyAccountProfile(profile map[string]interface{}) (*MyAccountProfile, error) {
+	var request = map[string]interface{}{
+		"profile": profile,
+	}
+
+	var response = &MyAccountProfile{}
+	err, _, _ := c.callHeader(context.Background(), "/idp/myaccount/profile", "PUT", myAccountHeader, request, response)
+	return response, err
+}
+
+// MyAccountEmails returns the email addresses of the signed in user
+func (c *Client) MyAccountEmails() (*[]MyAccountEmail, error) {
+	var response = &[]MyAccountEmail{}
+	err, _, _ := c.callHeader(context.Background(), "/idp/myaccount/emails", "GET", myAccountHeader, nil, response)
+	return response, err
+}
+
+// MyAccountPhones returns the phone numbers of the signed in user
+func (c *Client) MyAccountPhones() (*[]MyAccountPhone, error) {
+	var response = &[]MyAccountPhone{}
+	err, _, _ := c.callHeader(context.Background(), "/idp/myaccount/phones", "GET", myAccountHeader, nil, response)
+	return response, err
+}
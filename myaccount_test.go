@@ -0,0 +1,28 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMyAccountProfile(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/idp/myaccount/profile" ||
+			r.Header.Get("Authorization") != "Bearer access" ||
+			r.Header.Get("Accept") != "application/json; okta-version=1.0.0" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"profile":{"firstName":"John"}}`))
+	}))
+	defer done()
+	client.AccessToken = "access"
+
+	profile, err := client.MyAccountProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Profile["firstName"] != "John" {
+		t.Error("Unexpected profile ", profile.Profile)
+	}
+}
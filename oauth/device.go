@@ -0,0 +1,76 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const deviceCodeGrant = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthorization is a pending device sign in, the user visits
+// VerificationURI and enters UserCode, or opens VerificationURIComplete
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	// Interval is the minimum number of seconds between polls
+	Interval int `json:"interval"`
+}
+
+// AuthorizeDevice starts the device authorization grant, the app must have
+// the device authorization grant type enabled
+// https://developer.okta.com/docs/guides/device-authorization-grant/main/
+func (c *Config) AuthorizeDevice(ctx context.Context) (*DeviceAuthorization, error) {
+	v := url.Values{}
+	v.Set("scope", strings.Join(c.scopes(), " "))
+
+	var response = &DeviceAuthorization{}
+	if err := c.post(ctx, "device/authorize", v, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeviceToken polls the token endpoint until the user approved or denied
+// the device, the authorization expired or ctx is done
+func (c *Config) DeviceToken(ctx context.Context, authorization *DeviceAuthorization) (*Token, error) {
+	interval := time.Duration(authorization.Interval) * time.Second
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+	if authorization.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(authorization.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		v := url.Values{}
+		v.Set("grant_type", deviceCodeGrant)
+		v.Set("device_code", authorization.DeviceCode)
+		token, err := c.token(ctx, v)
+		if e, ok := err.(*Error); ok {
+			switch e.Code {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				// https://tools.ietf.org/html/rfc8628#section-3.5
+				interval += 5 * time.Second
+				continue
+			}
+		}
+		return token, err
+	}
+}
@@ -0,0 +1,158 @@
+package oauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// useDPoPNonce is the error of a request whose proof lacks the nonce the
+// server requires, the nonce is in the DPoP-Nonce header of the response
+const useDPoPNonce = "use_dpop_nonce"
+
+// DPoP proves possession of a key with every request (RFC 9449), the
+// access tokens issued to a Config with DPoP are bound to the key and only
+// accepted with a proof of it. Its Proof and SetNonce methods make it the
+// DPoP of an okta.Client. It is safe for concurrent use.
+type DPoP struct {
+	key *ecdsa.PrivateKey
+	jwk map[string]string
+
+	mu     sync.Mutex
+	nonces map[string]string
+}
+
+// NewDPoP returns a DPoP with a new P-256 key, tokens bound to it are
+// useless once the process exits
+func NewDPoP() (*DPoP, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewDPoPFromKey(key), nil
+}
+
+// NewDPoPFromKey returns a DPoP proving possession of a P-256 key, e.g.
+// one kept with the refresh tokens bound to it
+func NewDPoPFromKey(key *ecdsa.PrivateKey) *DPoP {
+	return &DPoP{
+		key: key,
+		jwk: map[string]string{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(padded(key.X.Bytes(), 32)),
+			"y":   base64.RawURLEncoding.EncodeToString(padded(key.Y.Bytes(), 32)),
+		},
+		nonces: map[string]string{},
+	}
+}
+
+// Thumbprint is the JWK thumbprint of the public key (RFC 7638), the jkt
+// the cnf claim of bound access tokens holds
+func (d *DPoP) Thumbprint() string {
+	// members in lexicographic order, as the thumbprint requires
+	canonical := `{"crv":"P-256","kty":"EC","x":"` + d.jwk["x"] + `","y":"` + d.jwk["y"] + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Proof returns the DPoP header of a request, accessToken is the token the
+// request is authorized with, empty for requests of the token endpoint
+func (d *DPoP) Proof(method, uri, accessToken string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+		"htm": method,
+		"htu": htu(uri),
+		"iat": time.Now().Unix(),
+	}
+	if nonce := d.nonce(uri); nonce != "" {
+		claims["nonce"] = nonce
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	header, err := json.Marshal(map[string]interface{}{"typ": "dpop+jwt", "alg": "ES256", "jwk": d.jwk})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, d.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	signature := append(padded(r.Bytes(), 32), padded(s.Bytes(), 32)...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// SetNonce records the DPoP-Nonce a server returned, it is sent with the
+// following proofs for the same origin
+func (d *DPoP) SetNonce(uri, nonce string) {
+	if nonce == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nonces[origin(uri)] = nonce
+}
+
+func (d *DPoP) nonce(uri string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.nonces[origin(uri)]
+}
+
+// observe records the nonce of a response and tells whether the request
+// has to be sent again with it
+func (d *DPoP) observe(uri string, resp *http.Response, e *Error) bool {
+	nonce := resp.Header.Get("DPoP-Nonce")
+	d.SetNonce(uri, nonce)
+	return nonce != "" && e != nil && e.Code == useDPoPNonce
+}
+
+// htu is the URI of a request without query and fragment
+func htu(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+func origin(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// padded left pads a big-endian integer to size bytes
+func padded(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
+}
@@ -0,0 +1,105 @@
+package oauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// verifyProof checks the signature of a proof with the key in its header
+// and returns its claims
+func verifyProof(t *testing.T, proof string) map[string]interface{} {
+	parts := strings.Split(proof, ".")
+	if len(parts) != 3 {
+		t.Fatal("Expected a JWT, got ", proof)
+	}
+	var header struct {
+		Typ string            `json:"typ"`
+		Alg string            `json:"alg"`
+		JWK map[string]string `json:"jwk"`
+	}
+	var claims map[string]interface{}
+	if decodeSegment(parts[0], &header) != nil || decodeSegment(parts[1], &claims) != nil {
+		t.Fatal("Expected JSON segments, got ", proof)
+	}
+	if header.Typ != "dpop+jwt" || header.Alg != "ES256" {
+		t.Error("Unexpected header ", header)
+	}
+
+	x, _ := base64.RawURLEncoding.DecodeString(header.JWK["x"])
+	y, _ := base64.RawURLEncoding.DecodeString(header.JWK["y"])
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if len(signature) != 64 || !ecdsa.Verify(key, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		t.Error("Expected the proof to be signed with the key of its header")
+	}
+	return claims
+}
+
+func TestDPoPProof(t *testing.T) {
+	dpop, err := NewDPoP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dpop.SetNonce("https://example.okta.com/oauth2/v1/token", "nonce")
+
+	proof, err := dpop.Proof("GET", "https://example.okta.com/api/v1/users?limit=200#top", "access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := verifyProof(t, proof)
+	sum := sha256.Sum256([]byte("access"))
+	if claims["htm"] != "GET" || claims["htu"] != "https://example.okta.com/api/v1/users" || claims["ath"] != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Error("Unexpected claims ", claims)
+	}
+	if claims["nonce"] != "nonce" || claims["jti"] == "" {
+		t.Error("Expected the nonce of the origin and a jti, got ", claims)
+	}
+
+	other, _ := dpop.Proof("POST", "https://other.okta.com/oauth2/v1/token", "")
+	if claims := verifyProof(t, other); claims["nonce"] != nil || claims["ath"] != nil || claims["jti"] == verifyProof(t, proof)["jti"] {
+		t.Error("Expected a proof without nonce and ath, got ", claims)
+	}
+
+	if len(dpop.Thumbprint()) != 43 {
+		t.Error("Expected a base64url SHA-256 thumbprint, got ", dpop.Thumbprint())
+	}
+}
+
+func TestDPoPTokenNonce(t *testing.T) {
+	dpop, _ := NewDPoP()
+	var proofs []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := verifyProof(t, r.Header.Get("DPoP"))
+		proofs = append(proofs, claims)
+		if claims["nonce"] != "server-nonce" {
+			w.Header().Set("DPoP-Nonce", "server-nonce")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"use_dpop_nonce","error_description":"Authorization server requires nonce in DPoP proof."}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "token_type": "DPoP", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	config := &Config{Issuer: server.URL, ClientID: "client", DPoP: dpop}
+	token, err := config.PasswordToken(context.Background(), "svc", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.TokenType != "DPoP" || len(proofs) != 2 {
+		t.Error("Expected the request to be sent again with the nonce, got ", token.TokenType, len(proofs))
+	}
+	if proofs[0]["htm"] != "POST" || proofs[0]["htu"] != server.URL+"/oauth2/v1/token" {
+		t.Error("Unexpected proof ", proofs[0])
+	}
+}
@@ -0,0 +1,59 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+)
+
+// Token type hints for Introspect and Revoke
+const (
+	AccessToken  = "access_token"
+	RefreshToken = "refresh_token"
+	IDToken      = "id_token"
+)
+
+// Introspection is the state of a token as known to the authorization
+// server, all fields but Active are empty for inactive tokens
+type Introspection struct {
+	Active    bool        `json:"active"`
+	Scope     string      `json:"scope"`
+	ClientID  string      `json:"client_id"`
+	Username  string      `json:"username"`
+	TokenType string      `json:"token_type"`
+	ExpiresAt int64       `json:"exp"`
+	IssuedAt  int64       `json:"iat"`
+	NotBefore int64       `json:"nbf"`
+	Subject   string      `json:"sub"`
+	Audience  interface{} `json:"aud"`
+	Issuer    string      `json:"iss"`
+	JTI       string      `json:"jti"`
+	UserID    string      `json:"uid"`
+	DeviceID  string      `json:"device_id"`
+}
+
+// Introspect asks the authorization server whether token is still active,
+// unlike TokenVerifier this notices revoked tokens. hint is one of
+// AccessToken, RefreshToken or IDToken and may be empty.
+func (c *Config) Introspect(ctx context.Context, token, hint string) (*Introspection, error) {
+	v := url.Values{}
+	v.Set("token", token)
+	if hint != "" {
+		v.Set("token_type_hint", hint)
+	}
+
+	var response = &Introspection{}
+	err := c.post(ctx, "introspect", v, response)
+	return response, err
+}
+
+// Revoke revokes an access or refresh token, revoking a refresh token
+// revokes the access tokens issued with it too. Unknown and already
+// revoked tokens are not an error.
+func (c *Config) Revoke(ctx context.Context, token, hint string) error {
+	v := url.Values{}
+	v.Set("token", token)
+	if hint != "" {
+		v.Set("token_type_hint", hint)
+	}
+	return c.post(ctx, "revoke", v, nil)
+}
@@ -0,0 +1,217 @@
+// Package oauth implements the authorization code flow with PKCE against
+// the org authorization server or a custom authorization server of Okta.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config describes an OpenID Connect app of an Okta org
+type Config struct {
+	// Issuer is https://{org}.okta.com for the org authorization server or
+	// https://{org}.okta.com/oauth2/{authorizationServerId} for a custom one
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// HTTPClient sends the token requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	// DPoP binds the tokens issued to its key when set, they must then be
+	// sent with a proof, see NewDPoP
+	DPoP *DPoP
+}
+
+// Token is the response of the token endpoint
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+
+	// Expiry is when the access token expires, computed from ExpiresIn
+	Expiry time.Time `json:"-"`
+}
+
+// Error is an OAuth 2.0 error response
+type Error struct {
+	HTTPCode    int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("oauth: %s", e.Code)
+	}
+	return fmt.Sprintf("oauth: %s: %s", e.Code, e.Description)
+}
+
+// Endpoint returns the URL of an endpoint of the authorization server such
+// as "authorize", "token" or "keys"
+func (c *Config) Endpoint(name string) string {
+	issuer := strings.TrimSuffix(c.Issuer, "/")
+	if strings.Contains(issuer, "/oauth2/") {
+		return issuer + "/v1/" + name
+	}
+	return issuer + "/oauth2/v1/" + name
+}
+
+// NewVerifier returns a random PKCE code verifier, it must be kept until
+// the code is exchanged
+func NewVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 PKCE code challenge for verifier
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL to send the user to for signing in, state is
+// returned to the redirect URL and nonce ends up in the ID token
+func (c *Config) AuthCodeURL(state, nonce, verifier string) string {
+	v := &url.Values{}
+	v.Add("client_id", c.ClientID)
+	v.Add("response_type", "code")
+	v.Add("redirect_uri", c.RedirectURL)
+	v.Add("scope", strings.Join(c.scopes(), " "))
+	v.Add("state", state)
+	if nonce != "" {
+		v.Add("nonce", nonce)
+	}
+	v.Add("code_challenge", Challenge(verifier))
+	v.Add("code_challenge_method", "S256")
+	return c.Endpoint("authorize") + "?" + v.Encode()
+}
+
+// Exchange trades the code the redirect URL received for tokens
+func (c *Config) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", c.RedirectURL)
+	v.Set("code_verifier", verifier)
+	return c.token(ctx, v)
+}
+
+// Refresh trades a refresh token for new tokens, the offline_access scope
+// must have been requested to get one
+func (c *Config) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	v := url.Values{}
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", refreshToken)
+	v.Set("scope", strings.Join(c.scopes(), " "))
+	return c.token(ctx, v)
+}
+
+func (c *Config) scopes() []string {
+	if len(c.Scopes) == 0 {
+		return []string{"openid"}
+	}
+	return c.Scopes
+}
+
+func (c *Config) token(ctx context.Context, v url.Values) (*Token, error) {
+	var token = &Token{}
+	if err := c.post(ctx, "token", v, token); err != nil {
+		return nil, err
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// post sends a form to an endpoint authenticating as the client, public
+// clients without a secret send their client_id instead. Token requests
+// carry a DPoP proof with DPoP and are sent again once when the server
+// asks for a nonce.
+func (c *Config) post(ctx context.Context, endpoint string, v url.Values, response interface{}) error {
+	if c.ClientSecret == "" {
+		v.Set("client_id", c.ClientID)
+	}
+
+	uri := c.Endpoint(endpoint)
+	dpop := c.DPoP
+	if endpoint != "token" {
+		dpop = nil
+	}
+	for attempt := 1; ; attempt++ {
+		resp, body, err := c.send(ctx, uri, v, dpop)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			e := &Error{HTTPCode: resp.StatusCode}
+			_ = json.Unmarshal(body, e)
+			if e.Code == "" {
+				e.Code = http.StatusText(resp.StatusCode)
+			}
+			if dpop != nil && dpop.observe(uri, resp, e) && attempt == 1 {
+				continue
+			}
+			return e
+		}
+		if dpop != nil {
+			dpop.observe(uri, resp, nil)
+		}
+
+		if len(body) == 0 || response == nil {
+			return nil
+		}
+		return json.Unmarshal(body, response)
+	}
+}
+
+func (c *Config) send(ctx context.Context, uri string, v url.Values, dpop *DPoP) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", uri, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	if c.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+	if dpop != nil {
+		proof, err := dpop.Proof("POST", uri, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("DPoP", proof)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return resp, body, err
+}
@@ -0,0 +1,199 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestEndpoint(t *testing.T) {
+	org := &Config{Issuer: "https://example.okta.com"}
+	if endpoint := org.Endpoint("token"); endpoint != "https://example.okta.com/oauth2/v1/token" {
+		t.Error("Unexpected org endpoint ", endpoint)
+	}
+
+	custom := &Config{Issuer: "https://example.okta.com/oauth2/default/"}
+	if endpoint := custom.Endpoint("token"); endpoint != "https://example.okta.com/oauth2/default/v1/token" {
+		t.Error("Unexpected custom authorization server endpoint ", endpoint)
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	// https://tools.ietf.org/html/rfc7636#appendix-B
+	challenge := Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Error("Unexpected challenge ", challenge)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code") != "code" || r.Form.Get("code_verifier") != "verifier" || r.Form.Get("client_id") != "client" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"The authorization code is invalid"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"access","token_type":"Bearer","expires_in":3600,"id_token":"id"}`))
+	}))
+	defer server.Close()
+
+	config := &Config{Issuer: server.URL + "/oauth2/default", ClientID: "client", RedirectURL: "http://localhost/callback"}
+
+	u, err := url.Parse(config.AuthCodeURL("state", "nonce", "verifier"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("code_challenge") != Challenge("verifier") || u.Query().Get("scope") != "openid" {
+		t.Error("Unexpected authorize URL ", u)
+	}
+
+	token, err := config.Exchange(context.Background(), "code", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access" || token.IDToken != "id" || token.Expiry.IsZero() {
+		t.Error("Unexpected token ", token)
+	}
+
+	_, err = config.Exchange(context.Background(), "expired", "verifier")
+	if e, ok := err.(*Error); !ok || e.Code != "invalid_grant" {
+		t.Error("Expected an OAuth error, got ", err)
+	}
+}
+
+func TestIntrospectRevoke(t *testing.T) {
+	revoked := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if user, secret, ok := r.BasicAuth(); !ok || user != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/oauth2/default/v1/revoke":
+			revoked[r.Form.Get("token")] = true
+		case "/oauth2/default/v1/introspect":
+			if revoked[r.Form.Get("token")] {
+				w.Write([]byte(`{"active":false}`))
+				return
+			}
+			w.Write([]byte(`{"active":true,"username":"john.doe@example.com","token_type":"Bearer"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{Issuer: server.URL + "/oauth2/default", ClientID: "client", ClientSecret: "secret"}
+	ctx := context.Background()
+
+	introspection, err := config.Introspect(ctx, "access", AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !introspection.Active || introspection.Username != "john.doe@example.com" {
+		t.Error("Expected an active token, got ", introspection)
+	}
+
+	if err := config.Revoke(ctx, "access", AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	introspection, err = config.Introspect(ctx, "access", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if introspection.Active {
+		t.Error("Expected the token to be revoked")
+	}
+
+	config.ClientSecret = "wrong"
+	if err := config.Revoke(ctx, "access", ""); err == nil {
+		t.Error("Expected invalid client credentials to fail")
+	}
+}
+
+func TestDeviceToken(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/oauth2/default/v1/device/authorize":
+			w.Write([]byte(`{"device_code":"device","user_code":"ABCD-EFGH","verification_uri":"https://example.okta.com/activate","expires_in":600}`))
+		case "/oauth2/default/v1/token":
+			if r.Form.Get("grant_type") != deviceCodeGrant || r.Form.Get("device_code") != "device" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			polls++
+			if polls < 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"access","token_type":"Bearer","expires_in":3600}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{Issuer: server.URL + "/oauth2/default", ClientID: "client"}
+	authorization, err := config.AuthorizeDevice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization.UserCode != "ABCD-EFGH" {
+		t.Error("Unexpected authorization ", authorization)
+	}
+
+	// polls every second, the smallest interval Okta hands out
+	authorization.Interval = 1
+	token, err := config.DeviceToken(context.Background(), authorization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access" || polls != 2 {
+		t.Error("Expected the token on the second poll, got ", token, polls)
+	}
+}
+
+func TestPasswordTokenSource(t *testing.T) {
+	grants := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		grant := r.Form.Get("grant_type")
+		grants = append(grants, grant)
+		switch {
+		case grant == "password" && r.Form.Get("password") == "secret":
+			w.Write([]byte(`{"access_token":"first","expires_in":1,"refresh_token":"refresh"}`))
+		case grant == "refresh_token" && r.Form.Get("refresh_token") == "refresh":
+			w.Write([]byte(`{"access_token":"refreshed","expires_in":3600}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{Issuer: server.URL + "/oauth2/default", ClientID: "client", Scopes: []string{"openid", "offline_access"}}
+	source := config.PasswordTokenSource("service@example.com", "secret")
+	ctx := context.Background()
+
+	token, err := source.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "first" || token.Valid() {
+		t.Error("Expected a token about to expire, got ", token)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err = source.Token(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if token.AccessToken != "refreshed" || token.RefreshToken != "refresh" || len(grants) != 2 || grants[1] != "refresh_token" {
+		t.Error("Expected one refresh keeping the refresh token, got ", token, grants)
+	}
+}
@@ -0,0 +1,81 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryLeeway renews tokens a bit before they expire so that they don't
+// expire in flight
+const expiryLeeway = time.Minute
+
+// Valid reports whether the access token is set and not about to expire
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" &&
+		(t.Expiry.IsZero() || time.Now().Add(expiryLeeway).Before(t.Expiry))
+}
+
+// PasswordToken performs the resource owner password grant, which has to
+// be enabled for the app and is only meant for legacy service accounts
+func (c *Config) PasswordToken(ctx context.Context, username, password string) (*Token, error) {
+	v := url.Values{}
+	v.Set("grant_type", "password")
+	v.Set("username", username)
+	v.Set("password", password)
+	v.Set("scope", strings.Join(c.scopes(), " "))
+	return c.token(ctx, v)
+}
+
+// PasswordTokenSource hands out access tokens of a service account, the
+// token is refreshed when it expires and a new one is requested with the
+// password when there is no refresh token or refreshing fails
+type PasswordTokenSource struct {
+	config   *Config
+	username string
+	password string
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// PasswordTokenSource returns a token source for a service account,
+// request the offline_access scope to have tokens refreshed rather than
+// sending the password again
+func (c *Config) PasswordTokenSource(username, password string) *PasswordTokenSource {
+	return &PasswordTokenSource{config: c, username: username, password: password}
+}
+
+// Token returns a valid token
+func (s *PasswordTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+
+	if s.token != nil && s.token.RefreshToken != "" {
+		token, err := s.config.Refresh(ctx, s.token.RefreshToken)
+		if err == nil {
+			if token.RefreshToken == "" {
+				// refresh tokens aren't always rotated
+				token.RefreshToken = s.token.RefreshToken
+			}
+			s.token = token
+			return token, nil
+		}
+		if e, ok := err.(*Error); !ok || e.Code != "invalid_grant" {
+			return nil, err
+		}
+	}
+
+	token, err := s.config.PasswordToken(ctx, s.username, s.password)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
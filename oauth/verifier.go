@@ -0,0 +1,309 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultKeysTTL   = time.Hour
+	defaultClockSkew = 2 * time.Minute
+	// keysRefetchDelay limits refetching keys for unknown key ids, tokens
+	// with made up ids mustn't turn into requests to Okta
+	keysRefetchDelay = time.Minute
+)
+
+// TokenVerifier validates ID and access tokens issued by an authorization
+// server against its published keys, the keys are fetched on first use and
+// cached
+type TokenVerifier struct {
+	// Issuer is the issuer of the tokens, see Config.Issuer
+	Issuer string
+	// ClientID is the expected audience of ID tokens
+	ClientID string
+	// Audience is the expected audience of access tokens, e.g. api://default
+	Audience string
+	// ClockSkew is the tolerance for exp, iat and nbf, 2 minutes when zero
+	ClockSkew time.Duration
+	// KeysTTL is how long fetched keys are used, 1 hour when zero
+	KeysTTL time.Duration
+	// HTTPClient fetches the keys, http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// NewTokenVerifier returns a verifier for the tokens of the app described
+// by config
+func NewTokenVerifier(config *Config, audience string) *TokenVerifier {
+	return &TokenVerifier{
+		Issuer:     strings.TrimSuffix(config.Issuer, "/"),
+		ClientID:   config.ClientID,
+		Audience:   audience,
+		HTTPClient: config.HTTPClient,
+	}
+}
+
+// Claims are the claims of a validated token
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	Nonce     string
+	// ClientID is the cid claim of access tokens
+	ClientID string
+	// UserID is the uid claim of access tokens
+	UserID string
+	// Scopes is the scp claim of access tokens
+	Scopes []string
+	// Raw holds every claim including those above
+	Raw map[string]interface{}
+}
+
+// ValidationError is returned for tokens that aren't valid
+type ValidationError struct {
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return "oauth: invalid token: " + e.Reason
+}
+
+// VerifyIDToken validates an ID token, nonce must be the one passed to
+// AuthCodeURL and is not checked when empty
+func (v *TokenVerifier) VerifyIDToken(ctx context.Context, token, nonce string) (*Claims, error) {
+	claims, err := v.verify(ctx, token, v.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, &ValidationError{"nonce does not match"}
+	}
+	return claims, nil
+}
+
+// VerifyAccessToken validates an access token of a custom authorization
+// server, access tokens of the org authorization server can only be
+// checked by introspection
+func (v *TokenVerifier) VerifyAccessToken(ctx context.Context, token string) (*Claims, error) {
+	return v.verify(ctx, token, v.Audience)
+}
+
+func (v *TokenVerifier) verify(ctx context.Context, token, audience string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, &ValidationError{"malformed token"}
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, &ValidationError{"malformed header"}
+	}
+	// Okta signs with RS256 only, anything else, in particular none or an
+	// HMAC keyed with the public key, is refused
+	if header.Alg != "RS256" {
+		return nil, &ValidationError{fmt.Sprintf("unexpected algorithm %q", header.Alg)}
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, &ValidationError{"malformed signature"}
+	}
+	hash := crypto.SHA256.New()
+	hash.Write([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash.Sum(nil), signature); err != nil {
+		return nil, &ValidationError{"invalid signature"}
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], &claims.Raw); err != nil {
+		return nil, &ValidationError{"malformed claims"}
+	}
+	claims.parse()
+
+	skew := v.ClockSkew
+	if skew == 0 {
+		skew = defaultClockSkew
+	}
+	now := time.Now()
+	switch {
+	case claims.Issuer != v.Issuer:
+		return nil, &ValidationError{fmt.Sprintf("unexpected issuer %q", claims.Issuer)}
+	case !contains(claims.Audience, audience):
+		return nil, &ValidationError{fmt.Sprintf("unexpected audience %v", claims.Audience)}
+	case claims.ExpiresAt.IsZero() || now.After(claims.ExpiresAt.Add(skew)):
+		return nil, &ValidationError{"token expired"}
+	case claims.IssuedAt.After(now.Add(skew)):
+		return nil, &ValidationError{"token issued in the future"}
+	}
+	if nbf, ok := numericDate(claims.Raw["nbf"]); ok && nbf.After(now.Add(skew)) {
+		return nil, &ValidationError{"token not valid yet"}
+	}
+	return claims, nil
+}
+
+func (c *Claims) parse() {
+	c.Issuer, _ = c.Raw["iss"].(string)
+	c.Subject, _ = c.Raw["sub"].(string)
+	c.Nonce, _ = c.Raw["nonce"].(string)
+	c.ClientID, _ = c.Raw["cid"].(string)
+	c.UserID, _ = c.Raw["uid"].(string)
+	c.ExpiresAt, _ = numericDate(c.Raw["exp"])
+	c.IssuedAt, _ = numericDate(c.Raw["iat"])
+	c.Audience = stringsClaim(c.Raw["aud"])
+	c.Scopes = stringsClaim(c.Raw["scp"])
+}
+
+// key returns the key with the given id, refetching the keys when it is
+// unknown as the authorization server may have rotated them
+func (v *TokenVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ttl := v.KeysTTL
+	if ttl == 0 {
+		ttl = defaultKeysTTL
+	}
+	key, ok := v.keys[kid]
+	expired := time.Since(v.fetched) > ttl
+	if ok && !expired {
+		return key, nil
+	}
+	if !expired && time.Since(v.fetched) < keysRefetchDelay {
+		return nil, &ValidationError{fmt.Sprintf("unknown key %q", kid)}
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+	v.fetched = time.Now()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, &ValidationError{fmt.Sprintf("unknown key %q", kid)}
+}
+
+func (v *TokenVerifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	config := &Config{Issuer: v.Issuer}
+	req, err := http.NewRequestWithContext(ctx, "GET", config.Endpoint("keys"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth: fetching keys failed with %s", resp.Status)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) > 4 {
+			continue
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("oauth: no signing keys published")
+	}
+	return keys, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func numericDate(value interface{}) (time.Time, bool) {
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// stringsClaim reads a claim that is either a string or an array of strings
+func stringsClaim(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func signToken(t *testing.T, key *rsa.PrivateKey, header, claims map[string]interface{}) string {
+	segment := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+	sum := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestTokenVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer server.Close()
+
+	issuer := server.URL + "/oauth2/default"
+	verifier := NewTokenVerifier(&Config{Issuer: issuer, ClientID: "client"}, "api://default")
+	header := map[string]interface{}{"alg": "RS256", "kid": "key1"}
+	claims := map[string]interface{}{
+		"iss":   issuer,
+		"aud":   "client",
+		"sub":   "00u1",
+		"nonce": "nonce",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+
+	verified, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, header, claims), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Subject != "00u1" {
+		t.Error("Unexpected subject ", verified.Subject)
+	}
+
+	if _, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, header, claims), "other"); err == nil {
+		t.Error("Expected a nonce mismatch to fail")
+	}
+	if _, err := verifier.VerifyAccessToken(context.Background(), signToken(t, key, header, claims)); err == nil {
+		t.Error("Expected an ID token to fail as access token")
+	}
+
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	if _, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, header, claims), ""); err == nil {
+		t.Error("Expected an expired token to fail")
+	}
+
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := verifier.VerifyIDToken(context.Background(), signToken(t, other, header, claims), ""); err == nil {
+		t.Error("Expected a token signed with another key to fail")
+	}
+
+	unknown := map[string]interface{}{"alg": "RS256", "kid": "key2"}
+	if _, err := verifier.VerifyIDToken(context.Background(), signToken(t, key, unknown, claims), ""); err == nil {
+		t.Error("Expected a token with an unknown key to fail")
+	}
+	if fetches != 1 {
+		t.Error("Expected the keys to be fetched once, got ", fetches)
+	}
+}
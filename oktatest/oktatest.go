@@ -0,0 +1,357 @@
+// Package oktatest builds realistic, fully populated Okta resources for the
+// unit tests of code using the okta package, in place of hand written
+// fixtures:
+//
+//	user := oktatest.NewTestUser(func(u *okta.User) {
+//		u.Status = okta.UserLockedOut
+//	})
+//
+// The values are random but reproducible, Fakers of the same seed and Now
+// return the same resources in the same order.
+package oktatest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+// DefaultOrgURL is the org of the ids and links of the resources
+const DefaultOrgURL = "https://example.okta.com"
+
+// idChars are the characters of Okta ids after their 3 character prefix
+const idChars = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var (
+	firstNames  = []string{"Jane", "John", "Maria", "Wei", "Aisha", "Lucas", "Priya", "Olga", "Kenji", "Fatima", "Noah", "Emma"}
+	lastNames   = []string{"Doe", "Smith", "Garcia", "Chen", "Okafor", "Silva", "Patel", "Ivanova", "Tanaka", "Haddad", "Miller", "Jones"}
+	departments = []string{"Engineering", "Finance", "Sales", "Marketing", "Support", "Legal", "Operations"}
+	titles      = []string{"Engineer", "Analyst", "Manager", "Director", "Consultant", "Specialist"}
+	cities      = []struct{ city, state, zip string }{
+		{"Atlanta", "GA", "30301"},
+		{"Austin", "TX", "73301"},
+		{"Chicago", "IL", "60601"},
+		{"Denver", "CO", "80201"},
+		{"Seattle", "WA", "98101"},
+	}
+	streets    = []string{"Main St", "Oak Ave", "Peachtree Rd", "Market St", "Elm St"}
+	languages  = []string{"en", "en-US", "es", "fr", "de"}
+	timeZones  = []string{"America/New_York", "America/Chicago", "America/Denver", "America/Los_Angeles"}
+	groupNames = []string{"Everyone Engineering", "Finance Approvers", "Sales EMEA", "VPN Users", "Contractors", "Help Desk"}
+)
+
+// Faker generates the resources from its own source of randomness, it is
+// safe for concurrent use
+type Faker struct {
+	// OrgURL is the org of the links, DefaultOrgURL when empty
+	OrgURL string
+	// Now is the time the timestamps are before, the time New was called
+	Now time.Time
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New returns a Faker of seed
+func New(seed int64) *Faker {
+	return &Faker{
+		Now:  time.Now().UTC().Truncate(time.Millisecond),
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+var defaultFaker = New(1)
+
+// NewTestUser returns an ACTIVE user with every standard profile attribute,
+// custom attributes and lifecycle links set, modified by mods
+func NewTestUser(mods ...func(*okta.User)) *okta.User {
+	return defaultFaker.User(mods...)
+}
+
+// NewTestGroup returns an OKTA_GROUP with stats embedded, modified by mods
+func NewTestGroup(mods ...func(*okta.Group)) *okta.Group {
+	return defaultFaker.Group(mods...)
+}
+
+// NewTestAuthnResponse returns a transaction in status with the state
+// token, embedded user, factors and links of that status, modified by mods
+func NewTestAuthnResponse(status okta.AuthnStatus, mods ...func(*okta.AuthnResponse)) *okta.AuthnResponse {
+	return defaultFaker.AuthnResponse(status, mods...)
+}
+
+// User is NewTestUser of the Faker
+func (f *Faker) User(mods ...func(*okta.User)) *okta.User {
+	f.mu.Lock()
+	first, last := f.pick(firstNames), f.pick(lastNames)
+	login := strings.ToLower(first+"."+last) + fmt.Sprintf("%d@example.com", f.rand.Intn(1000))
+	place := cities[f.rand.Intn(len(cities))]
+	user := &okta.User{
+		ID:     f.id("00u"),
+		Status: okta.UserActive,
+		Profile: okta.UserProfile{
+			Login:             login,
+			FirstName:         first,
+			LastName:          last,
+			NickName:          first,
+			DisplayName:       first + " " + last,
+			Email:             login,
+			SecondEmail:       strings.Replace(login, "@example.com", "@example.org", 1),
+			ProfileURL:        "https://intranet.example.com/people/" + strings.ToLower(first),
+			PreferredLanguage: f.pick(languages),
+			UserType:          "Employee",
+			Organization:      "Example Inc",
+			Title:             f.pick(titles),
+			Division:          "Americas",
+			Department:        f.pick(departments),
+			CostCenter:        fmt.Sprintf("CC-%04d", f.rand.Intn(10000)),
+			EmployeeNumber:    fmt.Sprintf("%06d", f.rand.Intn(1000000)),
+			MobilePhone:       f.phone(),
+			PrimaryPhone:      f.phone(),
+			StreetAddress:     fmt.Sprintf("%d %s", 1+f.rand.Intn(9999), f.pick(streets)),
+			City:              place.city,
+			State:             place.state,
+			ZipCode:           place.zip,
+			CountryCode:       "US",
+			Custom: map[string]interface{}{
+				"badge":    fmt.Sprintf("%d", f.rand.Intn(100000)),
+				"location": place.city,
+			},
+		},
+	}
+	created := f.before(f.Now, 365*24*time.Hour)
+	activated := f.after(*created, time.Hour)
+	passwordChanged := f.after(*activated, 90*24*time.Hour)
+	lastLogin := f.after(*passwordChanged, 24*time.Hour)
+	user.Created = created
+	user.Activated = activated
+	user.StatusChanged = activated
+	user.PasswordChanged = passwordChanged
+	user.LastLogin = lastLogin
+	user.LastUpdated = passwordChanged
+	user.Credentials.RecoveryQuestion.Question = "What is the name of your first pet?"
+	user.Credentials.Provider.Type = "OKTA"
+	user.Credentials.Provider.Name = "OKTA"
+
+	self := f.orgURL() + "/api/v1/users/" + user.ID
+	user.Links = okta.UserLinks{
+		ResetPassword:          f.link(self+"/lifecycle/reset_password", "POST"),
+		ResetFactors:           f.link(self+"/lifecycle/reset_factors", "POST"),
+		ExpirePassword:         f.link(self+"/lifecycle/expire_password", "POST"),
+		ForgotPassword:         f.link(self+"/credentials/forgot_password", "POST"),
+		ChangeRecoveryQuestion: f.link(self+"/credentials/change_recovery_question", "POST"),
+		Deactivate:             f.link(self+"/lifecycle/deactivate", "POST"),
+		ChangePassword:         f.link(self+"/credentials/change_password", "POST"),
+	}
+	user.Links.All = okta.Links{
+		"self":                   {{Href: self}},
+		"resetPassword":          {user.Links.ResetPassword},
+		"resetFactors":           {user.Links.ResetFactors},
+		"expirePassword":         {user.Links.ExpirePassword},
+		"forgotPassword":         {user.Links.ForgotPassword},
+		"changeRecoveryQuestion": {user.Links.ChangeRecoveryQuestion},
+		"deactivate":             {user.Links.Deactivate},
+		"changePassword":         {user.Links.ChangePassword},
+	}
+	f.mu.Unlock()
+
+	for _, mod := range mods {
+		mod(user)
+	}
+	return user
+}
+
+// Group is NewTestGroup of the Faker
+func (f *Faker) Group(mods ...func(*okta.Group)) *okta.Group {
+	f.mu.Lock()
+	name := f.pick(groupNames)
+	group := &okta.Group{
+		ID:          f.id("00g"),
+		Type:        okta.GroupOkta,
+		ObjectClass: []string{"okta:user_group"},
+		Profile: okta.GroupProfile{
+			Name:        name,
+			Description: "Members of " + name,
+		},
+	}
+	group.Created = f.before(f.Now, 365*24*time.Hour)
+	group.LastUpdated = f.after(*group.Created, 30*24*time.Hour)
+	group.LastMembershipUpdated = f.after(*group.LastUpdated, 24*time.Hour)
+	group.Embedded.Stats = &okta.GroupStats{
+		UsersCount: 1 + f.rand.Intn(500),
+		AppsCount:  f.rand.Intn(20),
+	}
+
+	self := f.orgURL() + "/api/v1/groups/" + group.ID
+	group.Links = okta.Links{
+		"logo": {
+			{Name: "medium", Href: f.orgURL() + "/assets/img/logos/groups/okta-medium.png", Type: "image/png"},
+			{Name: "large", Href: f.orgURL() + "/assets/img/logos/groups/okta-large.png", Type: "image/png"},
+		},
+		"users": {{Href: self + "/users"}},
+		"apps":  {{Href: self + "/apps"}},
+	}
+	f.mu.Unlock()
+
+	for _, mod := range mods {
+		mod(group)
+	}
+	return group
+}
+
+// AuthnResponse is NewTestAuthnResponse of the Faker
+func (f *Faker) AuthnResponse(status okta.AuthnStatus, mods ...func(*okta.AuthnResponse)) *okta.AuthnResponse {
+	user := f.User()
+
+	f.mu.Lock()
+	response := &okta.AuthnResponse{Status: status}
+	response.Embedded.User.ID = user.ID
+	response.Embedded.User.PasswordChanged = user.PasswordChanged
+	response.Embedded.User.Profile.Login = user.Profile.Login
+	response.Embedded.User.Profile.FirstName = user.Profile.FirstName
+	response.Embedded.User.Profile.LastName = user.Profile.LastName
+	response.Embedded.User.Profile.Locale = "en_US"
+	response.Embedded.User.Profile.TimeZone = f.pick(timeZones)
+
+	authn := f.orgURL() + "/api/v1/authn"
+	switch status {
+	case okta.AuthnSuccess:
+		response.SessionToken = f.token(25)
+		expiresAt := f.Now.Add(5 * time.Minute)
+		response.ExpiresAt = &expiresAt
+	case okta.AuthnUnauthenticated:
+	default:
+		response.StateToken = f.token(42)
+		expiresAt := f.Now.Add(5 * time.Minute)
+		response.ExpiresAt = &expiresAt
+		response.Links.Cancel.Href = authn + "/cancel"
+		response.Links.Cancel.Hints.Allow = []string{"POST"}
+	}
+
+	switch status {
+	case okta.AuthnMFARequired, okta.AuthnMFAChallenge:
+		response.Embedded.Factors = []okta.Factor{
+			f.factor(user.ID, okta.FactorPush, okta.FactorProviderOkta),
+			f.factor(user.ID, okta.FactorTOTP, okta.FactorProviderOkta),
+			f.factor(user.ID, okta.FactorSMS, okta.FactorProviderOkta),
+		}
+		response.Embedded.Policy.AllowRememberDevice = true
+		response.Embedded.Policy.RememberDeviceLifetimeInMinutes = 720
+		if status == okta.AuthnMFAChallenge {
+			factor := response.Embedded.Factors[2]
+			response.Embedded.Factor = &factor
+			response.Embedded.Factors = nil
+			response.Links.Next.Name = "verify"
+			response.Links.Next.Href = factor.Links.Verify.Href
+			response.Links.Next.Hints.Allow = []string{"POST"}
+			response.Links.Prev.Href = authn + "/previous"
+			response.Links.Resend = append(response.Links.Resend, struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			}{Name: "sms", Href: factor.Links.Verify.Href + "/resend"})
+		}
+	case okta.AuthnMFAEnroll:
+		for _, factorType := range []okta.FactorType{okta.FactorTOTP, okta.FactorSMS} {
+			factor := f.factor(user.ID, factorType, okta.FactorProviderOkta)
+			factor.ID = ""
+			factor.Links.Verify.Href = ""
+			factor.Links.Verify.Hints.Allow = nil
+			factor.Links.Enroll.Href = authn + "/factors"
+			response.Embedded.Factors = append(response.Embedded.Factors, factor)
+		}
+	case okta.AuthnPasswordWarn, okta.AuthnPasswordExpired:
+		response.Links.Next.Name = "changePassword"
+		response.Links.Next.Href = authn + "/credentials/change_password"
+		response.Links.Next.Hints.Allow = []string{"POST"}
+		if status == okta.AuthnPasswordWarn {
+			response.Links.Skip.Href = authn + "/skip"
+		}
+	}
+	f.mu.Unlock()
+
+	for _, mod := range mods {
+		mod(response)
+	}
+	return response
+}
+
+// factor returns an enrolled factor of a user with its verify link
+func (f *Faker) factor(userID string, factorType okta.FactorType, provider okta.FactorProvider) okta.Factor {
+	factor := okta.Factor{
+		ID:         f.id(factorPrefix(factorType)),
+		FactorType: factorType,
+		Provider:   provider,
+		VendorName: string(provider),
+	}
+	switch factorType {
+	case okta.FactorSMS:
+		factor.Profile.CredentialID = f.phone()
+	default:
+		factor.Profile.CredentialID = strings.ToLower(userID) + "@example.com"
+	}
+	factor.Links.Verify.Href = f.orgURL() + "/api/v1/authn/factors/" + factor.ID + "/verify"
+	factor.Links.Verify.Hints.Allow = []string{"POST"}
+	return factor
+}
+
+// factorPrefix is the prefix of the ids of the factors of factorType
+func factorPrefix(factorType okta.FactorType) string {
+	switch factorType {
+	case okta.FactorSMS:
+		return "mbl"
+	case okta.FactorPush:
+		return "opf"
+	default:
+		return "ost"
+	}
+}
+
+// id returns an Okta id of prefix such as 00u for users
+func (f *Faker) id(prefix string) string {
+	return prefix + f.token(17)
+}
+
+func (f *Faker) token(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = idChars[f.rand.Intn(len(idChars))]
+	}
+	return string(b)
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.rand.Intn(len(values))]
+}
+
+func (f *Faker) phone() string {
+	return fmt.Sprintf("+1 %03d-555-%04d", 200+f.rand.Intn(800), f.rand.Intn(10000))
+}
+
+// before returns a time up to d before t
+func (f *Faker) before(t time.Time, d time.Duration) *time.Time {
+	before := t.Add(-time.Duration(f.rand.Int63n(int64(d)))).Truncate(time.Millisecond)
+	return &before
+}
+
+// after returns a time up to d after t, but not after Now
+func (f *Faker) after(t time.Time, d time.Duration) *time.Time {
+	after := t.Add(time.Duration(f.rand.Int63n(int64(d)))).Truncate(time.Millisecond)
+	if after.After(f.Now) {
+		after = f.Now
+	}
+	return &after
+}
+
+func (f *Faker) link(href, method string) okta.Link {
+	return okta.Link{Href: href, Method: method}
+}
+
+func (f *Faker) orgURL() string {
+	if f.OrgURL == "" {
+		return DefaultOrgURL
+	}
+	return strings.TrimSuffix(f.OrgURL, "/")
+}
@@ -0,0 +1,100 @@
+package oktatest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+func TestNewTestUser(t *testing.T) {
+	user := NewTestUser(func(u *okta.User) {
+		u.Status = okta.UserLockedOut
+	})
+	if user.Status != okta.UserLockedOut {
+		t.Error("Expected the mod to apply, got ", user.Status)
+	}
+	if len(user.ID) != 20 || !strings.HasPrefix(user.ID, "00u") {
+		t.Error("Unexpected id ", user.ID)
+	}
+
+	// every standard attribute of the profile is set
+	profile := reflect.ValueOf(user.Profile)
+	for i := 0; i < profile.NumField(); i++ {
+		if profile.Field(i).IsZero() {
+			t.Error("Expected profile attribute ", profile.Type().Field(i).Name, " to be set")
+		}
+	}
+	if user.Created == nil || user.Activated.Before(*user.Created) || user.LastLogin.Before(*user.PasswordChanged) {
+		t.Error("Expected ordered timestamps, got ", user.Created, user.Activated, user.PasswordChanged, user.LastLogin)
+	}
+	if user.Links.Deactivate.Href != DefaultOrgURL+"/api/v1/users/"+user.ID+"/lifecycle/deactivate" || len(user.Links.All["self"]) != 1 {
+		t.Error("Unexpected links ", user.Links)
+	}
+
+	// the user survives a round trip through the API representation
+	data, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded okta.User
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Profile.Login != user.Profile.Login || decoded.Profile.Custom["badge"] != user.Profile.Custom["badge"] {
+		t.Error("Expected the profile to round trip, got ", decoded.Profile)
+	}
+}
+
+func TestFakerSeed(t *testing.T) {
+	a, b := New(42), New(42)
+	b.Now = a.Now
+	if !reflect.DeepEqual(a.User(), b.User()) || !reflect.DeepEqual(a.Group(), b.Group()) {
+		t.Error("Expected the same resources of the same seed")
+	}
+	if a.User().ID == a.User().ID {
+		t.Error("Expected different users")
+	}
+
+	c := New(42)
+	c.OrgURL = "https://acme.oktapreview.com/"
+	if users, _ := c.Group().Links.Get("users"); !strings.HasPrefix(users.Href, "https://acme.oktapreview.com/api/v1/groups/") {
+		t.Error("Expected links of the org, got ", users.Href)
+	}
+}
+
+func TestNewTestAuthnResponse(t *testing.T) {
+	mfa := NewTestAuthnResponse(okta.AuthnMFARequired)
+	if mfa.StateToken == "" || mfa.SessionToken != "" || mfa.Links.Cancel.Href == "" {
+		t.Error("Unexpected transaction ", mfa.StateToken, mfa.SessionToken, mfa.Links.Cancel.Href)
+	}
+	if len(mfa.GetSupportedFactors()) == 0 {
+		t.Error("Expected supported factors")
+	}
+	for _, factor := range mfa.Embedded.Factors {
+		if factor.ID == "" || !strings.HasSuffix(factor.Links.Verify.Href, "/factors/"+factor.ID+"/verify") {
+			t.Error("Unexpected factor ", factor)
+		}
+	}
+
+	challenge := NewTestAuthnResponse(okta.AuthnMFAChallenge)
+	if challenge.Embedded.Factor == nil || challenge.Links.Next.Href != challenge.Embedded.Factor.Links.Verify.Href || len(challenge.Links.Resend) != 1 {
+		t.Error("Unexpected challenge ", challenge.Embedded.Factor, challenge.Links)
+	}
+
+	success := NewTestAuthnResponse(okta.AuthnSuccess, func(r *okta.AuthnResponse) {
+		r.RelayState = "/dashboard"
+	})
+	if !success.Done() || success.SessionToken == "" || success.StateToken != "" || success.RelayState != "/dashboard" {
+		t.Error("Unexpected success ", success.SessionToken, success.StateToken, success.RelayState)
+	}
+	if success.Embedded.User.ID == "" || success.Embedded.User.Profile.Login == "" {
+		t.Error("Expected the user to be embedded")
+	}
+
+	if warn := NewTestAuthnResponse(okta.AuthnPasswordWarn); !warn.CanSkip() || warn.Links.Next.Name != "changePassword" {
+		t.Error("Unexpected password warning ", warn.Links)
+	}
+}
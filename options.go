@@ -0,0 +1,133 @@
+package okta
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions are the paging parameters of list endpoints. Limit is the
+// page size, pages are still followed until the end, After is the cursor
+// to start from.
+type ListOptions struct {
+	After string
+	Limit int
+}
+
+func (o *ListOptions) encode(v url.Values, limit int) {
+	if o.Limit > 0 {
+		limit = o.Limit
+	}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	if o.After != "" {
+		v.Set("after", o.After)
+	}
+}
+
+// ListUsersOptions filters ListUsers. Q matches the start of the login,
+// email and names, Filter and Search take Okta filter and search
+// expressions such as status eq "ACTIVE"
+type ListUsersOptions struct {
+	Q      string
+	Filter string
+	Search string
+	ListOptions
+}
+
+func (o *ListUsersOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		o = &ListUsersOptions{}
+	}
+	setNonEmpty(v, "q", o.Q)
+	setNonEmpty(v, "filter", o.Filter)
+	setNonEmpty(v, "search", o.Search)
+	o.ListOptions.encode(v, 200)
+	return v
+}
+
+// ListGroupsOptions filters ListGroups, see ListUsersOptions. Expand may be
+// stats or app to embed them in the groups.
+type ListGroupsOptions struct {
+	Q      string
+	Filter string
+	Search string
+	Expand string
+	ListOptions
+}
+
+func (o *ListGroupsOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		o = &ListGroupsOptions{}
+	}
+	setNonEmpty(v, "q", o.Q)
+	setNonEmpty(v, "filter", o.Filter)
+	setNonEmpty(v, "search", o.Search)
+	setNonEmpty(v, "expand", o.Expand)
+	o.ListOptions.encode(v, 200)
+	return v
+}
+
+// ListLogsOptions filters ListLogs. Since and Until bound the published
+// time, SortOrder is ASCENDING unless set to DESCENDING.
+type ListLogsOptions struct {
+	Since     time.Time
+	Until     time.Time
+	Filter    string
+	Q         string
+	SortOrder string
+	ListOptions
+}
+
+func (o *ListLogsOptions) values() url.Values {
+	v := url.Values{}
+	if o == nil {
+		o = &ListLogsOptions{}
+	}
+	if !o.Since.IsZero() {
+		v.Set("since", o.Since.UTC().Format(logTimeFormat))
+	}
+	if !o.Until.IsZero() {
+		v.Set("until", o.Until.UTC().Format(logTimeFormat))
+	}
+	setNonEmpty(v, "filter", o.Filter)
+	setNonEmpty(v, "q", o.Q)
+	if o.SortOrder != "" {
+		v.Set("sortOrder", o.SortOrder)
+	} else {
+		v.Set("sortOrder", "ASCENDING")
+	}
+	o.ListOptions.encode(v, 0)
+	return v
+}
+
+func setNonEmpty(v url.Values, key, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
+
+// withQuery appends the encoded values to an endpoint
+func withQuery(endpoint string, v url.Values) string {
+	if len(v) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + v.Encode()
+}
+
+// filterExpr builds a filter or search comparison of attribute with a
+// quoted value, escaping the value so it can't end the string early
+func filterExpr(attribute, operator, value string) string {
+	return fmt.Sprintf(`%s %s "%s"`, attribute, operator, searchEscape(value))
+}
+
+// searchEscape escapes backslashes and quotes of a quoted filter or search
+// value
+func searchEscape(value string) string {
+	return strings.Replace(strings.Replace(value, `\`, `\\`, -1), `"`, `\"`, -1)
+}
@@ -0,0 +1,73 @@
+package okta
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestListOptionsValues(t *testing.T) {
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cases := map[string]interface{ values() url.Values }{
+		"limit=200": (*ListUsersOptions)(nil),
+		"filter=status+eq+%22ACTIVE%22&limit=200":                &ListUsersOptions{Filter: `status eq "ACTIVE"`},
+		"after=00u1&limit=25&q=jane%26co&search=a+b":             &ListUsersOptions{Q: "jane&co", Search: "a b", ListOptions: ListOptions{After: "00u1", Limit: 25}},
+		"expand=stats&limit=200":                                 &ListGroupsOptions{Expand: "stats"},
+		"since=2020-01-02T03%3A04%3A05.000Z&sortOrder=ASCENDING": &ListLogsOptions{Since: since},
+	}
+	for expected, opts := range cases {
+		if actual := opts.values().Encode(); actual != expected {
+			t.Error("Expected ", expected, ", got ", actual)
+		}
+	}
+}
+
+func TestAppLinksEscapesFilter(t *testing.T) {
+	var filter string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		w.Write([]byte(`[]`))
+	}))
+	defer done()
+
+	if _, err := client.AppLinks("00u1", `aws" or appName eq "x`); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `appName eq "aws\" or appName eq \"x"`; filter != expected {
+		t.Error("Expected ", expected, ", got ", filter)
+	}
+}
+
+func TestListGroupsSearch(t *testing.T) {
+	var queries []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`[
+			{"id":"00g1","type":"OKTA_GROUP","profile":{"name":"Engineering Managers"},"_embedded":{"stats":{"usersCount":12}}},
+			{"id":"00g2","type":"OKTA_GROUP","profile":{"name":"Engineering"}}
+		]`))
+	}))
+	defer done()
+
+	groups, err := client.ListGroups(&ListGroupsOptions{Q: "Engineering", Expand: "stats"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := (*groups)[0].Embedded.Stats; stats == nil || stats.UsersCount != 12 {
+		t.Error("Expected embedded stats, got ", stats)
+	}
+
+	group, err := client.GroupByName("Engineering")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group == nil || group.ID != "00g2" {
+		t.Error("Expected the group with the exact name, got ", group)
+	}
+
+	expected := []string{"expand=stats&limit=200&q=Engineering", "limit=200&q=Engineering"}
+	if len(queries) != 2 || queries[0] != expected[0] || queries[1] != expected[1] {
+		t.Error("Expected queries ", expected, ", got ", queries)
+	}
+}
@@ -0,0 +1,123 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+type OrgSettings struct {
+	ID                    string     `json:"id,omitempty"`
+	Subdomain             string     `json:"subdomain,omitempty"`
+	CompanyName           string     `json:"companyName"`
+	Status                string     `json:"status,omitempty"`
+	Website               string     `json:"website,omitempty"`
+	PhoneNumber           string     `json:"phoneNumber,omitempty"`
+	EndUserSupportHelpURL string     `json:"endUserSupportHelpURL,omitempty"`
+	SupportPhoneNumber    string     `json:"supportPhoneNumber,omitempty"`
+	Address1              string     `json:"address1,omitempty"`
+	Address2              string     `json:"address2,omitempty"`
+	City                  string     `json:"city,omitempty"`
+	State                 string     `json:"state,omitempty"`
+	Country               string     `json:"country,omitempty"`
+	PostalCode            string     `json:"postalCode,omitempty"`
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty"`
+	Created               *time.Time `json:"created,omitempty"`
+	LastUpdated           *time.Time `json:"lastUpdated,omitempty"`
+	// Unknown, see UnknownFields
+	Unknown UnknownFields `json:"-"`
+}
+
+// Org contact types
+const (
+	BillingContact   = "BILLING"
+	TechnicalContact = "TECHNICAL"
+)
+
+type OrgContact struct {
+	ContactType string `json:"contactType"`
+}
+
+type OrgContactUser struct {
+	UserID string `json:"userId"`
+}
+
+// OrgSupportSettings tells whether Okta Support may sign in to the org
+type OrgSupportSettings struct {
+	Support    string     `json:"support"`
+	Expiration *time.Time `json:"expiration"`
+}
+
+// OrgSettings returns the settings of the org
+func (c *Client) OrgSettings() (*OrgSettings, error) {
+	var response = &OrgSettings{}
+	err, _ := c.call("org", "GET", nil, response)
+	return response, err
+}
+
+// UpdateOrgSettings replaces the settings of the org
+func (c *Client) UpdateOrgSettings(settings *OrgSettings) (*OrgSettings, error) {
+	var response = &OrgSettings{}
+	err, _ := c.call("org", "PUT", settings, response)
+	return response, err
+}
+
+// UpdateOrgSettingsPartial changes only the settings present in settings,
+// which may be any value encoding to a JSON object
+func (c *Client) UpdateOrgSettingsPartial(settings interface{}) (*OrgSettings, error) {
+	var response = &OrgSettings{}
+	err, _ := c.call("org", "POST", settings, response)
+	return response, err
+}
+
+// OrgContacts returns the contact types of the org
+func (c *Client) OrgContacts() (*[]OrgContact, error) {
+	var response = &[]OrgContact{}
+	err := c.listAll("org/contacts", response)
+	return response, err
+}
+
+// OrgContactUser returns the user assigned to a contact type
+func (c *Client) OrgContactUser(contactType string) (*OrgContactUser, error) {
+	var response = &OrgContactUser{}
+	err, _ := c.call("org/contacts/"+url.PathEscape(contactType), "GET", nil, response)
+	return response, err
+}
+
+// UpdateOrgContactUser assigns a user to a contact type
+func (c *Client) UpdateOrgContactUser(contactType, userID string) (*OrgContactUser, error) {
+	var request = &OrgContactUser{
+		UserID: userID,
+	}
+
+	var response = &OrgContactUser{}
+	err, _ := c.call("org/contacts/"+url.PathEscape(contactType), "PUT", request, response)
+	return response, err
+}
+
+// OrgSupportSettings returns whether Okta Support currently has access
+func (c *Client) OrgSupportSettings() (*OrgSupportSettings, error) {
+	var response = &OrgSupportSettings{}
+	err, _ := c.call("org/privacy/oktaSupport", "GET", nil, response)
+	return response, err
+}
+
+// GrantOktaSupport gives Okta Support access to the org for 8 hours
+func (c *Client) GrantOktaSupport() (*OrgSupportSettings, error) {
+	var response = &OrgSupportSettings{}
+	err, _ := c.call("org/privacy/oktaSupport/grant", "POST", nil, response)
+	return response, err
+}
+
+// ExtendOktaSupport extends the current Okta Support access by 24 hours
+func (c *Client) ExtendOktaSupport() (*OrgSupportSettings, error) {
+	var response = &OrgSupportSettings{}
+	err, _ := c.call("org/privacy/oktaSupport/extend", "POST", nil, response)
+	return response, err
+}
+
+// RevokeOktaSupport ends Okta Support access immediately
+func (c *Client) RevokeOktaSupport() (*OrgSupportSettings, error) {
+	var response = &OrgSupportSettings{}
+	err, _ := c.call("org/privacy/oktaSupport/revoke", "POST", nil, response)
+	return response, err
+}
@@ -0,0 +1,123 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestOrgSettings(t *testing.T) {
+	var bodies []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := ioutil.ReadAll(r.Body); len(body) > 0 {
+			bodies = append(bodies, string(body))
+		}
+		w.Write([]byte(`{"id":"00o1","subdomain":"example","companyName":"Example","status":"ACTIVE","website":"https://example.com"}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	settings, err := client.OrgSettings()
+	if err != nil || settings.Subdomain != "example" || settings.Status != "ACTIVE" {
+		t.Fatal("Expected the org settings, got ", settings, err)
+	}
+	if _, err := client.UpdateOrgSettings(&OrgSettings{CompanyName: "Example", Website: "https://example.com", Country: "US"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateOrgSettingsPartial(map[string]string{"supportPhoneNumber": "+1-555-0100"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"companyName":"Example","website":"https://example.com","country":"US"}`,
+		`{"supportPhoneNumber":"+1-555-0100"}`,
+	}
+	if len(bodies) != len(expected) || bodies[0] != expected[0] || bodies[1] != expected[1] {
+		t.Error("Expected bodies ", expected, ", got ", bodies)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/org",
+		"PUT /api/v1/org",
+		"POST /api/v1/org",
+	)
+}
+
+func TestOrgContacts(t *testing.T) {
+	var assigned string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/org/contacts" && r.URL.Query().Get("after") == "":
+			w.Header().Set("Link", `<https://`+r.Host+`/api/v1/org/contacts?after=BILLING>; rel="next"`)
+			w.Write([]byte(`[{"contactType":"BILLING"}]`))
+		case r.URL.Path == "/api/v1/org/contacts":
+			w.Write([]byte(`[{"contactType":"TECHNICAL"}]`))
+		case r.Method == "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			assigned = string(body)
+			w.Write(body)
+		default:
+			w.Write([]byte(`{"userId":"00u1"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	contacts, err := client.OrgContacts()
+	if err != nil || len(*contacts) != 2 || (*contacts)[0].ContactType != BillingContact || (*contacts)[1].ContactType != TechnicalContact {
+		t.Fatal("Expected the contact types of both pages, got ", contacts, err)
+	}
+	user, err := client.OrgContactUser(BillingContact)
+	if err != nil || user.UserID != "00u1" {
+		t.Fatal("Expected the billing contact, got ", user, err)
+	}
+	user, err = client.UpdateOrgContactUser(TechnicalContact, "00u2")
+	if err != nil || user.UserID != "00u2" || assigned != `{"userId":"00u2"}` {
+		t.Fatal("Expected the technical contact to be assigned, got ", assigned, user, err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/org/contacts",
+		"GET /api/v1/org/contacts?after=BILLING",
+		"GET /api/v1/org/contacts/BILLING",
+		"PUT /api/v1/org/contacts/TECHNICAL",
+	)
+}
+
+func TestOktaSupport(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/org/privacy/oktaSupport/revoke":
+			w.Write([]byte(`{"support":"DISABLED","expiration":null}`))
+		case "/api/v1/org/privacy/oktaSupport/extend":
+			w.Write([]byte(`{"support":"ENABLED","expiration":"2026-10-15T18:00:00.000Z"}`))
+		default:
+			w.Write([]byte(`{"support":"ENABLED","expiration":"2026-10-14T18:00:00.000Z"}`))
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	if settings, err := client.OrgSupportSettings(); err != nil || settings.Support != "ENABLED" {
+		t.Fatal("Expected the support settings, got ", settings, err)
+	}
+	settings, err := client.GrantOktaSupport()
+	if err != nil || settings.Expiration == nil || settings.Expiration.Day() != 14 {
+		t.Fatal("Expected access to be granted, got ", settings, err)
+	}
+	settings, err = client.ExtendOktaSupport()
+	if err != nil || settings.Expiration == nil || settings.Expiration.Day() != 15 {
+		t.Fatal("Expected access to be extended, got ", settings, err)
+	}
+	settings, err = client.RevokeOktaSupport()
+	if err != nil || settings.Support != "DISABLED" || settings.Expiration != nil {
+		t.Fatal("Expected access to be revoked, got ", settings, err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/org/privacy/oktaSupport",
+		"POST /api/v1/org/privacy/oktaSupport/grant",
+		"POST /api/v1/org/privacy/oktaSupport/extend",
+		"POST /api/v1/org/privacy/oktaSupport/revoke",
+	)
+}
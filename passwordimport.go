@@ -0,0 +1,104 @@
+package okta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Password hash algorithms accepted by Okta when importing users
+const (
+	HashBcrypt = "BCRYPT"
+	HashSHA512 = "SHA-512"
+	HashSHA256 = "SHA-256"
+	HashSHA1   = "SHA-1"
+	HashMD5    = "MD5"
+	HashPBKDF2 = "PBKDF2"
+)
+
+// UserCredentials are the credentials of a user being created
+type UserCredentials struct {
+	Password         *PasswordCredential `json:"password,omitempty"`
+	RecoveryQuestion *RecoveryQuestion   `json:"recovery_question,omitempty"`
+}
+
+// PasswordCredential sets the password of a user being created, either in
+// clear text, as a hash imported from another IdP or verified by the
+// password import inline hook on the first sign in
+type PasswordCredential struct {
+	Value string        `json:"value,omitempty"`
+	Hash  *PasswordHash `json:"hash,omitempty"`
+	Hook  *PasswordHook `json:"hook,omitempty"`
+}
+
+// PasswordHash is a hashed password. Salt and Value are base64 encoded,
+// except for BCRYPT where they are the radix-64 parts of the modular crypt
+// string, see BcryptHash.
+type PasswordHash struct {
+	Algorithm string `json:"algorithm"`
+	// WorkFactor is the cost of BCRYPT
+	WorkFactor int    `json:"workFactor,omitempty"`
+	Salt       string `json:"salt,omitempty"`
+	// SaltOrder is PREFIX or POSTFIX for the SHA and MD5 algorithms
+	SaltOrder string `json:"saltOrder,omitempty"`
+	Value     string `json:"value"`
+	// DigestAlgorithm, IterationCount and KeySize configure PBKDF2
+	DigestAlgorithm string `json:"digestAlgorithm,omitempty"`
+	IterationCount  int    `json:"iterationCount,omitempty"`
+	KeySize         int    `json:"keySize,omitempty"`
+}
+
+// PasswordHook defers the password of a user to the password import inline
+// hook, which is called on the first sign in
+type PasswordHook struct {
+	Type string `json:"type"`
+}
+
+type RecoveryQuestion struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// BcryptHash splits a bcrypt modular crypt string such as
+// $2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy into the
+// hash Okta expects
+func BcryptHash(hash string) (*PasswordHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "" || !strings.HasPrefix(parts[1], "2") || len(parts[3]) != 53 {
+		return nil, fmt.Errorf("okta: %q is not a bcrypt hash", hash)
+	}
+	cost, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("okta: %q is not a bcrypt hash", hash)
+	}
+	return &PasswordHash{
+		Algorithm:  HashBcrypt,
+		WorkFactor: cost,
+		Salt:       parts[3][:22],
+		Value:      parts[3][22:],
+	}, nil
+}
+
+// ImportUser creates a user keeping the password of another IdP, hash is
+// stored as is and the user can sign in with their current password. The
+// user is STAGED unless activate is true.
+func (c *Client) ImportUser(profile interface{}, hash *PasswordHash, activate bool) (*User, error) {
+	return c.CreateUser(&CreateUserRequest{
+		Profile: profile,
+		Credentials: &UserCredentials{
+			Password: &PasswordCredential{Hash: hash},
+		},
+	}, activate)
+}
+
+// ImportUserWithHook creates a user whose password is verified by the
+// password import inline hook of the org on their first sign in, see
+// PasswordImportInlineHookRequest
+func (c *Client) ImportUserWithHook(profile interface{}, activate bool) (*User, error) {
+	return c.CreateUser(&CreateUserRequest{
+		Profile: profile,
+		Credentials: &UserCredentials{
+			Password: &PasswordCredential{Hook: &PasswordHook{Type: "default"}},
+		},
+	}, activate)
+}
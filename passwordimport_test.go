@@ -0,0 +1,46 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestImportUser(t *testing.T) {
+	hash, err := BcryptHash("$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash.WorkFactor != 10 || hash.Salt != "N9qo8uLOickgx2ZMRZoMye" || hash.Value != "IjZAgcfl7p92ldGxad68LJZdL17lhWy" {
+		t.Errorf("Expected the parts of the bcrypt hash, got %+v", hash)
+	}
+	if _, err := BcryptHash("$1$salt$hash"); err == nil {
+		t.Error("Expected an error for a hash that isn't bcrypt")
+	}
+
+	var received map[string]interface{}
+	var query string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id":"00u1","status":"STAGED"}`))
+	}))
+	defer done()
+
+	if _, err := client.ImportUser(map[string]string{"login": "jane@example.com"}, hash, false); err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ := json.Marshal(received["credentials"])
+	expected := `{"password":{"hash":{"algorithm":"BCRYPT","salt":"N9qo8uLOickgx2ZMRZoMye","value":"IjZAgcfl7p92ldGxad68LJZdL17lhWy","workFactor":10}}}`
+	if string(encoded) != expected || query != "activate=false" {
+		t.Error("Expected ", expected, " with activate=false, got ", string(encoded), " ", query)
+	}
+
+	if _, err := client.ImportUserWithHook(map[string]string{"login": "jane@example.com"}, true); err != nil {
+		t.Fatal(err)
+	}
+	encoded, _ = json.Marshal(received["credentials"])
+	if expected := `{"password":{"hook":{"type":"default"}}}`; string(encoded) != expected {
+		t.Error("Expected ", expected, ", got ", string(encoded))
+	}
+}
@@ -0,0 +1,153 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ClientPool holds the clients of many orgs keyed by org URL. The clients
+// share the connections of one transport while each keeps its own
+// credentials, cookies and rate limiter, Okta rate limits being per org.
+type ClientPool struct {
+	// HTTPClient is the transport, timeout and redirect policy shared by
+	// the clients, the transport of NewClient when nil. Its cookie jar is
+	// not shared.
+	HTTPClient *http.Client
+	// Reserve is the share of every rate limit bucket of an org left to
+	// other clients, see NewBucketLimiter
+	Reserve float64
+	// Configure is called with every client added, e.g. to set Retry or
+	// OnRateLimit
+	Configure func(orgURL string, c *Client)
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewClientPool returns an empty pool, reserve is the share of the rate
+// limits of every org left to other clients
+func NewClientPool(reserve float64) *ClientPool {
+	return &ClientPool{Reserve: reserve}
+}
+
+// Add creates the client of the org of config, replacing the one the pool
+// had for the same org URL
+func (p *ClientPool) Add(config *Config) (*Client, error) {
+	client, err := config.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	shared := p.HTTPClient
+	if shared == nil {
+		shared = &http.Client{Transport: defaultTransport}
+	}
+	jar, _ := cookiejar.New(nil)
+	client.SetHTTPClient(&http.Client{
+		Transport:     shared.Transport,
+		CheckRedirect: shared.CheckRedirect,
+		Timeout:       shared.Timeout,
+		Jar:           jar,
+	})
+	client.Limiter = NewBucketLimiter(p.Reserve)
+
+	orgURL := poolKey(client.baseURL())
+	if p.Configure != nil {
+		p.Configure(orgURL, client)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients == nil {
+		p.clients = map[string]*Client{}
+	}
+	p.clients[orgURL] = client
+	return client, nil
+}
+
+// Client returns the client of an org URL such as https://example.okta.com
+func (p *ClientPool) Client(orgURL string) (*Client, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[poolKey(orgURL)]
+	return client, ok
+}
+
+// Remove drops the client of an org URL
+func (p *ClientPool) Remove(orgURL string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, poolKey(orgURL))
+}
+
+// OrgURLs returns the org URLs of the pool, sorted
+func (p *ClientPool) OrgURLs() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	orgURLs := make([]string, 0, len(p.clients))
+	for orgURL := range p.clients {
+		orgURLs = append(orgURLs, orgURL)
+	}
+	sort.Strings(orgURLs)
+	return orgURLs
+}
+
+// Each calls fn for every org with at most concurrency calls in flight
+// and returns a BatchError keyed by org URL for the calls that failed.
+// Orgs not started yet are skipped once ctx is done.
+func (p *ClientPool) Each(ctx context.Context, concurrency int, fn func(ctx context.Context, orgURL string, c *Client) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	failed := BatchError{}
+	var mu sync.Mutex
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for orgURL := range work {
+				client, ok := p.Client(orgURL)
+				if !ok {
+					continue
+				}
+				err := ctx.Err()
+				if err == nil {
+					err = fn(ctx, orgURL, client)
+				}
+				if err != nil {
+					mu.Lock()
+					failed[orgURL] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, orgURL := range p.OrgURLs() {
+		work <- orgURL
+	}
+	close(work)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// poolKey normalizes an org URL to its lowercase scheme and host
+func poolKey(orgURL string) string {
+	u, err := url.Parse(orgURL)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimSuffix(orgURL, "/"))
+	}
+	return fmt.Sprintf("%s://%s", strings.ToLower(u.Scheme), strings.ToLower(u.Host))
+}
@@ -0,0 +1,79 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientPool(t *testing.T) {
+	newOrg := func(token string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "SSWS "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"errorCode":"E0000011"}`))
+				return
+			}
+			w.Write([]byte(`{"id":"00o1","companyName":"` + token + `"}`))
+		}))
+	}
+	first, second := newOrg("first"), newOrg("second")
+	defer first.Close()
+	defer second.Close()
+
+	pool := NewClientPool(0.2)
+	var configured []string
+	pool.Configure = func(orgURL string, c *Client) {
+		configured = append(configured, orgURL)
+	}
+	for _, config := range []*Config{{OrgURL: first.URL, Token: "first"}, {OrgURL: second.URL + "/", Token: "second"}} {
+		if _, err := pool.Add(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(configured) != 2 || len(pool.OrgURLs()) != 2 {
+		t.Fatal("Expected both orgs to be added, got ", pool.OrgURLs())
+	}
+
+	client, ok := pool.Client(strings.ToUpper(second.URL))
+	if !ok {
+		t.Fatal("Expected the org URL to be matched case-insensitively")
+	}
+	if client.Limiter == nil {
+		t.Error("Expected every client to have a rate limiter")
+	}
+	if settings, err := client.OrgSettings(); err != nil || settings.CompanyName != "second" {
+		t.Error("Expected the credentials of the org, got ", err)
+	}
+	if first, _ := pool.Client(first.URL); first.client == client.client || first.client.Jar == client.client.Jar {
+		t.Error("Expected every client to keep its own cookies")
+	}
+
+	names := make(chan string, 2)
+	err := pool.Each(context.Background(), 2, func(ctx context.Context, orgURL string, c *Client) error {
+		settings, err := c.OrgSettings()
+		if err != nil {
+			return err
+		}
+		names <- settings.CompanyName
+		if settings.CompanyName == "first" {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	var failed BatchError
+	if !errors.As(err, &failed) || len(failed) != 1 || failed[first.URL] == nil {
+		t.Error("Expected the error of the first org, got ", err)
+	}
+	if len(names) != 2 {
+		t.Error("Expected every org to be called, got ", len(names))
+	}
+
+	pool.Remove(first.URL)
+	if _, ok := pool.Client(first.URL); ok || len(pool.OrgURLs()) != 1 {
+		t.Error("Expected the org to be removed, got ", pool.OrgURLs())
+	}
+}
@@ -0,0 +1,178 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the state of a rate limit bucket as of the last response
+// from one of its endpoints
+type RateLimit struct {
+	// Bucket is the method and endpoint template, e.g.
+	// GET users/{id}/groups, see UsageAnalyzer
+	Bucket    string
+	Limit     int
+	Remaining int
+	// Reset is when the bucket is refilled
+	Reset time.Time
+}
+
+// Utilization is the share of the bucket consumed, between 0 and 1
+func (r RateLimit) Utilization() float64 {
+	if r.Limit == 0 {
+		return 0
+	}
+	return float64(r.Limit-r.Remaining) / float64(r.Limit)
+}
+
+// RateLimits returns the latest rate limit of every bucket called so far
+func (c *Client) RateLimits() []RateLimit {
+	var limits []RateLimit
+	c.rateLimits.Range(func(_, value interface{}) bool {
+		limits = append(limits, value.(RateLimit))
+		return true
+	})
+	return limits
+}
+
+// RateLimit returns the latest rate limit of the bucket of an endpoint,
+// false if no response from it carried the X-Rate-Limit headers yet
+func (c *Client) RateLimit(method, endpoint string) (RateLimit, bool) {
+	value, ok := c.rateLimits.Load(method + " " + endpointTemplate(endpoint))
+	if !ok {
+		return RateLimit{}, false
+	}
+	return value.(RateLimit), true
+}
+
+// observeRateLimit stores the rate limit headers of a response and passes
+// them on to OnRateLimit
+func (c *Client) observeRateLimit(method, endpoint string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	limit, ok := parseRateLimit(resp.Header)
+	if !ok {
+		return
+	}
+	limit.Bucket = method + " " + endpointTemplate(endpoint)
+	c.rateLimits.Store(limit.Bucket, limit)
+	if limiter, ok := c.Limiter.(*BucketLimiter); ok {
+		limiter.observe(limit)
+	}
+	if c.OnRateLimit != nil {
+		c.OnRateLimit(limit)
+	}
+}
+
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-Rate-Limit-Limit"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-Rate-Limit-Remaining"))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	reset, err := strconv.ParseInt(header.Get("X-Rate-Limit-Reset"), 10, 64)
+	if err != nil {
+		return RateLimit{}, false
+	}
+	return RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}, true
+}
+
+// Limiter holds back requests before they are sent, a *rate.Limiter of
+// golang.org/x/time/rate can be used as one, as can a BucketLimiter
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+type rateBucketKey struct{}
+
+// BucketLimiter keeps requests under the Okta rate limits without external
+// coordination. It learns the limit of every bucket from the X-Rate-Limit
+// headers and counts its own requests against them, once only Reserve of a
+// bucket is left requests wait for its reset. Buckets not called yet are
+// not limited.
+type BucketLimiter struct {
+	// Reserve is the share of each bucket left to other clients of the org,
+	// between 0 and 1
+	Reserve float64
+
+	mu      sync.Mutex
+	buckets map[string]*limitBucket
+}
+
+type limitBucket struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// NewBucketLimiter returns a limiter leaving reserve of every bucket to
+// other clients, e.g. 0.2 for a bulk job that must not starve the sign in
+// flow sharing the org
+func NewBucketLimiter(reserve float64) *BucketLimiter {
+	return &BucketLimiter{Reserve: reserve, buckets: map[string]*limitBucket{}}
+}
+
+// Wait blocks until the bucket of the request has room or ctx is done
+func (l *BucketLimiter) Wait(ctx context.Context) error {
+	name, _ := ctx.Value(rateBucketKey{}).(string)
+	for {
+		l.mu.Lock()
+		bucket, ok := l.buckets[name]
+		if !ok || bucket.limit <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		now := time.Now()
+		if !now.Before(bucket.reset) {
+			// until Okta reports it the next window is assumed to follow
+			// the last one, rate limit windows are a minute
+			bucket.remaining = bucket.limit
+			bucket.reset = bucket.reset.Add((now.Sub(bucket.reset)/time.Minute + 1) * time.Minute)
+		}
+		if float64(bucket.remaining) > l.Reserve*float64(bucket.limit) {
+			bucket.remaining--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := bucket.reset.Sub(now)
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// observe replaces the count of a bucket with the one of Okta, which
+// includes the requests of other clients
+func (l *BucketLimiter) observe(limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buckets == nil {
+		l.buckets = map[string]*limitBucket{}
+	}
+	l.buckets[limit.Bucket] = &limitBucket{
+		limit:     limit.Limit,
+		remaining: limit.Remaining,
+		reset:     limit.Reset,
+	}
+}
+
+// limit waits for the Limiter of the client before a request is sent
+func (c *Client) limit(ctx context.Context, method, endpoint string) error {
+	if c.Limiter == nil {
+		return nil
+	}
+	return c.Limiter.Wait(context.WithValue(ctx, rateBucketKey{}, method+" "+endpointTemplate(endpoint)))
+}
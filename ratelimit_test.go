@@ -0,0 +1,103 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimits(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Unix()
+	remaining := 600
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining--
+		w.Header().Set("X-Rate-Limit-Limit", "600")
+		w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(reset, 10))
+		w.Write([]byte(`{}`))
+	}))
+	defer done()
+	var observed []RateLimit
+	client.OnRateLimit = func(limit RateLimit) {
+		observed = append(observed, limit)
+	}
+
+	client.User("00u1")
+	client.User("00u2")
+
+	limit, ok := client.RateLimit("GET", "users/00u3")
+	if !ok || limit.Bucket != "GET users/{id}" || limit.Remaining != 598 || limit.Reset.Unix() != reset {
+		t.Errorf("Expected the latest limit of the users bucket, got %+v", limit)
+	}
+	if len(observed) != 2 || observed[0].Remaining != 599 {
+		t.Errorf("Expected OnRateLimit for every response, got %+v", observed)
+	}
+	if limits := client.RateLimits(); len(limits) != 1 {
+		t.Errorf("Expected one bucket, got %+v", limits)
+	}
+	if _, ok := client.RateLimit("GET", "groups"); ok {
+		t.Error("Expected no limit for an endpoint never called")
+	}
+}
+
+func TestBucketLimiter(t *testing.T) {
+	limiter := NewBucketLimiter(0.5)
+	ctx := context.WithValue(context.Background(), rateBucketKey{}, "GET users/{id}")
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatal("Expected an unknown bucket not to be limited, got ", err)
+	}
+
+	limiter.observe(RateLimit{Bucket: "GET users/{id}", Limit: 4, Remaining: 3, Reset: time.Now().Add(200 * time.Millisecond)})
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed < 100*time.Millisecond {
+		t.Error("Expected to wait for the reset once the reserve is reached, waited ", elapsed)
+	}
+
+	limiter.observe(RateLimit{Bucket: "GET users/{id}", Limit: 4, Remaining: 0, Reset: time.Now().Add(time.Minute)})
+	canceled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(canceled); err != context.DeadlineExceeded {
+		t.Error("Expected the wait to end with the context, got ", err)
+	}
+}
+
+func TestBucketLimiterReset(t *testing.T) {
+	limiter := NewBucketLimiter(0.5)
+	ctx := context.WithValue(context.Background(), rateBucketKey{}, "GET users/{id}")
+	limiter.observe(RateLimit{Bucket: "GET users/{id}", Limit: 4, Remaining: 0, Reset: time.Now().Add(-time.Millisecond)})
+
+	canceled, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if limiter.Wait(canceled) == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 2 {
+		t.Error("Expected the refilled bucket to allow 2 requests until the next window, allowed ", allowed)
+	}
+	if reset := limiter.buckets["GET users/{id}"].reset; !reset.After(time.Now()) {
+		t.Error("Expected the reset to move to the next window, got ", reset)
+	}
+}
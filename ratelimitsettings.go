@@ -0,0 +1,125 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Per-client rate limit modes
+const (
+	PerClientEnforce = "ENFORCE"
+	PerClientPreview = "PREVIEW"
+	PerClientDisable = "DISABLE"
+)
+
+// PerClientRateLimitSettings is how the per-client rate limit of the
+// authorization endpoints applies, the overrides are by use case:
+// LOGIN_PAGE, OAUTH2_AUTHORIZE and OIE_APP_INTENT
+type PerClientRateLimitSettings struct {
+	DefaultMode          string            `json:"defaultMode"`
+	UseCaseModeOverrides map[string]string `json:"useCaseModeOverrides,omitempty"`
+}
+
+// PrincipalRateLimit is the share of the rate limits of the org an API
+// token or OAuth client may consume
+type PrincipalRateLimit struct {
+	ID          string `json:"id,omitempty"`
+	PrincipalID string `json:"principalId"`
+	// PrincipalType is SSWS_TOKEN or OAUTH_CLIENT
+	PrincipalType                string     `json:"principalType"`
+	DefaultPercentage            int        `json:"defaultPercentage,omitempty"`
+	DefaultConcurrencyPercentage int        `json:"defaultConcurrencyPercentage,omitempty"`
+	CreatedBy                    string     `json:"createdBy,omitempty"`
+	CreatedDate                  *time.Time `json:"createdDate,omitempty"`
+	LastUpdatedBy                string     `json:"lastUpdatedBy,omitempty"`
+	LastUpdate                   *time.Time `json:"lastUpdate,omitempty"`
+}
+
+// PerClientRateLimitSettings returns the per-client rate limit settings of
+// the org
+func (c *Client) PerClientRateLimitSettings() (*PerClientRateLimitSettings, error) {
+	var response = &PerClientRateLimitSettings{}
+	err, _ := c.call("rate-limit-settings/per-client", "GET", nil, response)
+	return response, err
+}
+
+// UpdatePerClientRateLimitSettings replaces the per-client rate limit
+// settings of the org
+func (c *Client) UpdatePerClientRateLimitSettings(settings *PerClientRateLimitSettings) (*PerClientRateLimitSettings, error) {
+	var response = &PerClientRateLimitSettings{}
+	err, _ := c.call("rate-limit-settings/per-client", "PUT", settings, response)
+	return response, err
+}
+
+// RateLimitWarningThreshold returns the percentage of a rate limit at which
+// admins are warned
+func (c *Client) RateLimitWarningThreshold() (int, error) {
+	var response struct {
+		WarningThreshold int `json:"warningThreshold"`
+	}
+	err, _ := c.call("rate-limit-settings/warning-threshold", "GET", nil, &response)
+	return response.WarningThreshold, err
+}
+
+// SetRateLimitWarningThreshold sets the percentage of a rate limit, between
+// 30 and 90, at which admins are warned
+func (c *Client) SetRateLimitWarningThreshold(percent int) error {
+	request := struct {
+		WarningThreshold int `json:"warningThreshold"`
+	}{percent}
+	err, _ := c.call("rate-limit-settings/warning-threshold", "PUT", request, nil)
+	return err
+}
+
+// RateLimitNotificationsEnabled tells whether admins get an email when the
+// org hits a rate limit
+func (c *Client) RateLimitNotificationsEnabled() (bool, error) {
+	var response struct {
+		NotificationsEnabled bool `json:"notificationsEnabled"`
+	}
+	err, _ := c.call("rate-limit-settings/admin-notifications", "GET", nil, &response)
+	return response.NotificationsEnabled, err
+}
+
+// SetRateLimitNotifications turns the rate limit emails to admins on or
+// off
+func (c *Client) SetRateLimitNotifications(enabled bool) error {
+	request := struct {
+		NotificationsEnabled bool `json:"notificationsEnabled"`
+	}{enabled}
+	err, _ := c.call("rate-limit-settings/admin-notifications", "PUT", request, nil)
+	return err
+}
+
+// ListPrincipalRateLimits returns the principal rate limits of a principal
+// type, SSWS_TOKEN or OAUTH_CLIENT
+func (c *Client) ListPrincipalRateLimits(principalType string) (*[]PrincipalRateLimit, error) {
+	v := url.Values{}
+	v.Set("filter", filterExpr("principalType", "eq", principalType))
+
+	var response = &[]PrincipalRateLimit{}
+	err := c.listAll(withQuery("principal-rate-limits", v), response)
+	return response, err
+}
+
+// PrincipalRateLimit returns the principal rate limit with the given id
+func (c *Client) PrincipalRateLimit(limitID string) (*PrincipalRateLimit, error) {
+	var response = &PrincipalRateLimit{}
+	err, _ := c.call("principal-rate-limits/"+url.PathEscape(limitID), "GET", nil, response)
+	return response, err
+}
+
+// CreatePrincipalRateLimit limits the share of an API token or OAuth client
+func (c *Client) CreatePrincipalRateLimit(limit *PrincipalRateLimit) (*PrincipalRateLimit, error) {
+	var response = &PrincipalRateLimit{}
+	err, _ := c.call("principal-rate-limits", "POST", limit, response)
+	return response, err
+}
+
+// UpdatePrincipalRateLimit replaces the principal rate limit with the given
+// id
+func (c *Client) UpdatePrincipalRateLimit(limitID string, limit *PrincipalRateLimit) (*PrincipalRateLimit, error) {
+	var response = &PrincipalRateLimit{}
+	err, _ := c.call("principal-rate-limits/"+url.PathEscape(limitID), "PUT", limit, response)
+	return response, err
+}
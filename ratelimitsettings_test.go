@@ -0,0 +1,47 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestRateLimitSettings(t *testing.T) {
+	var requests []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		switch r.URL.Path {
+		case "/api/v1/rate-limit-settings/warning-threshold":
+			w.Write([]byte(`{"warningThreshold":90}`))
+		case "/api/v1/principal-rate-limits":
+			w.Write([]byte(`[{"id":"0oacamvryxiyMqgiD0g4","principalId":"00T1","principalType":"SSWS_TOKEN","defaultPercentage":50}]`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer done()
+
+	threshold, err := client.RateLimitWarningThreshold()
+	if err != nil || threshold != 90 {
+		t.Error("Expected the threshold, got ", threshold, err)
+	}
+	if err := client.SetRateLimitWarningThreshold(60); err != nil {
+		t.Error(err)
+	}
+	limits, err := client.ListPrincipalRateLimits("SSWS_TOKEN")
+	if err != nil || len(*limits) != 1 || (*limits)[0].DefaultPercentage != 50 {
+		t.Error("Expected the principal rate limit, got ", limits, err)
+	}
+
+	expected := []string{
+		"GET /api/v1/rate-limit-settings/warning-threshold ",
+		`PUT /api/v1/rate-limit-settings/warning-threshold {"warningThreshold":60}`,
+		"GET /api/v1/principal-rate-limits?filter=principalType+eq+%22SSWS_TOKEN%22 ",
+	}
+	for i := range expected {
+		if i >= len(requests) || requests[i] != expected[i] {
+			t.Fatal("Expected requests ", expected, ", got ", requests)
+		}
+	}
+}
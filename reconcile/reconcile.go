@@ -0,0 +1,705 @@
+// Package reconcile compares the desired state of an Okta org with the org
+// and plans the creates, updates and deletes bringing the org to it, a
+// small terraform for the users, groups, group rules and apps of scripted
+// orgs. The state of one org read with Current can be planned into another.
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	okta "github.com/Cox-Automotive/go-okta"
+	"github.com/Cox-Automotive/go-okta/sync"
+)
+
+// Client is the part of okta.Client used by Diff and Apply
+type Client interface {
+	okta.UserService
+	okta.GroupService
+	okta.AppService
+}
+
+// State is the desired state of an org. Users and groups are those of the
+// sync package: users are matched by login, groups by name, group rules by
+// name and apps by label.
+type State struct {
+	Users      []sync.User  `json:"users"`
+	Groups     []sync.Group `json:"groups"`
+	GroupRules []GroupRule  `json:"groupRules"`
+	Apps       []App        `json:"apps"`
+}
+
+// GroupRule is a group rule of a State, Groups are the names of the groups
+// it assigns
+type GroupRule struct {
+	Name       string   `json:"name"`
+	Expression string   `json:"expression"`
+	Groups     []string `json:"groups"`
+	Inactive   bool     `json:"inactive,omitempty"`
+}
+
+// App is an app instance of a State. Only the top level Settings it
+// carries are compared, the other settings of the app are kept.
+type App struct {
+	Label      string                 `json:"label"`
+	Name       string                 `json:"name"`
+	SignOnMode string                 `json:"signOnMode"`
+	Settings   map[string]interface{} `json:"settings,omitempty"`
+	Inactive   bool                   `json:"inactive,omitempty"`
+}
+
+// ReadState reads a State encoded as JSON, numbers are kept as json.Number
+func ReadState(r io.Reader) (*State, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var state = &State{}
+	if err := decoder.Decode(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Current returns the state of an org, see sync.Export for the users and
+// groups included
+func Current(c Client) (*State, error) {
+	snapshot, err := sync.Export(c)
+	if err != nil {
+		return nil, err
+	}
+	state := &State{Users: snapshot.Users, Groups: snapshot.Groups}
+
+	names := map[string]string{}
+	for _, group := range snapshot.Groups {
+		names[group.ID] = group.Name
+	}
+	rules, err := c.GroupRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range *rules {
+		current := GroupRule{
+			Name:       rule.Name,
+			Expression: rule.Conditions.Expression.Value,
+			Inactive:   rule.Status == "INACTIVE",
+		}
+		for _, groupID := range rule.Actions.AssignUserToGroups.GroupIDs {
+			current.Groups = append(current.Groups, names[groupID])
+		}
+		state.GroupRules = append(state.GroupRules, current)
+	}
+
+	apps, err := c.ListApps()
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range *apps {
+		state.Apps = append(state.Apps, App{
+			Label:      app.Label,
+			Name:       app.Name,
+			SignOnMode: app.SignOnMode,
+			Settings:   app.Settings,
+			Inactive:   app.Status == "INACTIVE",
+		})
+	}
+	return state, nil
+}
+
+// Options changes what Diff plans
+type Options struct {
+	// Prune deletes the OKTA_GROUP groups, group rules, apps and users the
+	// desired state doesn't list, and removes users from the groups they
+	// don't list. The desired state then has to list every user to keep,
+	// the admin owning the API token included. Memberships of groups
+	// assigned by a group rule are left to the rule.
+	Prune bool
+}
+
+// Operation is what a Change does
+type Operation string
+
+const (
+	Create Operation = "create"
+	Update Operation = "update"
+	Delete Operation = "delete"
+)
+
+// Kind is what a Change is made to
+type Kind string
+
+const (
+	KindGroup      Kind = "group"
+	KindUser       Kind = "user"
+	KindMembership Kind = "membership"
+	KindGroupRule  Kind = "group-rule"
+	KindApp        Kind = "app"
+)
+
+// Change is a change of a Plan. Name is the group name, login, rule name
+// or app label, or the login of the member for memberships with Group the
+// group name. ID is the id of the object updated or deleted and Fields are
+// the fields an update changes.
+type Change struct {
+	Operation Operation
+	Kind      Kind
+	Name      string
+	Group     string
+	ID        string
+	Fields    []string
+
+	apply func(*Plan, Client) error
+}
+
+func (c Change) String() string {
+	symbol := map[Operation]string{Create: "+", Update: "~", Delete: "-"}[c.Operation]
+	switch {
+	case c.Kind == KindMembership && c.Operation == Delete:
+		return fmt.Sprintf("%s %s %s from %s", symbol, c.Kind, c.Name, c.Group)
+	case c.Kind == KindMembership:
+		return fmt.Sprintf("%s %s %s to %s", symbol, c.Kind, c.Name, c.Group)
+	case len(c.Fields) > 0:
+		return fmt.Sprintf("%s %s %s (%s)", symbol, c.Kind, c.Name, strings.Join(c.Fields, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", symbol, c.Kind, c.Name)
+}
+
+// Plan is the changes bringing an org to the desired state in the order
+// they are applied: creates and updates of groups, users, memberships,
+// group rules and apps, then the deletes of group rules, apps,
+// memberships, users and groups.
+type Plan struct {
+	Changes []Change
+
+	// Applied is the number of changes Apply made, applying the plan again
+	// after a failure resumes with the change that failed
+	Applied int
+
+	// groups and users map the names and lowercase logins to the ids known
+	// so far, those created are added by Apply
+	groups map[string]string
+	users  map[string]string
+}
+
+// Empty reports whether the org is already in the desired state
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Apply makes the changes of the plan, stopping at the first that fails
+func (p *Plan) Apply(c Client) error {
+	for ; p.Applied < len(p.Changes); p.Applied++ {
+		change := p.Changes[p.Applied]
+		if err := change.apply(p, c); err != nil {
+			return fmt.Errorf("reconcile: %s: %v", change, err)
+		}
+	}
+	return nil
+}
+
+func (p *Plan) groupIDs(names []string) []string {
+	var ids []string
+	for _, name := range names {
+		ids = append(ids, p.groups[name])
+	}
+	return ids
+}
+
+// Diff plans the changes bringing the org of c to the desired state
+func Diff(c Client, desired *State, opts *Options) (*Plan, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	d := &differ{
+		client:  c,
+		desired: desired,
+		opts:    opts,
+		plan:    &Plan{groups: map[string]string{}, users: map[string]string{}},
+	}
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	d.diffGroups()
+	if err := d.diffUsers(); err != nil {
+		return nil, err
+	}
+	d.diffMemberships()
+	d.diffGroupRules()
+	if err := d.diffApps(); err != nil {
+		return nil, err
+	}
+	if opts.Prune {
+		d.prune()
+	}
+	return d.plan, nil
+}
+
+type differ struct {
+	client  Client
+	desired *State
+	opts    *Options
+	plan    *Plan
+
+	// the current state keyed by group name, lowercase login, rule name
+	// and app label, members holds the member ids of every group id
+	groups  map[string]*okta.Group
+	users   map[string]*okta.User
+	members map[string]map[string]bool
+	rules   map[string]*okta.GroupRule
+	apps    map[string]*okta.App
+
+	// the keys of the current state in order, for deletes
+	groupNames, logins, ruleNames, appLabels []string
+}
+
+// load fetches the current state of the org
+func (d *differ) load() error {
+	groups, err := d.client.ListGroups(&okta.ListGroupsOptions{Filter: `type eq "` + okta.GroupOkta + `"`})
+	if err != nil {
+		return err
+	}
+	d.groups = map[string]*okta.Group{}
+	d.members = map[string]map[string]bool{}
+	for i := range *groups {
+		group := &(*groups)[i]
+		d.groups[group.Profile.Name] = group
+		d.groupNames = append(d.groupNames, group.Profile.Name)
+		d.plan.groups[group.Profile.Name] = group.ID
+
+		members, err := d.client.GroupMembers(group.ID)
+		if err != nil {
+			return err
+		}
+		d.members[group.ID] = map[string]bool{}
+		for _, member := range *members {
+			d.members[group.ID][member.ID] = true
+		}
+	}
+
+	users, err := d.client.ListUsers(nil)
+	if err != nil {
+		return err
+	}
+	d.users = map[string]*okta.User{}
+	for i := range *users {
+		user := &(*users)[i]
+		login := strings.ToLower(user.Profile.Login)
+		d.users[login] = user
+		d.logins = append(d.logins, login)
+		d.plan.users[login] = user.ID
+	}
+
+	rules, err := d.client.GroupRules()
+	if err != nil {
+		return err
+	}
+	d.rules = map[string]*okta.GroupRule{}
+	for i := range *rules {
+		d.rules[(*rules)[i].Name] = &(*rules)[i]
+		d.ruleNames = append(d.ruleNames, (*rules)[i].Name)
+	}
+
+	apps, err := d.client.ListApps()
+	if err != nil {
+		return err
+	}
+	d.apps = map[string]*okta.App{}
+	for i := range *apps {
+		d.apps[(*apps)[i].Label] = &(*apps)[i]
+		d.appLabels = append(d.appLabels, (*apps)[i].Label)
+	}
+
+	sort.Strings(d.groupNames)
+	sort.Strings(d.logins)
+	sort.Strings(d.ruleNames)
+	sort.Strings(d.appLabels)
+	return nil
+}
+
+// validate checks that the groups referenced by the desired state exist or
+// are going to
+func (d *differ) validate() error {
+	known := map[string]bool{}
+	for _, group := range d.desired.Groups {
+		known[group.Name] = true
+	}
+	if !d.opts.Prune {
+		for name := range d.groups {
+			known[name] = true
+		}
+	}
+
+	for _, user := range d.desired.Users {
+		if user.Login() == "" {
+			return fmt.Errorf("reconcile: user %q has no login", user.ID)
+		}
+		for _, name := range user.Groups {
+			if !known[name] {
+				return fmt.Errorf("reconcile: user %s is a member of unknown group %q", user.Login(), name)
+			}
+		}
+	}
+	for _, rule := range d.desired.GroupRules {
+		for _, name := range rule.Groups {
+			if !known[name] {
+				return fmt.Errorf("reconcile: group rule %s assigns unknown group %q", rule.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *differ) add(change Change) {
+	d.plan.Changes = append(d.plan.Changes, change)
+}
+
+func (d *differ) diffGroups() {
+	for _, group := range d.desired.Groups {
+		profile := okta.GroupProfile{Name: group.Name, Description: group.Description}
+		current, ok := d.groups[group.Name]
+		switch {
+		case !ok:
+			name := group.Name
+			d.add(Change{Operation: Create, Kind: KindGroup, Name: name, apply: func(p *Plan, c Client) error {
+				created, err := c.CreateGroup(profile)
+				if err != nil {
+					return err
+				}
+				p.groups[name] = created.ID
+				return nil
+			}})
+		case current.Profile.Description != group.Description:
+			id := current.ID
+			d.add(Change{Operation: Update, Kind: KindGroup, Name: group.Name, ID: id, Fields: []string{"description"},
+				apply: func(p *Plan, c Client) error {
+					_, err := c.UpdateGroup(id, profile)
+					return err
+				}})
+		}
+	}
+}
+
+func (d *differ) diffUsers() error {
+	for _, user := range d.desired.Users {
+		login := user.Login()
+		current, ok := d.users[strings.ToLower(login)]
+		if !ok {
+			profile := user.Profile
+			d.add(Change{Operation: Create, Kind: KindUser, Name: login, apply: func(p *Plan, c Client) error {
+				created, err := c.CreateUser(&okta.CreateUserRequest{Profile: profile}, true)
+				if err != nil {
+					return err
+				}
+				p.users[strings.ToLower(login)] = created.ID
+				return nil
+			}})
+			continue
+		}
+
+		changed, err := sync.ChangedAttributes(user, current)
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		var fields []string
+		for name := range changed {
+			fields = append(fields, name)
+		}
+		sort.Strings(fields)
+		id := current.ID
+		d.add(Change{Operation: Update, Kind: KindUser, Name: login, ID: id, Fields: fields,
+			apply: func(p *Plan, c Client) error {
+				_, err := c.UpdateUserProfilePartial(id, changed)
+				return err
+			}})
+	}
+	return nil
+}
+
+func (d *differ) diffMemberships() {
+	for _, user := range d.desired.Users {
+		login := user.Login()
+		current := d.users[strings.ToLower(login)]
+		for _, name := range user.Groups {
+			if group, ok := d.groups[name]; ok && current != nil && d.members[group.ID][current.ID] {
+				continue
+			}
+			group := name
+			d.add(Change{Operation: Create, Kind: KindMembership, Name: login, Group: group,
+				apply: func(p *Plan, c Client) error {
+					return c.AddUserToGroup(p.groups[group], p.users[strings.ToLower(login)])
+				}})
+		}
+	}
+}
+
+func (d *differ) diffGroupRules() {
+	names := map[string]string{}
+	for name, group := range d.groups {
+		names[group.ID] = name
+	}
+
+	for _, rule := range d.desired.GroupRules {
+		rule := rule
+		current, ok := d.rules[rule.Name]
+		if !ok {
+			d.add(Change{Operation: Create, Kind: KindGroupRule, Name: rule.Name, apply: func(p *Plan, c Client) error {
+				created, err := c.CreateGroupRule(okta.NewGroupRule(rule.Name, rule.Expression, p.groupIDs(rule.Groups)...))
+				if err != nil || rule.Inactive {
+					return err
+				}
+				return c.ActivateGroupRule(created.ID)
+			}})
+			continue
+		}
+
+		var currentGroups []string
+		for _, groupID := range current.Actions.AssignUserToGroups.GroupIDs {
+			currentGroups = append(currentGroups, names[groupID])
+		}
+		var fields []string
+		if current.Conditions.Expression.Value != rule.Expression {
+			fields = append(fields, "expression")
+		}
+		if !sameSet(currentGroups, rule.Groups) {
+			fields = append(fields, "groups")
+		}
+		active := current.Status == "ACTIVE"
+		if active == rule.Inactive {
+			fields = append(fields, "status")
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		id, replace := current.ID, len(fields) > 1 || fields[0] != "status"
+		d.add(Change{Operation: Update, Kind: KindGroupRule, Name: rule.Name, ID: id, Fields: fields,
+			apply: func(p *Plan, c Client) error {
+				// rules can only be changed while inactive
+				if active {
+					if err := c.DeactivateGroupRule(id); err != nil {
+						return err
+					}
+				}
+				if replace {
+					updated := okta.NewGroupRule(rule.Name, rule.Expression, p.groupIDs(rule.Groups)...)
+					updated.ID = id
+					if _, err := c.UpdateGroupRule(id, updated); err != nil {
+						return err
+					}
+				}
+				if rule.Inactive {
+					return nil
+				}
+				return c.ActivateGroupRule(id)
+			}})
+	}
+}
+
+func (d *differ) diffApps() error {
+	for _, app := range d.desired.Apps {
+		app := app
+		current, ok := d.apps[app.Label]
+		if !ok {
+			d.add(Change{Operation: Create, Kind: KindApp, Name: app.Label, apply: func(p *Plan, c Client) error {
+				_, err := c.CreateApp(&okta.AppRequest{
+					Name:       app.Name,
+					Label:      app.Label,
+					SignOnMode: app.SignOnMode,
+					Settings:   app.Settings,
+				}, !app.Inactive)
+				return err
+			}})
+			continue
+		}
+		if app.Name != "" && app.Name != current.Name {
+			return fmt.Errorf("reconcile: app %s is a %s app, can't change it to %s", app.Label, current.Name, app.Name)
+		}
+
+		var fields []string
+		if app.SignOnMode != "" && app.SignOnMode != current.SignOnMode {
+			fields = append(fields, "signOnMode")
+		}
+		settings := map[string]interface{}{}
+		for name, value := range current.Settings {
+			settings[name] = value
+		}
+		for name, value := range app.Settings {
+			if !sync.Equal(value, current.Settings[name]) {
+				fields = append(fields, "settings."+name)
+			}
+			settings[name] = value
+		}
+		active := current.Status == "ACTIVE"
+		if active == app.Inactive {
+			fields = append(fields, "status")
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		request := &okta.AppRequest{
+			Name:       current.Name,
+			Label:      current.Label,
+			SignOnMode: current.SignOnMode,
+			Features:   current.Features,
+			Settings:   settings,
+		}
+		if app.SignOnMode != "" {
+			request.SignOnMode = app.SignOnMode
+		}
+		id, statusOnly := current.ID, len(fields) == 1 && fields[0] == "status"
+		d.add(Change{Operation: Update, Kind: KindApp, Name: app.Label, ID: id, Fields: fields,
+			apply: func(p *Plan, c Client) error {
+				if !statusOnly {
+					if _, err := c.UpdateApp(id, request); err != nil {
+						return err
+					}
+				}
+				switch {
+				case active && app.Inactive:
+					return c.DeactivateApp(id)
+				case !active && !app.Inactive:
+					return c.ActivateApp(id)
+				}
+				return nil
+			}})
+	}
+	return nil
+}
+
+// prune plans the deletes, group rules first as they reference groups
+func (d *differ) prune() {
+	desiredGroups := map[string]bool{}
+	for _, group := range d.desired.Groups {
+		desiredGroups[group.Name] = true
+	}
+	desiredUsers := map[string]map[string]bool{}
+	for _, user := range d.desired.Users {
+		groups := map[string]bool{}
+		for _, name := range user.Groups {
+			groups[name] = true
+		}
+		desiredUsers[strings.ToLower(user.Login())] = groups
+	}
+	desiredRules := map[string]bool{}
+	ruled := map[string]bool{}
+	for _, rule := range d.desired.GroupRules {
+		desiredRules[rule.Name] = true
+		for _, name := range rule.Groups {
+			ruled[name] = true
+		}
+	}
+	desiredApps := map[string]bool{}
+	for _, app := range d.desired.Apps {
+		desiredApps[app.Label] = true
+	}
+
+	for _, name := range d.ruleNames {
+		if desiredRules[name] {
+			continue
+		}
+		id, active := d.rules[name].ID, d.rules[name].Status == "ACTIVE"
+		d.add(Change{Operation: Delete, Kind: KindGroupRule, Name: name, ID: id, apply: func(p *Plan, c Client) error {
+			if active {
+				if err := c.DeactivateGroupRule(id); err != nil {
+					return err
+				}
+			}
+			return c.DeleteGroupRule(id)
+		}})
+	}
+
+	for _, label := range d.appLabels {
+		if desiredApps[label] {
+			continue
+		}
+		id, active := d.apps[label].ID, d.apps[label].Status == "ACTIVE"
+		d.add(Change{Operation: Delete, Kind: KindApp, Name: label, ID: id, apply: func(p *Plan, c Client) error {
+			if active {
+				if err := c.DeactivateApp(id); err != nil {
+					return err
+				}
+			}
+			return c.DeleteApp(id)
+		}})
+	}
+
+	for _, name := range d.groupNames {
+		group := d.groups[name]
+		if !desiredGroups[name] || ruled[name] {
+			continue
+		}
+		for _, login := range d.logins {
+			user := d.users[login]
+			groups, managed := desiredUsers[login]
+			if !managed || groups[name] || !d.members[group.ID][user.ID] {
+				continue
+			}
+			groupID, userID := group.ID, user.ID
+			d.add(Change{Operation: Delete, Kind: KindMembership, Name: user.Profile.Login, Group: name,
+				apply: func(p *Plan, c Client) error {
+					return c.RemoveUserFromGroup(groupID, userID)
+				}})
+		}
+	}
+
+	for _, login := range d.logins {
+		if _, ok := desiredUsers[login]; ok {
+			continue
+		}
+		user := d.users[login]
+		id, deprovisioned := user.ID, user.Status == okta.UserDeprovisioned
+		d.add(Change{Operation: Delete, Kind: KindUser, Name: user.Profile.Login, ID: id, apply: func(p *Plan, c Client) error {
+			if !deprovisioned {
+				if err := c.DeactivateUser(id); err != nil {
+					return err
+				}
+			}
+			return c.DeleteUser(id)
+		}})
+	}
+
+	for _, name := range d.groupNames {
+		if desiredGroups[name] {
+			continue
+		}
+		id := d.groups[name].ID
+		d.add(Change{Operation: Delete, Kind: KindGroup, Name: name, ID: id, apply: func(p *Plan, c Client) error {
+			return c.DeleteGroup(id)
+		}})
+	}
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]int{}
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		if seen[s] == 0 {
+			return false
+		}
+		seen[s]--
+	}
+	return true
+}
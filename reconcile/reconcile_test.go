@@ -0,0 +1,202 @@
+package reconcile
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	okta "github.com/Cox-Automotive/go-okta"
+	"github.com/Cox-Automotive/go-okta/sync"
+)
+
+// fakeOrg serves fixed lists and records every write, created objects get
+// the id new
+type fakeOrg struct {
+	lists  map[string]string
+	writes []string
+	bodies []map[string]interface{}
+}
+
+func (o *fakeOrg) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+	if r.Method == "GET" {
+		list, ok := o.lists[path]
+		if !ok {
+			list = "[]"
+		}
+		w.Write([]byte(list))
+		return
+	}
+
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	o.writes = append(o.writes, r.Method+" "+path)
+	o.bodies = append(o.bodies, body)
+	w.Write([]byte(`{"id":"new"}`))
+}
+
+// newFakeOrg returns the fake org, a client calling it and a func closing it
+func newFakeOrg() (*fakeOrg, *okta.Client, func()) {
+	org := &fakeOrg{lists: map[string]string{
+		"groups": `[
+			{"id":"00g1","type":"OKTA_GROUP","profile":{"name":"engineering","description":"Engineers"}},
+			{"id":"00g2","type":"OKTA_GROUP","profile":{"name":"legacy"}}
+		]`,
+		"groups/00g1/users": `[{"id":"00u1"},{"id":"00u2"}]`,
+		"groups/00g2/users": `[{"id":"00u2"}]`,
+		"users": `[
+			{"id":"00u1","status":"ACTIVE","profile":{"login":"jane@example.com","firstName":"Jane"}},
+			{"id":"00u2","status":"ACTIVE","profile":{"login":"john@example.com","firstName":"John"}},
+			{"id":"00u3","status":"ACTIVE","profile":{"login":"old@example.com"}}
+		]`,
+		"groups/rules": `[
+			{"id":"0pr1","status":"ACTIVE","name":"engineers",
+			 "conditions":{"expression":{"value":"user.department==\"Engineering\""}},
+			 "actions":{"assignUserToGroups":{"groupIds":["00g1"]}}}
+		]`,
+		"apps": `[
+			{"id":"0oa1","name":"bookmark","label":"Wiki","status":"ACTIVE","signOnMode":"BOOKMARK",
+			 "settings":{"app":{"url":"https://old.example.com"}}},
+			{"id":"0oa2","name":"bookmark","label":"Old wiki","status":"INACTIVE","signOnMode":"BOOKMARK"}
+		]`,
+	}}
+
+	server := httptest.NewTLSServer(org)
+	client := okta.NewClient("organization")
+	client.BaseURL = server.URL
+	client.SetHTTPClient(server.Client())
+	return org, client, server.Close
+}
+
+var desired = &State{
+	Groups: []sync.Group{
+		{Name: "engineering", Description: "Engineers"},
+		{Name: "sales", Description: "Sales"},
+	},
+	Users: []sync.User{
+		{Profile: map[string]interface{}{"login": "jane@example.com", "firstName": "Janet"}, Groups: []string{"engineering"}},
+		{Profile: map[string]interface{}{"login": "john@example.com", "firstName": "John"}, Groups: []string{"sales"}},
+		{Profile: map[string]interface{}{"login": "ann@example.com"}, Groups: []string{"sales"}},
+	},
+	GroupRules: []GroupRule{
+		{Name: "engineers", Expression: `user.department=="Engineering"`, Groups: []string{"engineering"}},
+		{Name: "sellers", Expression: `user.department=="Sales"`, Groups: []string{"sales"}},
+	},
+	Apps: []App{
+		{Label: "Wiki", Name: "bookmark", Settings: map[string]interface{}{
+			"app": map[string]interface{}{"url": "https://wiki.example.com"},
+		}},
+	},
+}
+
+func TestDiff(t *testing.T) {
+	org, client, done := newFakeOrg()
+	defer done()
+
+	plan, err := Diff(client, desired, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "+ group sales\n" +
+		"~ user jane@example.com (firstName)\n" +
+		"+ user ann@example.com\n" +
+		"+ membership john@example.com to sales\n" +
+		"+ membership ann@example.com to sales\n" +
+		"+ group-rule sellers\n" +
+		"~ app Wiki (settings.app)\n"
+	if plan.String() != expected {
+		t.Error("Unexpected plan\n", plan)
+	}
+	if len(org.writes) != 0 {
+		t.Error("Expected Diff not to write, got ", org.writes)
+	}
+
+	plan, err = Diff(client, desired, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected += "- app Old wiki\n" +
+		"- user old@example.com\n" +
+		"- group legacy\n"
+	if plan.String() != expected {
+		t.Error("Unexpected plan with Prune\n", plan)
+	}
+
+	unknown := &State{Users: []sync.User{{Profile: map[string]interface{}{"login": "ann@example.com"}, Groups: []string{"nope"}}}}
+	if _, err := Diff(client, unknown, nil); err == nil {
+		t.Error("Expected an error for an unknown group")
+	}
+}
+
+func TestApply(t *testing.T) {
+	org, client, done := newFakeOrg()
+	defer done()
+
+	plan, err := Diff(client, desired, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(client); err != nil {
+		t.Fatal(err)
+	}
+	writes := []string{
+		"POST groups",
+		"POST users/00u1",
+		"POST users",
+		"PUT groups/new/users/00u2",
+		"PUT groups/new/users/new",
+		"POST groups/rules",
+		"POST groups/rules/new/lifecycle/activate",
+		"PUT apps/0oa1",
+		"DELETE apps/0oa2",
+		"POST users/00u3/lifecycle/deactivate",
+		"DELETE users/00u3",
+		"DELETE groups/00g2",
+	}
+	if !reflect.DeepEqual(org.writes, writes) {
+		t.Error("Unexpected writes ", org.writes)
+	}
+	if plan.Applied != len(plan.Changes) {
+		t.Error("Expected every change to be applied, got ", plan.Applied)
+	}
+
+	rule := org.bodies[5]
+	actions, _ := rule["actions"].(map[string]interface{})
+	if !reflect.DeepEqual(actions["assignUserToGroups"], map[string]interface{}{"groupIds": []interface{}{"new"}}) {
+		t.Error("Expected the rule to assign the created group, got ", rule)
+	}
+	app := org.bodies[7]
+	if app["name"] != "bookmark" || app["signOnMode"] != "BOOKMARK" {
+		t.Error("Expected the app to keep its name and sign on mode, got ", app)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	_, client, done := newFakeOrg()
+	defer done()
+
+	state, err := Current(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Users) != 3 || len(state.Groups) != 2 || len(state.Apps) != 2 {
+		t.Fatal("Unexpected state ", state)
+	}
+	if state.GroupRules[0].Name != "engineers" || !reflect.DeepEqual(state.GroupRules[0].Groups, []string{"engineering"}) {
+		t.Error("Expected the rule to reference group names, got ", state.GroupRules)
+	}
+	if !state.Apps[1].Inactive {
+		t.Error("Expected the inactive app to be marked, got ", state.Apps[1])
+	}
+
+	plan, err := Diff(client, state, &Options{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Error("Expected no changes from the current state, got\n", plan)
+	}
+}
@@ -0,0 +1,233 @@
+package okta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces secrets in errors and debug output
+const redacted = "REDACTED"
+
+// secretHeaders carry credentials or sessions
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// secretNames are the lowercase names of query parameters, form fields and
+// JSON attributes holding secrets, password objects are redacted whole
+var secretNames = map[string]bool{
+	"password":         true,
+	"oldpassword":      true,
+	"newpassword":      true,
+	"temppassword":     true,
+	"passcode":         true,
+	"answer":           true,
+	"token":            true,
+	"sessiontoken":     true,
+	"statetoken":       true,
+	"recoverytoken":    true,
+	"activationtoken":  true,
+	"activationurl":    true,
+	"resetpasswordurl": true,
+	"code":             true,
+	"code_verifier":    true,
+	"client_secret":    true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"id_token":         true,
+	"client_assertion": true,
+	"assertion":        true,
+	"samlresponse":     true,
+	// push provider credentials
+	"tokensigningkey":    true,
+	"serviceaccountjson": true,
+	// TOTP enrollment
+	"sharedsecret": true,
+	// domain certificates
+	"privatekey": true,
+}
+
+// secretValueParents are the lowercase names of JSON attributes whose
+// value attributes are secrets, like the header an event or inline hook
+// authenticates its deliveries with
+var secretValueParents = map[string]bool{
+	"authscheme": true,
+	"headers":    true,
+}
+
+// redactURL replaces the values of secret query parameters
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	u.RawQuery = redactQuery(u.RawQuery)
+	return u.String()
+}
+
+func redactQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	changed := false
+	for name := range values {
+		if secretNames[strings.ToLower(name)] {
+			values[name] = []string{redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	return values.Encode()
+}
+
+// redactBody replaces secret attributes of a JSON or form encoded body,
+// other bodies are returned as they are
+func redactBody(contentType string, body []byte) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return []byte(redactQuery(string(body)))
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	data, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+func redactValue(value interface{}) interface{} {
+	return redactChild(value, "")
+}
+
+// redactChild redacts value, an attribute named parent or an element of it
+func redactChild(value interface{}, parent string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, attribute := range v {
+			lower := strings.ToLower(name)
+			if (secretNames[lower] || lower == "value" && secretValueParents[parent]) && attribute != nil {
+				v[name] = redacted
+			} else {
+				v[name] = redactChild(attribute, lower)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactChild(v[i], parent)
+		}
+	}
+	return value
+}
+
+// redactError removes secrets from the URL of a failed request
+func redactError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return &url.Error{Op: urlErr.Op, URL: redactURL(urlErr.URL), Err: urlErr.Err}
+	}
+	return err
+}
+
+// DebugTransport writes every request and response to Out with passwords,
+// tokens and cookies redacted, for troubleshooting. It is safe for
+// concurrent use, see Client.SetDebug.
+type DebugTransport struct {
+	// Transport sends the requests, http.DefaultTransport when nil
+	Transport http.RoundTripper
+	Out       io.Writer
+
+	mu sync.Mutex
+}
+
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var dump bytes.Buffer
+	fmt.Fprintf(&dump, "> %s %s\n", req.Method, redactURL(req.URL.String()))
+	writeHeaders(&dump, ">", req.Header)
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		clone := *req
+		clone.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req = &clone
+		writeBody(&dump, ">", req.Header.Get("Content-Type"), body)
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		fmt.Fprintf(&dump, "< %v\n", redactError(err))
+		t.write(dump.Bytes())
+		return nil, err
+	}
+
+	fmt.Fprintf(&dump, "< %s %s\n", resp.Proto, resp.Status)
+	writeHeaders(&dump, "<", resp.Header)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	writeBody(&dump, "<", resp.Header.Get("Content-Type"), body)
+	t.write(dump.Bytes())
+	return resp, err
+}
+
+func (t *DebugTransport) write(dump []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Out.Write(append(dump, '\n'))
+}
+
+func writeHeaders(w io.Writer, prefix string, header http.Header) {
+	var names []string
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if secretHeaders[http.CanonicalHeaderKey(name)] {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s %s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+func writeBody(w io.Writer, prefix, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s\n%s\n", prefix, redactBody(contentType, body))
+}
+
+// SetDebug dumps every request of the client and its response to w with
+// the secrets redacted, see DebugTransport. Calling SetHTTPClient
+// afterwards turns it off.
+func (c *Client) SetDebug(w io.Writer) {
+	client := *c.client
+	client.Transport = &DebugTransport{Transport: c.client.Transport, Out: w}
+	c.client = &client
+}
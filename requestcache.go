@@ -0,0 +1,102 @@
+package okta
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type requestCacheKey struct{}
+
+// requestCache shares lookups between everything handling one inbound
+// request, concurrent lookups of the same key wait for the first one
+type requestCache struct {
+	mu      sync.Mutex
+	entries map[requestCacheEntryKey]*requestCacheEntry
+}
+
+type requestCacheEntryKey struct {
+	client *Client
+	kind   string
+	id     string
+}
+
+type requestCacheEntry struct {
+	ready chan struct{}
+	value interface{}
+	err   error
+}
+
+// WithRequestCache returns a context carrying an empty lookup cache. The
+// Context variants of User and Groups called with it, or any context
+// derived from it, share their results instead of calling Okta again, so
+// scope the context to a single inbound request. Cached results are shared
+// between callers and must not be modified.
+func WithRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestCacheKey{}, &requestCache{
+		entries: map[requestCacheEntryKey]*requestCacheEntry{},
+	})
+}
+
+// cached returns the value stored under kind and id in the request cache of
+// ctx, calling fetch to fill it on first use or when ctx has no cache
+func (c *Client) cached(ctx context.Context, kind, id string, fetch func() (interface{}, error)) (interface{}, error) {
+	cache, ok := ctx.Value(requestCacheKey{}).(*requestCache)
+	if !ok {
+		return fetch()
+	}
+
+	// results fetched with embeds are kept apart from those without
+	if expand := callOptionsOf(ctx).expand; len(expand) > 0 {
+		kind += "?expand=" + strings.Join(expand, ",")
+	}
+	key := requestCacheEntryKey{client: c, kind: kind, id: id}
+
+	cache.mu.Lock()
+	entry, found := cache.entries[key]
+	if !found {
+		entry = &requestCacheEntry{ready: make(chan struct{})}
+		cache.entries[key] = entry
+	}
+	cache.mu.Unlock()
+
+	if found {
+		select {
+		case <-entry.ready:
+			return entry.value, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	entry.value, entry.err = fetch()
+	close(entry.ready)
+	return entry.value, entry.err
+}
+
+// UserContext is User using the request cache of ctx if it has one
+func (c *Client) UserContext(ctx context.Context, userID string) (*User, error) {
+	value, err := c.cached(ctx, "user", userID, func() (interface{}, error) {
+		var response = &User{}
+		err := c.lookupUser(ctx, userID, response)
+		return response, err
+	})
+	if value == nil {
+		return &User{}, err
+	}
+	return value.(*User), err
+}
+
+// GroupsContext is Groups using the request cache of ctx if it has one
+func (c *Client) GroupsContext(ctx context.Context, userID string) (*[]Group, error) {
+	value, err := c.cached(ctx, "groups", userID, func() (interface{}, error) {
+		var response = &[]Group{}
+		err := c.listAllContext(ctx, "users/"+url.PathEscape(userID)+"/groups?limit=200", response)
+		return response, err
+	})
+	if value == nil {
+		return &[]Group{}, err
+	}
+	return value.(*[]Group), err
+}
@@ -0,0 +1,43 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRequestCache(t *testing.T) {
+	var calls int32
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"id":"00u1"}`))
+	}))
+	defer done()
+
+	ctx := WithRequestCache(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := client.UserContext(ctx, "00u1")
+			if err != nil || user.ID != "00u1" {
+				t.Error("Expected user 00u1, got ", user, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Error("Expected a single call within the request scope, got ", calls)
+	}
+
+	if _, err := client.UserContext(context.Background(), "00u1"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Error("Expected a call without a request cache, got ", calls)
+	}
+}
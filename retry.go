@@ -0,0 +1,157 @@
+package okta
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultRetryMax      = 30 * time.Second
+)
+
+// Reasons for a retry
+const (
+	RetryRateLimited = "rate_limited"
+	RetryServerError = "server_error"
+	RetryNetwork     = "network_error"
+)
+
+// RetryPolicy decides how often a request is resent. Rate limited requests
+// wait until the X-Rate-Limit-Reset announced by Okta, server and network
+// errors back off exponentially. Only idempotent methods are resent after
+// a network or server error, a POST may already have been applied and is
+// only resent when rate limited or answered with 503 Service Unavailable.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a request is sent at most,
+	// including the first attempt, 3 when zero
+	MaxAttempts int
+	// Backoff is the wait before the first retry of a server or network
+	// error, doubled for every further attempt, 500ms when zero
+	Backoff time.Duration
+	// MaxWait caps a single wait, a rate limit resetting later than that
+	// is returned as an error instead, 30 seconds when zero
+	MaxWait time.Duration
+}
+
+// RetryEvent describes a retry before the client sleeps, letting operators
+// tell Okta throttling apart from slow or failing calls
+type RetryEvent struct {
+	Method   string
+	Endpoint string
+	// Attempt is the attempt that failed, 1 for the first request
+	Attempt int
+	// Reason is one of RetryRateLimited, RetryServerError or RetryNetwork
+	Reason string
+	// Status is the HTTP status of the failed attempt, 0 for network errors
+	Status int
+	// Sleep is how long the client waits before the next attempt
+	Sleep time.Duration
+	// Err is the network error for RetryNetwork
+	Err error
+}
+
+// retry decides whether a failed attempt is sent again and reports the
+// retry to OnRetry
+func (c *Client) retry(method, endpoint string, attempt int, resp *http.Response, err error) (RetryEvent, bool) {
+	event := RetryEvent{Method: method, Endpoint: endpoint, Attempt: attempt, Err: err}
+	policy := c.Retry
+	if policy == nil {
+		return event, false
+	}
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultRetryAttempts
+	}
+	if attempt >= maxAttempts {
+		return event, false
+	}
+
+	maxWait := policy.MaxWait
+	if maxWait == 0 {
+		maxWait = defaultRetryMax
+	}
+
+	switch {
+	case err != nil:
+		if !idempotent(method) {
+			return event, false
+		}
+		event.Reason = RetryNetwork
+		event.Sleep = policy.backoff(attempt)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		event.Reason = RetryRateLimited
+		event.Status = resp.StatusCode
+		event.Sleep = rateLimitWait(resp.Header)
+		if event.Sleep == 0 {
+			event.Sleep = policy.backoff(attempt)
+		}
+	case resp.StatusCode >= 500 && c.errorMap().Classify(resp.StatusCode, "").Retryable:
+		if !idempotent(method) && resp.StatusCode != http.StatusServiceUnavailable {
+			return event, false
+		}
+		event.Reason = RetryServerError
+		event.Status = resp.StatusCode
+		event.Sleep = policy.backoff(attempt)
+	default:
+		return event, false
+	}
+
+	if event.Sleep > maxWait {
+		return event, false
+	}
+	if c.OnRetry != nil {
+		c.OnRetry(event)
+	}
+	return event, true
+}
+
+// wait sleeps for the retry unless ctx is done first
+func (e RetryEvent) wait(ctx context.Context) error {
+	timer := time.NewTimer(e.Sleep)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	backoff <<= uint(attempt - 1)
+	// jitter keeps clients that failed together from retrying together
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// rateLimitWait is the time until the rate limit window resets, zero when
+// Okta didn't announce it
+func rateLimitWait(header http.Header) time.Duration {
+	reset, err := strconv.ParseInt(header.Get("X-Rate-Limit-Reset"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	wait := time.Until(time.Unix(reset, 0))
+	if wait < time.Second {
+		// the reset is only accurate to the second and clocks drift
+		wait = time.Second
+	}
+	return wait
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package okta
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errorCode":"E0000047"}`))
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"id":"00u1"}`))
+		}
+	}))
+	defer done()
+	client.Retry = &RetryPolicy{Backoff: time.Millisecond}
+
+	var events []RetryEvent
+	client.OnRetry = func(event RetryEvent) {
+		events = append(events, event)
+	}
+
+	user, err := client.User("00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "00u1" || calls != 3 {
+		t.Error("Expected success on the third attempt, got ", user.ID, calls)
+	}
+	if len(events) != 2 ||
+		events[0].Reason != RetryRateLimited || events[0].Attempt != 1 ||
+		events[1].Reason != RetryServerError || events[1].Status != http.StatusServiceUnavailable ||
+		events[1].Endpoint != "users/00u1" {
+		t.Error("Expected a rate limit and a server error retry, got ", events)
+	}
+
+	calls = 0
+	events = nil
+	client.Retry.MaxAttempts = 1
+	if _, err := client.User("00u1"); !IsRetryable(err) || len(events) != 0 {
+		t.Error("Expected the first error without retries, got ", err, events)
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	statuses := []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}
+	calls := 0
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[calls])
+		calls++
+		w.Write([]byte(`{"id":"00u1"}`))
+	}))
+	defer done()
+	client.Retry = &RetryPolicy{Backoff: time.Millisecond}
+
+	// the 503 was not processed, the 500 may have created the user
+	if _, err := client.CreateUser(&CreateUserRequest{}, false); err == nil || calls != 2 {
+		t.Error("Expected the POST to be sent again only after the 503, got ", err, calls)
+	}
+
+	calls = 1
+	if _, err := client.CreateUser(&CreateUserRequest{}, false); err == nil || calls != 2 {
+		t.Error("Expected a POST answered with 500 to be sent once, got ", err, calls)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	header := http.Header{}
+	if wait := rateLimitWait(header); wait != 0 {
+		t.Error("Expected no wait without a reset header, got ", wait)
+	}
+
+	header.Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10))
+	if wait := rateLimitWait(header); wait < 8*time.Second || wait > 10*time.Second {
+		t.Error("Expected to wait for the reset, got ", wait)
+	}
+
+	header.Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10))
+	if wait := rateLimitWait(header); wait != time.Second {
+		t.Error("Expected a minimal wait for a past reset, got ", wait)
+	}
+}
@@ -0,0 +1,145 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+// Standard administrator role types
+const (
+	SuperAdmin               = "SUPER_ADMIN"
+	OrgAdmin                 = "ORG_ADMIN"
+	AppAdmin                 = "APP_ADMIN"
+	UserAdmin                = "USER_ADMIN"
+	HelpDeskAdmin            = "HELP_DESK_ADMIN"
+	ReadOnlyAdmin            = "READ_ONLY_ADMIN"
+	MobileAdmin              = "MOBILE_ADMIN"
+	APIAccessManagementAdmin = "API_ACCESS_MANAGEMENT_ADMIN"
+	ReportAdmin              = "REPORT_ADMIN"
+	GroupMembershipAdmin     = "GROUP_MEMBERSHIP_ADMIN"
+)
+
+// Role is an administrator role assigned to a user or group
+type Role struct {
+	ID          string     `json:"id,omitempty"`
+	Label       string     `json:"label,omitempty"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	// AssignmentType is USER for direct assignments and GROUP for roles a
+	// user has through a group
+	AssignmentType string `json:"assignmentType,omitempty"`
+}
+
+// RoleAppTarget is an app, or app instance when ID is set, an APP_ADMIN
+// role is limited to
+type RoleAppTarget struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Status      string `json:"status"`
+	Category    string `json:"category"`
+}
+
+// ListRoles returns the administrator roles of a user, including those
+// assigned through groups
+func (c *Client) ListRoles(userID string) (*[]Role, error) {
+	var response = &[]Role{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/roles", response)
+	return response, err
+}
+
+// AssignRole assigns an administrator role to a user, roleType is one of
+// the standard role types such as UserAdmin
+func (c *Client) AssignRole(userID, roleType string) (*Role, error) {
+	var request = &Role{Type: roleType}
+
+	var response = &Role{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles", "POST", request, response)
+	return response, err
+}
+
+// UnassignRole removes an administrator role from a user
+func (c *Client) UnassignRole(userID, roleID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID), "DELETE", nil, nil)
+	return err
+}
+
+// ListGroupRoles returns the administrator roles assigned to a group
+func (c *Client) ListGroupRoles(groupID string) (*[]Role, error) {
+	var response = &[]Role{}
+	err := c.listAll("groups/"+url.PathEscape(groupID)+"/roles", response)
+	return response, err
+}
+
+// AssignGroupRole assigns an administrator role to every member of a group
+func (c *Client) AssignGroupRole(groupID, roleType string) (*Role, error) {
+	var request = &Role{Type: roleType}
+
+	var response = &Role{}
+	err, _ := c.call("groups/"+url.PathEscape(groupID)+"/roles", "POST", request, response)
+	return response, err
+}
+
+// UnassignGroupRole removes an administrator role from a group
+func (c *Client) UnassignGroupRole(groupID, roleID string) error {
+	err, _ := c.call("groups/"+url.PathEscape(groupID)+"/roles/"+url.PathEscape(roleID), "DELETE", nil, nil)
+	return err
+}
+
+// RoleGroupTargets returns the groups a USER_ADMIN, HELP_DESK_ADMIN or
+// GROUP_MEMBERSHIP_ADMIN role is limited to, none means all groups
+func (c *Client) RoleGroupTargets(userID, roleID string) (*[]Group, error) {
+	var response = &[]Group{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/groups", response)
+	return response, err
+}
+
+// AddRoleGroupTarget limits a role to a group, the first target turns an
+// unlimited role into a limited one
+func (c *Client) AddRoleGroupTarget(userID, roleID, groupID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/groups/"+url.PathEscape(groupID), "PUT", nil, nil)
+	return err
+}
+
+// RemoveRoleGroupTarget removes a group from the targets of a role, the
+// last target can't be removed
+func (c *Client) RemoveRoleGroupTarget(userID, roleID, groupID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/groups/"+url.PathEscape(groupID), "DELETE", nil, nil)
+	return err
+}
+
+// RoleAppTargets returns the apps and app instances an APP_ADMIN role is
+// limited to, none means all apps
+func (c *Client) RoleAppTargets(userID, roleID string) (*[]RoleAppTarget, error) {
+	var response = &[]RoleAppTarget{}
+	err := c.listAll("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/catalog/apps", response)
+	return response, err
+}
+
+// AddRoleAppTarget limits an APP_ADMIN role to every instance of an app,
+// appName is the catalog name such as amazon_aws
+func (c *Client) AddRoleAppTarget(userID, roleID, appName string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/catalog/apps/"+url.PathEscape(appName), "PUT", nil, nil)
+	return err
+}
+
+// AddRoleAppInstanceTarget limits an APP_ADMIN role to a single app instance
+func (c *Client) AddRoleAppInstanceTarget(userID, roleID, appName, appID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/catalog/apps/"+url.PathEscape(appName)+"/"+url.PathEscape(appID), "PUT", nil, nil)
+	return err
+}
+
+// RemoveRoleAppTarget removes an app from the targets of an APP_ADMIN role
+func (c *Client) RemoveRoleAppTarget(userID, roleID, appName string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/catalog/apps/"+url.PathEscape(appName), "DELETE", nil, nil)
+	return err
+}
+
+// RemoveRoleAppInstanceTarget removes an app instance from the targets of
+// an APP_ADMIN role
+func (c *Client) RemoveRoleAppInstanceTarget(userID, roleID, appName, appID string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/roles/"+url.PathEscape(roleID)+"/targets/catalog/apps/"+url.PathEscape(appName)+"/"+url.PathEscape(appID), "DELETE", nil, nil)
+	return err
+}
@@ -0,0 +1,116 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRoles(t *testing.T) {
+	var assigned []Role
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/users/00u1/roles":
+			w.Write([]byte(`[{"id":"ra1","label":"User Administrator","type":"USER_ADMIN","status":"ACTIVE","assignmentType":"GROUP"}]`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/groups/00g1/roles":
+			w.Write([]byte(`[{"id":"ra2","type":"HELP_DESK_ADMIN","status":"ACTIVE","assignmentType":"GROUP"}]`))
+		case r.Method == "POST":
+			var role Role
+			json.NewDecoder(r.Body).Decode(&role)
+			assigned = append(assigned, role)
+			role.ID = "ra3"
+			json.NewEncoder(w).Encode(role)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	roles, err := client.ListRoles("00u1")
+	if err != nil || len(*roles) != 1 || (*roles)[0].Type != UserAdmin || (*roles)[0].AssignmentType != "GROUP" {
+		t.Fatal("Expected the role of the user, got ", roles, err)
+	}
+	role, err := client.AssignRole("00u1", ReadOnlyAdmin)
+	if err != nil || role.ID != "ra3" {
+		t.Fatal("Expected the assigned role, got ", role, err)
+	}
+	if err := client.UnassignRole("00u1", "ra3"); err != nil {
+		t.Fatal(err)
+	}
+	roles, err = client.ListGroupRoles("00g1")
+	if err != nil || len(*roles) != 1 || (*roles)[0].Type != HelpDeskAdmin {
+		t.Fatal("Expected the role of the group, got ", roles, err)
+	}
+	if _, err := client.AssignGroupRole("00g1", GroupMembershipAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UnassignGroupRole("00g1", "ra3"); err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 2 || assigned[0].Type != ReadOnlyAdmin || assigned[1].Type != GroupMembershipAdmin {
+		t.Error("Expected the role types to be assigned, got ", assigned)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/users/00u1/roles",
+		"POST /api/v1/users/00u1/roles",
+		"DELETE /api/v1/users/00u1/roles/ra3",
+		"GET /api/v1/groups/00g1/roles",
+		"POST /api/v1/groups/00g1/roles",
+		"DELETE /api/v1/groups/00g1/roles/ra3",
+	)
+}
+
+func TestRoleTargets(t *testing.T) {
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/users/00u1/roles/ra1/targets/groups":
+			w.Write([]byte(`[{"id":"00g1","profile":{"name":"Engineering"}}]`))
+		case r.Method == "GET":
+			w.Write([]byte(`[{"name":"amazon_aws","displayName":"AWS Account Federation","status":"ACTIVE","category":"SOCIAL"},{"id":"0oa1","name":"salesforce","displayName":"Salesforce.com","status":"ACTIVE"}]`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	groups, err := client.RoleGroupTargets("00u1", "ra1")
+	if err != nil || len(*groups) != 1 || (*groups)[0].Profile.Name != "Engineering" {
+		t.Fatal("Expected the group target, got ", groups, err)
+	}
+	if err := client.AddRoleGroupTarget("00u1", "ra1", "00g2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveRoleGroupTarget("00u1", "ra1", "00g1"); err != nil {
+		t.Fatal(err)
+	}
+	apps, err := client.RoleAppTargets("00u1", "ra1")
+	if err != nil || len(*apps) != 2 || (*apps)[0].ID != "" || (*apps)[1].ID != "0oa1" {
+		t.Fatal("Expected an app and an app instance target, got ", apps, err)
+	}
+	if err := client.AddRoleAppTarget("00u1", "ra1", "amazon_aws"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.AddRoleAppInstanceTarget("00u1", "ra1", "salesforce", "0oa1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveRoleAppTarget("00u1", "ra1", "amazon_aws"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveRoleAppInstanceTarget("00u1", "ra1", "salesforce", "0oa1"); err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/users/00u1/roles/ra1/targets/groups",
+		"PUT /api/v1/users/00u1/roles/ra1/targets/groups/00g2",
+		"DELETE /api/v1/users/00u1/roles/ra1/targets/groups/00g1",
+		"GET /api/v1/users/00u1/roles/ra1/targets/catalog/apps",
+		"PUT /api/v1/users/00u1/roles/ra1/targets/catalog/apps/amazon_aws",
+		"PUT /api/v1/users/00u1/roles/ra1/targets/catalog/apps/salesforce/0oa1",
+		"DELETE /api/v1/users/00u1/roles/ra1/targets/catalog/apps/amazon_aws",
+		"DELETE /api/v1/users/00u1/roles/ra1/targets/catalog/apps/salesforce/0oa1",
+	)
+}
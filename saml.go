@@ -0,0 +1,164 @@
+package okta
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// awsRoleAttribute is the SAML attribute holding the AWS roles a user may
+// assume, as "role arn,provider arn" pairs
+const awsRoleAttribute = "https://aws.amazon.com/SAML/Attributes/Role"
+
+var (
+	htmlTag       = regexp.MustCompile(`(?is)<(form|input)\b([^>]*)>`)
+	htmlAttribute = regexp.MustCompile(`(?s)([a-zA-Z_:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// SAMLAssertion is the assertion Okta posts to an app when signing in
+type SAMLAssertion struct {
+	// SAMLResponse is the base64 encoded response as posted to the app
+	SAMLResponse string
+	// RelayState is posted along with the response if the app uses one
+	RelayState string
+	// Destination is the assertion consumer service the browser would post
+	// to, e.g. https://signin.aws.amazon.com/saml
+	Destination string
+}
+
+// AWSRole is a role of the AWS account federation app
+type AWSRole struct {
+	RoleARN      string
+	PrincipalARN string
+}
+
+// SAMLAssertion follows an app link such as AppLinks LinkURL with the
+// session cookie and extracts the assertion from the auto-submitting form
+// Okta answers with. Session must have been called before.
+func (c *Client) SAMLAssertion(linkURL string) (*SAMLAssertion, error) {
+	if c.sessionCookie() == nil {
+		return nil, errors.New("a session is required to sign in to an app")
+	}
+
+	req, err := http.NewRequest("GET", linkURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "text/html")
+	req.Header.Set("User-Agent", c.userAgent())
+	c.addSessionCookie(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &errorResponse{
+			HTTPCode:  resp.StatusCode,
+			RequestID: resp.Header.Get("X-Okta-Request-Id"),
+			Endpoint:  linkURL,
+			Class:     c.errorMap().Classify(resp.StatusCode, ""),
+		}
+	}
+
+	assertion := parseSAMLForm(string(body))
+	if assertion.SAMLResponse == "" {
+		return nil, errors.New("no SAMLResponse in the app sign in page, the session may have expired")
+	}
+	return assertion, nil
+}
+
+// parseSAMLForm reads the form posting the SAMLResponse
+func parseSAMLForm(page string) *SAMLAssertion {
+	assertion := &SAMLAssertion{}
+	for _, tag := range htmlTag.FindAllStringSubmatch(page, -1) {
+		attributes := map[string]string{}
+		for _, attribute := range htmlAttribute.FindAllStringSubmatch(tag[2], -1) {
+			attributes[strings.ToLower(attribute[1])] = html.UnescapeString(attribute[2] + attribute[3])
+		}
+
+		if strings.EqualFold(tag[1], "form") {
+			if assertion.SAMLResponse == "" {
+				assertion.Destination = attributes["action"]
+			}
+			continue
+		}
+		switch attributes["name"] {
+		case "SAMLResponse":
+			assertion.SAMLResponse = attributes["value"]
+		case "RelayState":
+			assertion.RelayState = attributes["value"]
+		}
+	}
+	return assertion
+}
+
+// Decode returns the XML of the SAML response
+func (a *SAMLAssertion) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(a.SAMLResponse)
+}
+
+// Attribute returns the values of an attribute of the assertion
+func (a *SAMLAssertion) Attribute(name string) ([]string, error) {
+	data, err := a.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Assertion struct {
+			Attributes []struct {
+				Name   string   `xml:"Name,attr"`
+				Values []string `xml:"AttributeValue"`
+			} `xml:"AttributeStatement>Attribute"`
+		} `xml:"Assertion"`
+	}
+	if err := xml.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, attribute := range response.Assertion.Attributes {
+		if attribute.Name == name {
+			for _, value := range attribute.Values {
+				values = append(values, strings.TrimSpace(value))
+			}
+		}
+	}
+	return values, nil
+}
+
+// AWSRoles returns the roles the assertion allows to assume with
+// sts:AssumeRoleWithSAML
+func (a *SAMLAssertion) AWSRoles() ([]AWSRole, error) {
+	values, err := a.Attribute(awsRoleAttribute)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []AWSRole
+	for _, value := range values {
+		parts := strings.Split(value, ",")
+		if len(parts) != 2 {
+			continue
+		}
+		// the order of the pair isn't fixed, the provider is the saml-provider
+		role, principal := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if strings.Contains(role, ":saml-provider/") {
+			role, principal = principal, role
+		}
+		roles = append(roles, AWSRole{RoleARN: role, PrincipalARN: principal})
+	}
+	return roles, nil
+}
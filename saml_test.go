@@ -0,0 +1,57 @@
+package okta
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+)
+
+const testSAMLResponse = `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol">
+<saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion">
+<saml2:AttributeStatement>
+<saml2:Attribute Name="https://aws.amazon.com/SAML/Attributes/Role">
+<saml2:AttributeValue>arn:aws:iam::123456789012:saml-provider/Okta,arn:aws:iam::123456789012:role/Admin</saml2:AttributeValue>
+<saml2:AttributeValue>arn:aws:iam::123456789012:role/ReadOnly,arn:aws:iam::123456789012:saml-provider/Okta</saml2:AttributeValue>
+</saml2:Attribute>
+</saml2:AttributeStatement>
+</saml2:Assertion>
+</samlp:Response>`
+
+func TestSAMLAssertion(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(testSAMLResponse))
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "session" {
+			w.Write([]byte(`<html><form action="/login"></form></html>`))
+			return
+		}
+		w.Write([]byte(`<html><body onload="document.forms[0].submit()">
+<form id="appForm" action="https&#x3a;&#x2f;&#x2f;signin.aws.amazon.com&#x2f;saml" method="POST">
+<input name="SAMLResponse" type="hidden" value="` + encoded + `"/>
+<input name="RelayState" type="hidden" value=""/>
+</form></body></html>`))
+	}))
+	defer done()
+
+	if _, err := client.SAMLAssertion(client.BaseURL + "/home/amazon_aws/0oa1/272"); err == nil {
+		t.Error("Expected an error without a session")
+	}
+
+	client.SessionCookie = &http.Cookie{Name: "sid", Value: "session"}
+	assertion, err := client.SAMLAssertion(client.BaseURL + "/home/amazon_aws/0oa1/272")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assertion.SAMLResponse != encoded || assertion.Destination != "https://signin.aws.amazon.com/saml" {
+		t.Error("Expected the response and destination of the form, got ", assertion.Destination)
+	}
+
+	roles, err := assertion.AWSRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 2 ||
+		roles[0].RoleARN != "arn:aws:iam::123456789012:role/Admin" ||
+		roles[1].PrincipalARN != "arn:aws:iam::123456789012:saml-provider/Okta" {
+		t.Error("Expected both roles, got ", roles)
+	}
+}
@@ -0,0 +1,132 @@
+package okta
+
+import (
+	"net/url"
+	"time"
+)
+
+type UserSchema struct {
+	ID          string     `json:"id,omitempty"`
+	Schema      string     `json:"$schema,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	Definitions struct {
+		Base   *UserSchemaDefinition `json:"base,omitempty"`
+		Custom *UserSchemaDefinition `json:"custom,omitempty"`
+	} `json:"definitions"`
+}
+
+type UserSchemaDefinition struct {
+	ID         string                         `json:"id"`
+	Type       string                         `json:"type"`
+	Properties map[string]*UserSchemaProperty `json:"properties"`
+	Required   []string                       `json:"required,omitempty"`
+}
+
+// UserSchemaProperty is a profile attribute, pointers are used for
+// constraints so that unset ones aren't sent to Okta
+type UserSchemaProperty struct {
+	Title             string        `json:"title"`
+	Description       string        `json:"description,omitempty"`
+	Type              string        `json:"type"`
+	Format            string        `json:"format,omitempty"`
+	Pattern           string        `json:"pattern,omitempty"`
+	Required          bool          `json:"required,omitempty"`
+	Mutability        string        `json:"mutability,omitempty"`
+	Scope             string        `json:"scope,omitempty"`
+	Unique            string        `json:"unique,omitempty"`
+	MinLength         *int          `json:"minLength,omitempty"`
+	MaxLength         *int          `json:"maxLength,omitempty"`
+	Minimum           *float64      `json:"minimum,omitempty"`
+	Maximum           *float64      `json:"maximum,omitempty"`
+	Enum              []interface{} `json:"enum,omitempty"`
+	ExternalName      string        `json:"externalName,omitempty"`
+	ExternalNamespace string        `json:"externalNamespace,omitempty"`
+	OneOf             []struct {
+		Const interface{} `json:"const"`
+		Title string      `json:"title"`
+	} `json:"oneOf,omitempty"`
+	Items *struct {
+		Type string        `json:"type"`
+		Enum []interface{} `json:"enum,omitempty"`
+	} `json:"items,omitempty"`
+	Master *struct {
+		Type string `json:"type"`
+	} `json:"master,omitempty"`
+	Permissions []struct {
+		Principal string `json:"principal"`
+		Action    string `json:"action"`
+	} `json:"permissions,omitempty"`
+}
+
+// UserSchema returns the default user schema of the org
+func (c *Client) UserSchema() (*UserSchema, error) {
+	var response = &UserSchema{}
+	err, _ := c.call("meta/schemas/user/default", "GET", nil, response)
+	return response, err
+}
+
+// UpdateUserSchema applies a partial update to the default user schema,
+// only custom properties can be added, changed or removed (set to nil)
+func (c *Client) UpdateUserSchema(schema *UserSchema) (*UserSchema, error) {
+	var response = &UserSchema{}
+	err, _ := c.call("meta/schemas/user/default", "POST", schema, response)
+	return response, err
+}
+
+// AddUserSchemaProperty adds or changes a custom attribute of the default
+// user schema
+func (c *Client) AddUserSchemaProperty(name string, property *UserSchemaProperty) (*UserSchema, error) {
+	return c.UpdateUserSchema(customSchemaUpdate(name, property))
+}
+
+// RemoveUserSchemaProperty removes a custom attribute from the default user
+// schema, the attribute is dropped from every user profile
+func (c *Client) RemoveUserSchemaProperty(name string) (*UserSchema, error) {
+	return c.UpdateUserSchema(customSchemaUpdate(name, nil))
+}
+
+// AppUserSchema returns the user schema of an app instance
+func (c *Client) AppUserSchema(appID string) (*UserSchema, error) {
+	var response = &UserSchema{}
+	err, _ := c.call("meta/schemas/apps/"+url.PathEscape(appID)+"/default", "GET", nil, response)
+	return response, err
+}
+
+// UpdateAppUserSchema applies a partial update to the user schema of an app
+// instance, see UpdateUserSchema
+func (c *Client) UpdateAppUserSchema(appID string, schema *UserSchema) (*UserSchema, error) {
+	var response = &UserSchema{}
+	err, _ := c.call("meta/schemas/apps/"+url.PathEscape(appID)+"/default", "POST", schema, response)
+	return response, err
+}
+
+// AddAppUserSchemaProperty adds or changes a custom attribute of the user
+// schema of an app instance
+func (c *Client) AddAppUserSchemaProperty(appID, name string, property *UserSchemaProperty) (*UserSchema, error) {
+	return c.UpdateAppUserSchema(appID, customSchemaUpdate(name, property))
+}
+
+// RemoveAppUserSchemaProperty removes a custom attribute from the user
+// schema of an app instance
+func (c *Client) RemoveAppUserSchemaProperty(appID, name string) (*UserSchema, error) {
+	return c.UpdateAppUserSchema(appID, customSchemaUpdate(name, nil))
+}
+
+// customSchemaUpdate builds a partial schema update touching a single
+// custom property, a nil property is sent as null which removes it
+func customSchemaUpdate(name string, property *UserSchemaProperty) *UserSchema {
+	schema := &UserSchema{}
+	schema.Definitions.Custom = &UserSchemaDefinition{
+		ID:   "#custom",
+		Type: "object",
+		Properties: map[string]*UserSchemaProperty{
+			name: property,
+		},
+	}
+	return schema
+}
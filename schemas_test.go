@@ -0,0 +1,83 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestUserSchema(t *testing.T) {
+	var updates []string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			body, _ := ioutil.ReadAll(r.Body)
+			updates = append(updates, string(body))
+		}
+		w.Write([]byte(`{"id":"https://example.okta.com/meta/schemas/user/default","$schema":"http://json-schema.org/draft-04/schema#",
+			"name":"user","type":"object","definitions":{
+				"base":{"id":"#base","type":"object","properties":{"login":{"title":"Username","type":"string","required":true,"minLength":5,"maxLength":100,"mutability":"READ_WRITE","scope":"NONE"}},"required":["login"]},
+				"custom":{"id":"#custom","type":"object","properties":{
+					"badge":{"title":"Badge","type":"string","unique":"UNIQUE_VALIDATED","master":{"type":"PROFILE_MASTER"},"permissions":[{"principal":"SELF","action":"READ_ONLY"}]},
+					"tier":{"title":"Tier","type":"string","enum":["gold","silver"],"oneOf":[{"const":"gold","title":"Gold"},{"const":"silver","title":"Silver"}]},
+					"roles":{"title":"Roles","type":"array","items":{"type":"string"}}}}}}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	schema, err := client.UserSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := schema.Definitions.Base.Properties["login"]
+	if login == nil || !login.Required || *login.MinLength != 5 || *login.MaxLength != 100 || login.Maximum != nil {
+		t.Errorf("Unexpected base property %+v", login)
+	}
+	custom := schema.Definitions.Custom.Properties
+	if custom["badge"].Unique != "UNIQUE_VALIDATED" || custom["badge"].Master.Type != "PROFILE_MASTER" || custom["badge"].Permissions[0].Action != "READ_ONLY" {
+		t.Errorf("Unexpected badge %+v", custom["badge"])
+	}
+	if len(custom["tier"].OneOf) != 2 || custom["tier"].OneOf[1].Title != "Silver" || custom["roles"].Items.Type != "string" {
+		t.Errorf("Unexpected tier and roles %+v %+v", custom["tier"], custom["roles"])
+	}
+
+	maxLength := 20
+	if _, err := client.AddUserSchemaProperty("nickname", &UserSchemaProperty{Title: "Nickname", Type: "string", MaxLength: &maxLength}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RemoveUserSchemaProperty("badge"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AppUserSchema("0oa1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AddAppUserSchemaProperty("0oa1", "costCenter", &UserSchemaProperty{Title: "Cost center", Type: "string", Scope: "NONE"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RemoveAppUserSchemaProperty("0oa1", "costCenter"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`{"definitions":{"custom":{"id":"#custom","type":"object","properties":{"nickname":{"title":"Nickname","type":"string","maxLength":20}}}}}`,
+		`{"definitions":{"custom":{"id":"#custom","type":"object","properties":{"badge":null}}}}`,
+		`{"definitions":{"custom":{"id":"#custom","type":"object","properties":{"costCenter":{"title":"Cost center","type":"string","scope":"NONE"}}}}}`,
+		`{"definitions":{"custom":{"id":"#custom","type":"object","properties":{"costCenter":null}}}}`,
+	}
+	if len(updates) != len(expected) {
+		t.Fatal("Expected updates ", expected, ", got ", updates)
+	}
+	for i := range expected {
+		if updates[i] != expected[i] {
+			t.Errorf("Expected update %s, got %s", expected[i], updates[i])
+		}
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/meta/schemas/user/default",
+		"POST /api/v1/meta/schemas/user/default",
+		"POST /api/v1/meta/schemas/user/default",
+		"GET /api/v1/meta/schemas/apps/0oa1/default",
+		"POST /api/v1/meta/schemas/apps/0oa1/default",
+		"POST /api/v1/meta/schemas/apps/0oa1/default",
+	)
+}
@@ -0,0 +1,121 @@
+package okta
+
+import (
+	"context"
+)
+
+// UserService is the part of Client managing users, for mocking Client in
+// tests of code depending on it
+type UserService interface {
+	User(userID string) (*User, error)
+	UserContext(ctx context.Context, userID string) (*User, error)
+	UsersBatch(ids []string, concurrency int) (*[]User, error)
+	ListUsers(opts *ListUsersOptions) (*[]User, error)
+	Me() (*User, error)
+	CreateUser(user *CreateUserRequest, activate bool) (*User, error)
+	ImportUser(profile interface{}, hash *PasswordHash, activate bool) (*User, error)
+	ImportUserWithHook(profile interface{}, activate bool) (*User, error)
+	UpdateMe(profile interface{}) (*User, error)
+	UpdateUserProfile(userID string, profile interface{}) (*User, error)
+	UpdateUserProfilePartial(userID string, profile interface{}) (*User, error)
+	UpdateUserProfileIfUnmodified(user *User, profile interface{}) (*User, error)
+	ResetPassword(userID string, sendEmail bool) error
+	ResetPasswordLink(userID string) (*ResetPasswordLink, error)
+	ActivateUser(userID string, sendEmail bool) (*ActivationLink, error)
+	ReactivateUser(userID string, sendEmail bool) (*ActivationLink, error)
+	CreateUserWithActivationLink(user *CreateUserRequest) (*User, *ActivationLink, error)
+	DeactivateUser(userID string) error
+	SuspendUser(userID string) error
+	UnsuspendUser(userID string) error
+	UnlockUser(userID string) error
+	ExpirePassword(userID string) (*User, error)
+	ExpirePasswordWithTempPassword(userID string) (*TempPassword, error)
+	ResetFactors(userID string) error
+	DeleteUser(userID string) error
+	ListGrants(userID string) (*[]Grant, error)
+	RevokeGrant(userID, grantID string) error
+	RevokeGrants(userID string) error
+	ListUserClients(userID string) (*[]OAuthClient, error)
+	ListUserOAuthTokens(userID, clientID string) (*[]OAuthToken, error)
+	RevokeUserTokensForApp(userID, clientID string) error
+	RevokeUserAccess(userID string) error
+}
+
+// GroupService is the part of Client managing groups and their members
+type GroupService interface {
+	Groups(userID string) (*[]Group, error)
+	GroupsContext(ctx context.Context, userID string) (*[]Group, error)
+	ListGroups(opts *ListGroupsOptions) (*[]Group, error)
+	GroupByName(name string) (*Group, error)
+	Group(groupID string) (*Group, error)
+	CreateGroup(profile GroupProfile) (*Group, error)
+	UpdateGroup(groupID string, profile GroupProfile) (*Group, error)
+	DeleteGroup(groupID string) error
+	GroupMembers(groupID string) (*[]User, error)
+	AddUserToGroup(groupID, userID string) error
+	RemoveUserFromGroup(groupID, userID string) error
+	ListGroupOwners(groupID string) (*[]GroupOwner, error)
+	AddGroupOwner(groupID, ownerID, ownerType string) (*GroupOwner, error)
+	RemoveGroupOwner(groupID, ownerID string) error
+	ListUsersByGroupRule(expression string) (*[]User, error)
+	GroupRules() (*[]GroupRule, error)
+	CreateGroupRule(rule *GroupRule) (*GroupRule, error)
+	UpdateGroupRule(ruleID string, rule *GroupRule) (*GroupRule, error)
+	ActivateGroupRule(ruleID string) error
+	DeactivateGroupRule(ruleID string) error
+	DeleteGroupRule(ruleID string) error
+}
+
+// AuthnService is the part of Client signing users in
+type AuthnService interface {
+	Authenticate(username, password string, opts ...AuthnOption) (*AuthnResponse, error)
+	AuthenticateContext(ctx context.Context, username, password string, opts ...AuthnOption) (*AuthnResponse, error)
+	Session(sessionToken string) (*SessionResponse, error)
+	SessionContext(ctx context.Context, sessionToken string) (*SessionResponse, error)
+	RefreshSession(sessionID string) (*SessionResponse, error)
+	RefreshCurrentSession() (*SessionResponse, error)
+	CloseSession(sessionID string) error
+	CloseCurrentSession() error
+	SessionCookieRedirectURL(sessionToken, redirectURL string) string
+	ExchangeSessionToken(sessionToken, redirectURL string) (*SessionRedirect, error)
+}
+
+// AppService is the part of Client dealing with app instances and the apps
+// of users
+type AppService interface {
+	ListApps() (*[]App, error)
+	CreateApp(app *AppRequest, activate bool) (*App, error)
+	UpdateApp(appID string, app *AppRequest) (*App, error)
+	ActivateApp(appID string) error
+	DeactivateApp(appID string) error
+	DeleteApp(appID string) error
+	AppLinks(userID string, appName string) (*AppLinks, error)
+	ListAssignedApplicationsForUser(userID string) (*[]App, error)
+	AppUser(appID, userID string) (*AppUser, error)
+	AppUsers(appID string) (*[]AppUser, error)
+	AppUsersContext(ctx context.Context, appID string) (*[]AppUser, error)
+	SetAppUserCredentials(appID, userID, userName, password string) (*AppUser, error)
+	SAMLAssertion(linkURL string) (*SAMLAssertion, error)
+	ListClientSecrets(appID string) (*[]ClientSecret, error)
+	CreateClientSecret(appID string) (*ClientSecret, error)
+	ActivateClientSecret(appID, secretID string) (*ClientSecret, error)
+	DeactivateClientSecret(appID, secretID string) (*ClientSecret, error)
+	DeleteClientSecret(appID, secretID string) error
+	RotateClientSecret(appID string) (*ClientSecret, error)
+	ListAppKeys(appID string) (*[]AppKey, error)
+	AppKey(appID, keyID string) (*AppKey, error)
+	GenerateAppKey(appID string, validityYears int) (*AppKey, error)
+	CloneAppKey(appID, keyID, targetAppID string) (*AppKey, error)
+	GenerateCSR(appID string, request *CSRRequest) (*CSR, error)
+	ListCSRs(appID string) (*[]CSR, error)
+	RevokeCSR(appID, csrID string) error
+	PublishCert(appID, csrID string, cert []byte) (*AppKey, error)
+	SetAppSigningKey(appID, keyID string) (*App, error)
+}
+
+var (
+	_ UserService  = (*Client)(nil)
+	_ GroupService = (*Client)(nil)
+	_ AuthnService = (*Client)(nil)
+	_ AppService   = (*Client)(nil)
+)
@@ -0,0 +1,184 @@
+package okta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRefreshBefore   = 5 * time.Minute
+	defaultSignInAttempts  = 3
+	sessionRefreshInterval = 30 * time.Second
+)
+
+// SessionManager keeps a session of a user alive for long running agents.
+// It signs in and creates the session on first use, refreshes it before it
+// expires and signs in again once it lapsed, so the session cookie of
+// Client stays valid.
+type SessionManager struct {
+	Client   *Client
+	Username string
+	Password string
+
+	// MFA completes a transaction that didn't succeed with the password
+	// alone, e.g. by verifying a push, and returns the SUCCESS response.
+	// Signing in fails when MFA is required and it is nil.
+	MFA func(ctx context.Context, resp *AuthnResponse) (*AuthnResponse, error)
+
+	// RefreshBefore is how long before it expires the session is
+	// refreshed, 5 minutes when zero
+	RefreshBefore time.Duration
+
+	// SignInAttempts is how often signing in is tried when creating the
+	// session fails, 3 when zero. Session tokens are single use, every
+	// attempt signs in again rather than resending a token Okta may have
+	// consumed.
+	SignInAttempts int
+
+	// OnSession is called with every session created or refreshed
+	OnSession func(*SessionResponse)
+
+	mu      sync.Mutex
+	session *SessionResponse
+}
+
+// NewSessionManager returns a manager signing in to c with a username and
+// password
+func NewSessionManager(c *Client, username, password string) *SessionManager {
+	return &SessionManager{Client: c, Username: username, Password: password}
+}
+
+func (m *SessionManager) refreshBefore() time.Duration {
+	if m.RefreshBefore == 0 {
+		return defaultRefreshBefore
+	}
+	return m.RefreshBefore
+}
+
+// Session returns a valid session, signing in or refreshing as needed. A
+// session still valid is returned when refreshing it fails for a reason
+// other than the session being gone.
+func (m *SessionManager) Session(ctx context.Context) (*SessionResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session != nil && time.Until(m.session.expiry()) > m.refreshBefore() {
+		return m.session, nil
+	}
+	if m.session != nil && time.Now().Before(m.session.expiry()) {
+		session, err := m.Client.refreshCurrentSession(ctx)
+		switch kind := ClassOf(err).Kind; {
+		case err == nil:
+			m.setSession(session)
+			return session, nil
+		case kind != ErrorNotFound && kind != ErrorUnauthorized:
+			if time.Now().Before(m.session.expiry()) {
+				return m.session, nil
+			}
+			return nil, err
+		}
+		// the session was closed or expired early, sign in again
+	}
+
+	m.session = nil
+	session, err := m.signIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.setSession(session)
+	return session, nil
+}
+
+func (m *SessionManager) setSession(session *SessionResponse) {
+	m.session = session
+	if m.OnSession != nil {
+		m.OnSession(session)
+	}
+}
+
+func (m *SessionManager) signIn(ctx context.Context) (*SessionResponse, error) {
+	attempts := m.SignInAttempts
+	if attempts == 0 {
+		attempts = defaultSignInAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var resp *AuthnResponse
+		resp, err = m.Client.AuthenticateContext(ctx, m.Username, m.Password)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Status != AuthnSuccess {
+			if m.MFA == nil {
+				return nil, fmt.Errorf("okta: signing in requires %s", resp.Status)
+			}
+			if resp, err = m.MFA(ctx, resp); err != nil {
+				return nil, err
+			}
+			if resp == nil || resp.Status != AuthnSuccess {
+				return nil, errors.New("okta: MFA didn't complete the sign in")
+			}
+		}
+
+		var session *SessionResponse
+		session, err = m.Client.SessionContext(ctx, resp.SessionToken)
+		if err == nil {
+			return session, nil
+		}
+		// a token consumed by a request that failed on the way back is
+		// rejected as invalid, only a new sign in helps
+		class := ClassOf(err)
+		if ctx.Err() != nil || (class.Kind != ErrorUnknown && class.Kind != ErrorUnauthorized && !class.Retryable) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// Run keeps the session alive until ctx is done, refreshing it
+// RefreshBefore its expiry. It returns ctx.Err() or the error of signing
+// in when the session lapsed.
+func (m *SessionManager) Run(ctx context.Context) error {
+	for {
+		session, err := m.Session(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		wait := time.Until(session.expiry()) - m.refreshBefore()
+		if wait < sessionRefreshInterval {
+			// refreshing failed or the session lifetime is short
+			wait = sessionRefreshInterval
+			if until := time.Until(session.expiry()); until < wait && until > 0 {
+				wait = until
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Close signs out of the session, Run should be stopped first
+func (m *SessionManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.session == nil {
+		return nil
+	}
+	m.session = nil
+	return m.Client.CloseCurrentSession()
+}
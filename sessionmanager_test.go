@@ -0,0 +1,79 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSessionManager(t *testing.T) {
+	var signIns, creates, refreshes int32
+	expiresAt := time.Now().Add(2 * time.Minute)
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/authn":
+			n := atomic.AddInt32(&signIns, 1)
+			fmt.Fprintf(w, `{"status":"MFA_REQUIRED","stateToken":"state%d"}`, n)
+		case "/api/v1/sessions":
+			if atomic.AddInt32(&creates, 1) == 1 {
+				// the token was consumed but the response lost
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			fmt.Fprintf(w, `{"id":"102session","expiresAt":%q}`, expiresAt.Format(time.RFC3339))
+		case "/api/v1/sessions/me/lifecycle/refresh":
+			if cookie, _ := r.Cookie("sid"); cookie == nil || cookie.Value != "102session" {
+				t.Error("Expected the session cookie, got ", r.Cookies())
+			}
+			if atomic.AddInt32(&refreshes, 1) == 1 {
+				fmt.Fprintf(w, `{"id":"102session","expiresAt":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007"}`))
+		}
+	}))
+	defer done()
+
+	var verified []string
+	manager := NewSessionManager(client, "agent@example.com", "password")
+	manager.MFA = func(ctx context.Context, resp *AuthnResponse) (*AuthnResponse, error) {
+		verified = append(verified, resp.StateToken)
+		return &AuthnResponse{Status: AuthnSuccess, SessionToken: "token"}, nil
+	}
+
+	session, err := manager.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.ID != "102session" || signIns != 2 || len(verified) != 2 {
+		t.Error("Expected a second sign in after the failed session, got ", signIns, verified)
+	}
+
+	// within RefreshBefore of expiresAt
+	session, err = manager.Session(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 1 || time.Until(session.expiry()) < 50*time.Minute {
+		t.Error("Expected the session to be refreshed, got ", refreshes, session.ExpiresAt)
+	}
+
+	// lapsed sessions are replaced by signing in again
+	manager.RefreshBefore = 2 * time.Hour
+	if _, err := manager.Session(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 2 || signIns != 3 {
+		t.Error("Expected a new sign in after the session was gone, got ", refreshes, signIns)
+	}
+
+	manager.MFA = nil
+	manager.session = nil
+	if _, err := manager.Session(context.Background()); err == nil {
+		t.Error("Expected an error when MFA is required without a callback")
+	}
+}
@@ -1,6 +1,9 @@
 package okta
 
 import (
+	"context"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -8,15 +11,25 @@ type SessionRequest struct {
 	SessionToken string `json:"sessionToken"`
 }
 
+// SessionStatus is the state of a session
+type SessionStatus string
+
+// https://developer.okta.com/docs/reference/api/sessions/#session-status
+const (
+	SessionActive      SessionStatus = "ACTIVE"
+	SessionMFARequired SessionStatus = "MFA_REQUIRED"
+	SessionMFAEnroll   SessionStatus = "MFA_ENROLL"
+)
+
 type SessionResponse struct {
-	ID                       string      `json:"id"`
-	Login                    string      `json:"login"`
-	UserID                   string      `json:"userId"`
-	ExpiresAt                time.Time   `json:"expiresAt"`
-	Status                   string      `json:"status"`
-	LastPasswordVerification time.Time   `json:"lastPasswordVerification"`
-	LastFactorVerification   interface{} `json:"lastFactorVerification"`
-	Amr                      []string    `json:"amr"`
+	ID                       string        `json:"id"`
+	Login                    string        `json:"login"`
+	UserID                   string        `json:"userId"`
+	ExpiresAt                *time.Time    `json:"expiresAt"`
+	Status                   SessionStatus `json:"status"`
+	LastPasswordVerification *time.Time    `json:"lastPasswordVerification"`
+	LastFactorVerification   *time.Time    `json:"lastFactorVerification"`
+	Amr                      []string      `json:"amr"`
 	Idp                      struct {
 		ID   string `json:"id"`
 		Type string `json:"type"`
@@ -44,3 +57,133 @@ type SessionResponse struct {
 		} `json:"user"`
 	} `json:"_links"`
 }
+
+// expiry is when the session expires, the zero time if Okta didn't say so
+// the session counts as expired
+func (s *SessionResponse) expiry() time.Time {
+	if s.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return *s.ExpiresAt
+}
+
+// SessionCookieRedirectURL returns the URL that turns a session token into
+// an Okta session cookie in the browser visiting it and then redirects the
+// browser to redirectURL, which must be a trusted origin of the org
+// https://developer.okta.com/docs/guides/session-cookie/overview/
+func (c *Client) SessionCookieRedirectURL(sessionToken, redirectURL string) string {
+	v := &url.Values{}
+	v.Add("token", sessionToken)
+	v.Add("redirectUrl", redirectURL)
+	return c.baseURL() + "/login/sessionCookieRedirect?" + v.Encode()
+}
+
+// SessionRedirect is the response to a sessionCookieRedirect
+type SessionRedirect struct {
+	// Location is where Okta redirects to, redirectURL on success
+	Location string
+	// Cookies are the cookies Okta set, including the sid session cookie
+	Cookies []*http.Cookie
+}
+
+// ExchangeSessionToken exchanges a session token for an Okta session
+// without a browser, the cookies are stored in the jar of the client and
+// returned so they can be handed on, e.g. to a reverse proxied browser
+func (c *Client) ExchangeSessionToken(sessionToken, redirectURL string) (*SessionRedirect, error) {
+	req, err := http.NewRequest("GET", c.SessionCookieRedirectURL(sessionToken, redirectURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent())
+
+	client := *c.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// the URL carries the session token
+		return nil, redactError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return nil, &errorResponse{
+			HTTPCode:  resp.StatusCode,
+			RequestID: resp.Header.Get("X-Okta-Request-Id"),
+			Endpoint:  req.URL.Path,
+			Class:     c.errorMap().Classify(resp.StatusCode, ""),
+		}
+	}
+
+	redirect := &SessionRedirect{
+		Location: resp.Header.Get("Location"),
+		Cookies:  resp.Cookies(),
+	}
+	for _, cookie := range redirect.Cookies {
+		if cookie.Name == "sid" && cookie.Value != "" {
+			c.sessionMu.Lock()
+			c.SessionCookie = cookie
+			if client.Jar != nil {
+				// the jar already holds the cookie
+				c.jarCookie = cookie
+			}
+			c.sessionMu.Unlock()
+		}
+	}
+	return redirect, nil
+}
+
+// RefreshSession extends the session with the given id to the session
+// lifetime of the org, it needs an API token
+func (c *Client) RefreshSession(sessionID string) (*SessionResponse, error) {
+	var response = &SessionResponse{}
+	err, _ := c.call("sessions/"+url.PathEscape(sessionID)+"/lifecycle/refresh", "POST", nil, response)
+	return response, err
+}
+
+// RefreshCurrentSession extends the session of the session cookie of the
+// client, see Session
+func (c *Client) RefreshCurrentSession() (*SessionResponse, error) {
+	return c.refreshCurrentSession(context.Background())
+}
+
+func (c *Client) refreshCurrentSession(ctx context.Context) (*SessionResponse, error) {
+	var response = &SessionResponse{}
+	err, _ := c.callContext(ctx, "sessions/me/lifecycle/refresh", "POST", nil, response)
+	return response, err
+}
+
+// CloseSession ends the session with the given id, it needs an API token
+func (c *Client) CloseSession(sessionID string) error {
+	err, _ := c.call("sessions/"+url.PathEscape(sessionID), "DELETE", nil, nil)
+	return err
+}
+
+// CloseCurrentSession signs out of the session of the session cookie of
+// the client and forgets the cookie, also when Okta no longer knows the
+// session
+func (c *Client) CloseCurrentSession() error {
+	err, _ := c.call("sessions/me", "DELETE", nil, nil)
+	if err == nil || ClassOf(err).Kind == ErrorNotFound {
+		c.clearSessionCookie()
+	}
+	return err
+}
+
+// clearSessionCookie removes SessionCookie and the sid of the jar
+func (c *Client) clearSessionCookie() {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	if u, err := url.Parse(c.baseURL()); err == nil && c.client.Jar != nil {
+		expired := &http.Cookie{Name: "sid", Path: "/", MaxAge: -1}
+		if c.SessionCookie != nil {
+			expired.Domain, expired.Path = c.SessionCookie.Domain, c.SessionCookie.Path
+		}
+		c.client.Jar.SetCookies(u, []*http.Cookie{expired})
+	}
+	c.SessionCookie = nil
+	c.jarCookie = nil
+}
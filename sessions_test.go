@@ -0,0 +1,98 @@
+package okta
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestExchangeSessionToken(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/login/sessionCookieRedirect" || r.URL.Query().Get("token") != "token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "102session", Path: "/"})
+		http.Redirect(w, r, r.URL.Query().Get("redirectUrl"), http.StatusFound)
+	}))
+	defer done()
+
+	redirectURL := "https://app.example.com/login?state=1"
+	u, err := url.Parse(client.SessionCookieRedirectURL("token", redirectURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != "/login/sessionCookieRedirect" || u.Query().Get("redirectUrl") != redirectURL {
+		t.Error("Unexpected redirect URL ", u)
+	}
+
+	redirect, err := client.ExchangeSessionToken("token", redirectURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redirect.Location != redirectURL || client.SessionCookie == nil || client.SessionCookie.Value != "102session" {
+		t.Error("Expected the session and the redirect, got ", redirect.Location, client.SessionCookie)
+	}
+
+	if _, err := client.ExchangeSessionToken("invalid", redirectURL); ClassOf(err).Kind != ErrorNotFound {
+		t.Error("Expected an error for a failed exchange, got ", err)
+	}
+}
+
+func TestCloseCurrentSession(t *testing.T) {
+	var closed []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/sessions":
+			w.Write([]byte(`{"id":"102session"}`))
+		case r.URL.Path == "/api/v1/sessions/me" && r.Method == "DELETE":
+			cookie, err := r.Cookie("sid")
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errorCode":"E0000007"}`))
+				return
+			}
+			closed = append(closed, cookie.Value)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer done()
+
+	if _, err := client.Session("token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CloseCurrentSession(); err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 1 || closed[0] != "102session" || client.SessionCookie != nil {
+		t.Error("Expected the session to be closed and forgotten, got ", closed, client.SessionCookie)
+	}
+
+	// the jar no longer sends the cookie either
+	if err := client.CloseCurrentSession(); ClassOf(err).Kind != ErrorNotFound {
+		t.Error("Expected no session to close, got ", err)
+	}
+}
+
+func TestSessionTimes(t *testing.T) {
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"102session","expiresAt":"2026-03-01T18:30:00.000Z","lastPasswordVerification":"2026-03-01T16:30:00Z","lastFactorVerification":null}`))
+	}))
+	defer done()
+
+	session, err := client.RefreshSession("102session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires := time.Date(2026, 3, 1, 18, 30, 0, 0, time.UTC)
+	if session.ExpiresAt == nil || !session.ExpiresAt.Equal(expires) || !session.expiry().Equal(expires) {
+		t.Error("Expected expiresAt to be parsed, got ", session.ExpiresAt)
+	}
+	if session.LastPasswordVerification == nil || session.LastFactorVerification != nil {
+		t.Error("Expected null to leave the time nil, got ", session.LastPasswordVerification, session.LastFactorVerification)
+	}
+	if !(&SessionResponse{}).expiry().IsZero() {
+		t.Error("Expected a session without expiresAt to count as expired")
+	}
+}
@@ -0,0 +1,55 @@
+package okta
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotStored is returned by a Store for keys it has no value for
+var ErrNotStored = errors.New("okta: no value stored for key")
+
+// Store persists small pieces of client state between runs, such as
+// session and refresh tokens of a CLI
+type Store interface {
+	// Get returns the value of key or ErrNotStored
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	Delete(key string) error
+}
+
+// MemoryStore is a Store keeping values in memory, it is safe for
+// concurrent use
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string][]byte{}}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrNotStored
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (s *MemoryStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
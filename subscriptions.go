@@ -0,0 +1,107 @@
+package okta
+
+import (
+	"net/url"
+)
+
+// Admin notification types
+const (
+	NotificationConnectorAgent           = "CONNECTOR_AGENT"
+	NotificationUserLockedOut            = "USER_LOCKED_OUT"
+	NotificationAppImport                = "APP_IMPORT"
+	NotificationLDAPAgent                = "LDAP_AGENT"
+	NotificationADAgent                  = "AD_AGENT"
+	NotificationOktaAnnouncement         = "OKTA_ANNOUNCEMENT"
+	NotificationOktaIssue                = "OKTA_ISSUE"
+	NotificationOktaUpdate               = "OKTA_UPDATE"
+	NotificationIWAAgent                 = "IWA_AGENT"
+	NotificationUserDeprovision          = "USER_DEPROVISION"
+	NotificationReportSuspiciousActivity = "REPORT_SUSPICIOUS_ACTIVITY"
+	NotificationRateLimit                = "RATELIMIT_NOTIFICATION"
+)
+
+// Subscription tells whether the admins of a role or an admin get the
+// emails of a notification type
+type Subscription struct {
+	NotificationType string   `json:"notificationType"`
+	Channels         []string `json:"channels"`
+	// Status is subscribed or unsubscribed
+	Status string `json:"status"`
+}
+
+// ListRoleSubscriptions returns the notification subscriptions of an
+// administrator role, roleType is one of the standard role types such as
+// SuperAdmin or the id of a custom role
+func (c *Client) ListRoleSubscriptions(roleType string) (*[]Subscription, error) {
+	var response = &[]Subscription{}
+	err, _ := c.call("roles/"+url.PathEscape(roleType)+"/subscriptions", "GET", nil, response)
+	return response, err
+}
+
+// RoleSubscription returns the subscription of a role to a notification
+// type
+func (c *Client) RoleSubscription(roleType, notificationType string) (*Subscription, error) {
+	var response = &Subscription{}
+	err, _ := c.call("roles/"+url.PathEscape(roleType)+"/subscriptions/"+url.PathEscape(notificationType), "GET", nil, response)
+	return response, err
+}
+
+// SubscribeRole subscribes the admins of a role to a notification type
+func (c *Client) SubscribeRole(roleType, notificationType string) error {
+	err, _ := c.call("roles/"+url.PathEscape(roleType)+"/subscriptions/"+url.PathEscape(notificationType)+"/subscribe", "POST", nil, nil)
+	return err
+}
+
+// UnsubscribeRole unsubscribes the admins of a role from a notification
+// type
+func (c *Client) UnsubscribeRole(roleType, notificationType string) error {
+	err, _ := c.call("roles/"+url.PathEscape(roleType)+"/subscriptions/"+url.PathEscape(notificationType)+"/unsubscribe", "POST", nil, nil)
+	return err
+}
+
+// ListUserSubscriptions returns the notification subscriptions of an admin
+func (c *Client) ListUserSubscriptions(userID string) (*[]Subscription, error) {
+	var response = &[]Subscription{}
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/subscriptions", "GET", nil, response)
+	return response, err
+}
+
+// SubscribeUser subscribes an admin to a notification type
+func (c *Client) SubscribeUser(userID, notificationType string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/subscriptions/"+url.PathEscape(notificationType)+"/subscribe", "POST", nil, nil)
+	return err
+}
+
+// UnsubscribeUser unsubscribes an admin from a notification type
+func (c *Client) UnsubscribeUser(userID, notificationType string) error {
+	err, _ := c.call("users/"+url.PathEscape(userID)+"/subscriptions/"+url.PathEscape(notificationType)+"/unsubscribe", "POST", nil, nil)
+	return err
+}
+
+// SetRoleSubscriptions subscribes the admins of a role to exactly the given
+// notification types and unsubscribes them from the others, so
+// subscriptions can be kept the same across orgs
+func (c *Client) SetRoleSubscriptions(roleType string, notificationTypes ...string) error {
+	wanted := map[string]bool{}
+	for _, notificationType := range notificationTypes {
+		wanted[notificationType] = true
+	}
+
+	subscriptions, err := c.ListRoleSubscriptions(roleType)
+	if err != nil {
+		return err
+	}
+	for _, subscription := range *subscriptions {
+		subscribed := subscription.Status == "subscribed"
+		switch {
+		case wanted[subscription.NotificationType] && !subscribed:
+			err = c.SubscribeRole(roleType, subscription.NotificationType)
+		case !wanted[subscription.NotificationType] && subscribed:
+			err = c.UnsubscribeRole(roleType, subscription.NotificationType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
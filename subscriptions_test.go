@@ -0,0 +1,41 @@
+package okta
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetRoleSubscriptions(t *testing.T) {
+	var requests []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "GET" {
+			w.Write([]byte(`[
+				{"notificationType":"USER_LOCKED_OUT","channels":["email"],"status":"unsubscribed"},
+				{"notificationType":"OKTA_ISSUE","channels":["email"],"status":"subscribed"},
+				{"notificationType":"OKTA_UPDATE","channels":["email"],"status":"subscribed"}
+			]`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer done()
+
+	if err := client.SetRoleSubscriptions(SuperAdmin, NotificationUserLockedOut, NotificationOktaIssue); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GET /api/v1/roles/SUPER_ADMIN/subscriptions",
+		"POST /api/v1/roles/SUPER_ADMIN/subscriptions/USER_LOCKED_OUT/subscribe",
+		"POST /api/v1/roles/SUPER_ADMIN/subscriptions/OKTA_UPDATE/unsubscribe",
+	}
+	if len(requests) != len(expected) {
+		t.Fatal("Expected requests ", expected, ", got ", requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Fatal("Expected requests ", expected, ", got ", requests)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package sync
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteJSON writes the snapshot as indented JSON
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// ReadJSON reads a snapshot written by WriteJSON, numbers are kept as
+// json.Number
+func ReadJSON(r io.Reader) (*Snapshot, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var snapshot = &Snapshot{}
+	if err := decoder.Decode(snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// groupSeparator joins the group names of a user in a CSV cell
+const groupSeparator = ";"
+
+// WriteUsersCSV writes a header row followed by a row per user with the id,
+// status and groups columns and a column per profile attribute, login
+// first. Attributes that aren't strings are written as JSON.
+func WriteUsersCSV(w io.Writer, users []User) error {
+	seen := map[string]bool{}
+	var attributes []string
+	for _, user := range users {
+		for name := range user.Profile {
+			if !seen[name] && name != "login" {
+				seen[name] = true
+				attributes = append(attributes, name)
+			}
+		}
+	}
+	sort.Strings(attributes)
+	attributes = append([]string{"login"}, attributes...)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"id", "status", "groups"}, attributes...)); err != nil {
+		return err
+	}
+	for _, user := range users {
+		row := []string{user.ID, user.Status, strings.Join(user.Groups, groupSeparator)}
+		for _, name := range attributes {
+			cell, err := csvCell(user.Profile[name])
+			if err != nil {
+				return err
+			}
+			row = append(row, cell)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// ReadUsersCSV reads users written by WriteUsersCSV, every attribute is
+// read as a string and empty cells are left out of the profile
+func ReadUsersCSV(r io.Reader) ([]User, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	columns := map[string]int{}
+	for j, name := range header {
+		columns[name] = j
+	}
+	if _, ok := columns["login"]; !ok {
+		return nil, fmt.Errorf("sync: users CSV has no login column")
+	}
+
+	var users []User
+	for _, row := range rows[1:] {
+		user := User{Profile: map[string]interface{}{}}
+		for j, cell := range row {
+			if cell == "" {
+				continue
+			}
+			switch header[j] {
+			case "id":
+				user.ID = cell
+			case "status":
+				user.Status = cell
+			case "groups":
+				user.Groups = strings.Split(cell, groupSeparator)
+			default:
+				user.Profile[header[j]] = cell
+			}
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// WriteGroupsCSV writes a header row followed by the id, name and
+// description of every group
+func WriteGroupsCSV(w io.Writer, groups []Group) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "name", "description"}); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := writer.Write([]string{group.ID, group.Name, group.Description}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadGroupsCSV reads groups written by WriteGroupsCSV
+func ReadGroupsCSV(r io.Reader) ([]Group, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for j, name := range rows[0] {
+		columns[name] = j
+	}
+	name, ok := columns["name"]
+	if !ok {
+		return nil, fmt.Errorf("sync: groups CSV has no name column")
+	}
+	cell := func(row []string, column string) string {
+		if j, ok := columns[column]; ok {
+			return row[j]
+		}
+		return ""
+	}
+
+	var groups []Group
+	for _, row := range rows[1:] {
+		groups = append(groups, Group{
+			ID:          cell(row, "id"),
+			Name:        row[name],
+			Description: cell(row, "description"),
+		})
+	}
+	return groups, nil
+}
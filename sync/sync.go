@@ -0,0 +1,418 @@
+// Package sync exports the users and groups of an Okta org to JSON or CSV
+// and imports them into an org with create-or-update semantics, e.g. to
+// migrate between orgs. Imports only add: users and groups missing from
+// the snapshot and memberships it doesn't list are left alone.
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+// Client is the part of okta.Client used by Export and Import
+type Client interface {
+	okta.UserService
+	okta.GroupService
+}
+
+// Snapshot is the users and groups of an org
+type Snapshot struct {
+	Users  []User  `json:"users"`
+	Groups []Group `json:"groups"`
+}
+
+// User is a user of a Snapshot. Profile holds every non-empty profile
+// attribute, login included, Groups the names of the groups the user is a
+// member of. ID and Status are those of the exported org and are ignored
+// by Import, users are matched by login.
+type User struct {
+	ID      string                 `json:"id,omitempty"`
+	Status  string                 `json:"status,omitempty"`
+	Profile map[string]interface{} `json:"profile"`
+	Groups  []string               `json:"groups,omitempty"`
+}
+
+// Login returns the login of the user from its profile
+func (u *User) Login() string {
+	login, _ := u.Profile["login"].(string)
+	return login
+}
+
+// Group is an OKTA_GROUP of a Snapshot, groups are matched by name
+type Group struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Export returns the users of the org and its OKTA_GROUP groups, app and
+// built-in groups are managed by Okta and left out
+func Export(c Client) (*Snapshot, error) {
+	groups, err := c.ListGroups(&okta.ListGroupsOptions{Filter: `type eq "` + okta.GroupOkta + `"`})
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.ListUsers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	memberships := map[string][]string{}
+	for _, group := range *groups {
+		if group.Type != "" && group.Type != okta.GroupOkta {
+			continue
+		}
+		snapshot.Groups = append(snapshot.Groups, Group{
+			ID:          group.ID,
+			Name:        group.Profile.Name,
+			Description: group.Profile.Description,
+		})
+
+		members, err := c.GroupMembers(group.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range *members {
+			memberships[member.ID] = append(memberships[member.ID], group.Profile.Name)
+		}
+	}
+
+	for _, user := range *users {
+		profile, err := profileMap(user.Profile)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(memberships[user.ID])
+		snapshot.Users = append(snapshot.Users, User{
+			ID:      user.ID,
+			Status:  string(user.Status),
+			Profile: profile,
+			Groups:  memberships[user.ID],
+		})
+	}
+	return snapshot, nil
+}
+
+// ChangedAttributes returns the profile attributes of user whose value
+// differs from the profile of current, the login excepted as logins are
+// matched case insensitively. Strings are converted to the type of a
+// number or boolean attribute of current first, see Import.
+func ChangedAttributes(user User, current *okta.User) (map[string]interface{}, error) {
+	existing, err := profileMap(current.Profile)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]interface{}{}
+	for name, value := range user.Profile {
+		if name == "login" {
+			continue
+		}
+		value = coerce(value, existing[name])
+		if !Equal(value, existing[name]) {
+			changed[name] = value
+		}
+	}
+	return changed, nil
+}
+
+// profileMap returns the non-empty attributes of a profile, numbers are
+// kept as json.Number
+func profileMap(profile okta.UserProfile) (map[string]interface{}, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	var attributes map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil {
+		return nil, err
+	}
+	for name, value := range attributes {
+		if value == nil || value == "" {
+			delete(attributes, name)
+		}
+	}
+	return attributes, nil
+}
+
+// ImportOptions changes how Import applies a snapshot
+type ImportOptions struct {
+	// DryRun reports the changes without making them
+	DryRun bool
+	// Activate activates the users created, they are STAGED otherwise
+	Activate bool
+}
+
+// Action is the kind of a Change
+type Action string
+
+const (
+	CreateGroup Action = "create-group"
+	UpdateGroup Action = "update-group"
+	CreateUser  Action = "create-user"
+	UpdateUser  Action = "update-user"
+	AddMember   Action = "add-member"
+)
+
+// Change is a change Import made, or would make in a dry run. Target is
+// the group name or user login, Group the group name of AddMember and
+// Attributes the profile attributes changed by UpdateUser.
+type Change struct {
+	Action     Action
+	Target     string
+	Group      string
+	Attributes []string
+	Err        error
+}
+
+func (c Change) String() string {
+	switch c.Action {
+	case AddMember:
+		return fmt.Sprintf("%s %s to %s", c.Action, c.Target, c.Group)
+	case UpdateUser:
+		return fmt.Sprintf("%s %s (%s)", c.Action, c.Target, strings.Join(c.Attributes, ", "))
+	}
+	return fmt.Sprintf("%s %s", c.Action, c.Target)
+}
+
+// ImportReport lists the changes of an Import in the order they were made
+type ImportReport struct {
+	DryRun  bool
+	Changes []Change
+}
+
+// Errors returns the errors of the changes that failed
+func (r *ImportReport) Errors() []error {
+	var errs []error
+	for _, change := range r.Changes {
+		if change.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", change, change.Err))
+		}
+	}
+	return errs
+}
+
+// Import creates the groups and users of the snapshot missing from the org
+// and updates those that differ, then adds the missing group memberships.
+// Users are matched by login and groups by name. Existing users only get
+// the attributes of the snapshot that changed, attributes it doesn't carry
+// are kept.
+//
+// A change that fails is recorded in the report and the import goes on,
+// the error returned is for reading the current state of the org. Values
+// read from CSV are strings, they are converted to the type of the
+// attribute of the existing user when it is a number or a boolean.
+func Import(c Client, snapshot *Snapshot, opts *ImportOptions) (*ImportReport, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	i := &importer{
+		client: c,
+		opts:   opts,
+		report: &ImportReport{DryRun: opts.DryRun},
+		groups: map[string]*okta.Group{},
+		users:  map[string]*okta.User{},
+	}
+
+	groups, err := c.ListGroups(&okta.ListGroupsOptions{Filter: `type eq "` + okta.GroupOkta + `"`})
+	if err != nil {
+		return nil, err
+	}
+	for j := range *groups {
+		group := &(*groups)[j]
+		i.groups[group.Profile.Name] = group
+	}
+	users, err := c.ListUsers(nil)
+	if err != nil {
+		return nil, err
+	}
+	for j := range *users {
+		user := &(*users)[j]
+		i.users[strings.ToLower(user.Profile.Login)] = user
+	}
+
+	for _, group := range snapshot.Groups {
+		i.group(group)
+	}
+	for _, user := range snapshot.Users {
+		if err := i.user(user); err != nil {
+			return nil, err
+		}
+	}
+	return i.report, nil
+}
+
+type importer struct {
+	client Client
+	opts   *ImportOptions
+	report *ImportReport
+
+	// groups and users are the current ones keyed by name and lowercase
+	// login, members caches the ids of the members of existing groups
+	groups  map[string]*okta.Group
+	users   map[string]*okta.User
+	members map[string]map[string]bool
+}
+
+func (i *importer) record(change Change) {
+	i.report.Changes = append(i.report.Changes, change)
+}
+
+func (i *importer) group(group Group) {
+	profile := okta.GroupProfile{Name: group.Name, Description: group.Description}
+
+	current, ok := i.groups[group.Name]
+	switch {
+	case !ok:
+		change := Change{Action: CreateGroup, Target: group.Name}
+		created := &okta.Group{Profile: profile}
+		if !i.opts.DryRun {
+			created, change.Err = i.client.CreateGroup(profile)
+		}
+		if change.Err == nil {
+			i.groups[group.Name] = created
+		}
+		i.record(change)
+	case current.Profile.Description != group.Description:
+		change := Change{Action: UpdateGroup, Target: group.Name}
+		if !i.opts.DryRun {
+			_, change.Err = i.client.UpdateGroup(current.ID, profile)
+		}
+		i.record(change)
+	}
+}
+
+func (i *importer) user(user User) error {
+	login := user.Login()
+	if login == "" {
+		i.record(Change{Action: CreateUser, Err: fmt.Errorf("sync: user %q has no login", user.ID)})
+		return nil
+	}
+
+	current, ok := i.users[strings.ToLower(login)]
+	if !ok {
+		change := Change{Action: CreateUser, Target: login}
+		request := &okta.CreateUserRequest{Profile: user.Profile}
+		for _, name := range user.Groups {
+			if group, ok := i.groups[name]; ok && group.ID != "" {
+				request.GroupIDs = append(request.GroupIDs, group.ID)
+			}
+		}
+		if !i.opts.DryRun {
+			_, change.Err = i.client.CreateUser(request, i.opts.Activate)
+		}
+		i.record(change)
+		for _, name := range user.Groups {
+			membership := Change{Action: AddMember, Target: login, Group: name}
+			if _, ok := i.groups[name]; !ok {
+				membership.Err = fmt.Errorf("sync: no group %q", name)
+			} else if change.Err != nil {
+				membership.Err = change.Err
+			}
+			i.record(membership)
+		}
+		return nil
+	}
+
+	changed, err := ChangedAttributes(user, current)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for name := range changed {
+		names = append(names, name)
+	}
+	if len(changed) > 0 {
+		sort.Strings(names)
+		change := Change{Action: UpdateUser, Target: login, Attributes: names}
+		if !i.opts.DryRun {
+			_, change.Err = i.client.UpdateUserProfilePartial(current.ID, changed)
+		}
+		i.record(change)
+	}
+
+	for _, name := range user.Groups {
+		membership := Change{Action: AddMember, Target: login, Group: name}
+		group, ok := i.groups[name]
+		switch {
+		case !ok:
+			membership.Err = fmt.Errorf("sync: no group %q", name)
+		case group.ID == "":
+			// created by this dry run
+		default:
+			members, err := i.groupMembers(group.ID)
+			if err != nil {
+				return err
+			}
+			if members[current.ID] {
+				continue
+			}
+			if !i.opts.DryRun {
+				membership.Err = i.client.AddUserToGroup(group.ID, current.ID)
+			}
+		}
+		i.record(membership)
+	}
+	return nil
+}
+
+func (i *importer) groupMembers(groupID string) (map[string]bool, error) {
+	if members, ok := i.members[groupID]; ok {
+		return members, nil
+	}
+	users, err := i.client.GroupMembers(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members := map[string]bool{}
+	for _, user := range *users {
+		members[user.ID] = true
+	}
+	if i.members == nil {
+		i.members = map[string]map[string]bool{}
+	}
+	i.members[groupID] = members
+	return members, nil
+}
+
+// coerce converts a string read from CSV to a number or boolean when the
+// existing value of the attribute is one
+func coerce(value, existing interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch existing.(type) {
+	case json.Number:
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+	case bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// Equal reports whether two attribute values have the same JSON encoding,
+// e.g. a json.Number and the float64 of the same number
+func Equal(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(left, right)
+}
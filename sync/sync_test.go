@@ -0,0 +1,232 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	okta "github.com/Cox-Automotive/go-okta"
+)
+
+// fakeOrg serves the users, groups and memberships endpoints from memory
+type fakeOrg struct {
+	users   []map[string]interface{}
+	groups  []map[string]interface{}
+	members map[string][]string
+	writes  []string
+}
+
+func (o *fakeOrg) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	if r.Method != "GET" {
+		o.writes = append(o.writes, r.Method+" "+r.URL.Path)
+	}
+
+	switch {
+	case r.Method == "GET" && len(path) == 1 && path[0] == "users":
+		json.NewEncoder(w).Encode(o.users)
+	case r.Method == "GET" && len(path) == 1 && path[0] == "groups":
+		json.NewEncoder(w).Encode(o.groups)
+	case r.Method == "GET" && len(path) == 3 && path[0] == "groups":
+		var users []map[string]interface{}
+		for _, id := range o.members[path[1]] {
+			users = append(users, map[string]interface{}{"id": id})
+		}
+		json.NewEncoder(w).Encode(users)
+	case r.Method == "POST" && len(path) == 1 && path[0] == "groups":
+		group := map[string]interface{}{"id": "00gnew", "type": okta.GroupOkta, "profile": body["profile"]}
+		o.groups = append(o.groups, group)
+		json.NewEncoder(w).Encode(group)
+	case r.Method == "POST" && len(path) == 1 && path[0] == "users":
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "00unew", "profile": body["profile"]})
+	case r.Method == "PUT" && len(path) == 4 && path[0] == "groups":
+		o.members[path[1]] = append(o.members[path[1]], path[3])
+	default:
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+// newFakeOrg returns the fake org, a client calling it and a func closing it
+func newFakeOrg() (*fakeOrg, *okta.Client, func()) {
+	org := &fakeOrg{
+		users: []map[string]interface{}{
+			{"id": "00u1", "status": "ACTIVE", "profile": map[string]interface{}{
+				"login": "jane@example.com", "email": "jane@example.com", "firstName": "Jane", "employeeId": 7,
+			}},
+			{"id": "00u2", "status": "STAGED", "profile": map[string]interface{}{
+				"login": "john@example.com", "email": "john@example.com", "firstName": "John",
+			}},
+		},
+		groups: []map[string]interface{}{
+			{"id": "00g1", "type": okta.GroupOkta, "profile": map[string]interface{}{"name": "engineering", "description": "Engineers"}},
+			{"id": "00g2", "type": okta.GroupBuiltIn, "profile": map[string]interface{}{"name": "Everyone"}},
+		},
+		members: map[string][]string{"00g1": {"00u1"}, "00g2": {"00u1", "00u2"}},
+	}
+
+	server := httptest.NewTLSServer(org)
+	client := okta.NewClient("organization")
+	client.BaseURL = server.URL
+	client.SetHTTPClient(server.Client())
+	return org, client, server.Close
+}
+
+func TestExport(t *testing.T) {
+	_, client, done := newFakeOrg()
+	defer done()
+
+	snapshot, err := Export(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Groups) != 1 || snapshot.Groups[0].Name != "engineering" {
+		t.Error("Expected only the OKTA_GROUP, got ", snapshot.Groups)
+	}
+	if len(snapshot.Users) != 2 {
+		t.Fatal("Expected both users, got ", snapshot.Users)
+	}
+	jane := snapshot.Users[0]
+	if jane.Login() != "jane@example.com" || !reflect.DeepEqual(jane.Groups, []string{"engineering"}) {
+		t.Error("Unexpected user ", jane)
+	}
+	if jane.Profile["employeeId"] != json.Number("7") {
+		t.Error("Expected custom attributes to be kept, got ", jane.Profile)
+	}
+	if _, ok := jane.Profile["lastName"]; ok {
+		t.Error("Expected empty attributes to be left out, got ", jane.Profile)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, snapshot) {
+		t.Error("Expected the JSON to round trip, got ", read)
+	}
+}
+
+func TestUsersCSV(t *testing.T) {
+	users := []User{
+		{ID: "00u1", Status: "ACTIVE", Groups: []string{"a", "b"}, Profile: map[string]interface{}{
+			"login": "jane@example.com", "firstName": "Jane", "employeeId": json.Number("7"),
+		}},
+		{ID: "00u2", Profile: map[string]interface{}{"login": "john@example.com", "city": "Atlanta, GA"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUsersCSV(&buf, users); err != nil {
+		t.Fatal(err)
+	}
+	header := strings.SplitN(buf.String(), "\n", 2)[0]
+	if header != "id,status,groups,login,city,employeeId,firstName" {
+		t.Error("Unexpected header ", header)
+	}
+
+	read, err := ReadUsersCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users[0].Profile["employeeId"] = "7"
+	if !reflect.DeepEqual(read, users) {
+		t.Errorf("Expected the CSV to round trip, got %#v", read)
+	}
+
+	if _, err := ReadUsersCSV(strings.NewReader("id,email\n00u1,jane@example.com\n")); err == nil {
+		t.Error("Expected an error without a login column")
+	}
+}
+
+func TestGroupsCSV(t *testing.T) {
+	groups := []Group{{ID: "00g1", Name: "engineering", Description: "Engineers, all of them"}}
+
+	var buf bytes.Buffer
+	if err := WriteGroupsCSV(&buf, groups); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadGroupsCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, groups) {
+		t.Error("Expected the CSV to round trip, got ", read)
+	}
+}
+
+func TestImport(t *testing.T) {
+	snapshot := &Snapshot{
+		Groups: []Group{
+			{Name: "engineering", Description: "Engineering"},
+			{Name: "sales"},
+		},
+		Users: []User{
+			{Groups: []string{"engineering"}, Profile: map[string]interface{}{
+				"login": "JANE@example.com", "firstName": "Jane", "employeeId": "8",
+			}},
+			{Groups: []string{"engineering", "sales"}, Profile: map[string]interface{}{
+				"login": "john@example.com", "firstName": "John",
+			}},
+			{Groups: []string{"sales", "unknown"}, Profile: map[string]interface{}{
+				"login": "ann@example.com",
+			}},
+		},
+	}
+	expected := []string{
+		"update-group engineering",
+		"create-group sales",
+		"update-user JANE@example.com (employeeId)",
+		"add-member john@example.com to engineering",
+		"add-member john@example.com to sales",
+		"create-user ann@example.com",
+		"add-member ann@example.com to sales",
+		"add-member ann@example.com to unknown",
+	}
+
+	org, client, done := newFakeOrg()
+	defer done()
+	report, err := Import(client, snapshot, &ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []string
+	for _, change := range report.Changes {
+		changes = append(changes, change.String())
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Error("Unexpected changes ", changes)
+	}
+	if len(report.Errors()) != 1 {
+		t.Error("Expected the unknown group to be reported, got ", report.Errors())
+	}
+	if len(org.writes) != 0 {
+		t.Error("Expected no writes in a dry run, got ", org.writes)
+	}
+
+	report, err = Import(client, snapshot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != len(expected) || len(report.Errors()) != 1 {
+		t.Error("Expected the same changes to be made, got ", report.Changes)
+	}
+	writes := []string{
+		"PUT /api/v1/groups/00g1",
+		"POST /api/v1/groups",
+		"POST /api/v1/users/00u1",
+		"PUT /api/v1/groups/00g1/users/00u2",
+		"PUT /api/v1/groups/00gnew/users/00u2",
+		"POST /api/v1/users",
+	}
+	if !reflect.DeepEqual(org.writes, writes) {
+		t.Error("Unexpected writes ", org.writes)
+	}
+}
@@ -0,0 +1,35 @@
+package okta
+
+import (
+	"time"
+)
+
+// ThreatInsight actions
+const (
+	ThreatInsightNone  = "none"
+	ThreatInsightAudit = "audit"
+	ThreatInsightBlock = "block"
+)
+
+type ThreatInsightConfiguration struct {
+	Action string `json:"action"`
+	// ExcludeZones are ids of network zones ThreatInsight ignores
+	ExcludeZones []string   `json:"excludeZones"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastUpdated  *time.Time `json:"lastUpdated,omitempty"`
+}
+
+// GetThreatInsightConfiguration returns the ThreatInsight settings of the org
+func (c *Client) GetThreatInsightConfiguration() (*ThreatInsightConfiguration, error) {
+	var response = &ThreatInsightConfiguration{}
+	err, _ := c.call("threats/configuration", "GET", nil, response)
+	return response, err
+}
+
+// UpdateThreatInsightConfiguration changes the ThreatInsight action and
+// excluded network zones
+func (c *Client) UpdateThreatInsightConfiguration(config *ThreatInsightConfiguration) (*ThreatInsightConfiguration, error) {
+	var response = &ThreatInsightConfiguration{}
+	err, _ := c.call("threats/configuration", "POST", config, response)
+	return response, err
+}
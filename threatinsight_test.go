@@ -0,0 +1,39 @@
+package okta
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestThreatInsightConfiguration(t *testing.T) {
+	var update string
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			body, _ := ioutil.ReadAll(r.Body)
+			update = string(body)
+			w.Write([]byte(`{"action":"block","excludeZones":["nzo1"],"created":"2020-08-05T22:18:30.629Z","lastUpdated":"2020-09-08T20:53:20.882Z"}`))
+			return
+		}
+		w.Write([]byte(`{"action":"audit","excludeZones":[],"created":"2020-08-05T22:18:30.629Z","lastUpdated":"2020-08-05T22:18:30.629Z"}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	config, err := client.GetThreatInsightConfiguration()
+	if err != nil || config.Action != ThreatInsightAudit || config.Created == nil {
+		t.Fatal("Expected the configuration, got ", config, err)
+	}
+	config, err = client.UpdateThreatInsightConfiguration(&ThreatInsightConfiguration{Action: ThreatInsightBlock, ExcludeZones: []string{"nzo1"}})
+	if err != nil || config.Action != ThreatInsightBlock || len(config.ExcludeZones) != 1 || config.LastUpdated.Month() != 9 {
+		t.Fatal("Expected the updated configuration, got ", config, err)
+	}
+	if update != `{"action":"block","excludeZones":["nzo1"]}` {
+		t.Error("Unexpected update ", update)
+	}
+
+	recorder.expect(t,
+		"GET /api/v1/threats/configuration",
+		"POST /api/v1/threats/configuration",
+	)
+}
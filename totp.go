@@ -0,0 +1,99 @@
+package okta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// TOTPActivation is the shared secret of a TOTP factor being enrolled, to
+// show as a QR code or for manual entry in the authenticator app
+type TOTPActivation struct {
+	TimeStep     int    `json:"timeStep"`
+	SharedSecret string `json:"sharedSecret"`
+	// Encoding of SharedSecret, base32
+	Encoding  string `json:"encoding"`
+	KeyLength int    `json:"keyLength"`
+	Links     struct {
+		// QRCode is the PNG of the QR code to scan, Href is a data URI or a
+		// link expiring with the transaction
+		QRCode struct {
+			Href string `json:"href"`
+			Type string `json:"type"`
+		} `json:"qrcode"`
+	} `json:"_links"`
+}
+
+// URI returns the otpauth key URI of the secret, for rendering the QR code
+// without the image Okta provides. issuer and account are what the
+// authenticator app displays, e.g. the org and the login of the user.
+func (a *TOTPActivation) URI(issuer, account string) string {
+	v := url.Values{}
+	v.Set("secret", a.SharedSecret)
+	v.Set("issuer", issuer)
+	if a.TimeStep > 0 {
+		v.Set("period", strconv.Itoa(a.TimeStep))
+	}
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+func (f *Factor) UnmarshalJSON(data []byte) error {
+	type plain Factor
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+	if f.FactorType != FactorTOTP {
+		return nil
+	}
+
+	// the activation of TOTP factors is a shared secret, not the WebAuthn
+	// creation options
+	var embedded struct {
+		Embedded struct {
+			Activation *TOTPActivation `json:"activation"`
+		} `json:"_embedded"`
+	}
+	if err := json.Unmarshal(data, &embedded); err != nil {
+		return err
+	}
+	f.Embedded.Activation = nil
+	f.Embedded.TOTP = embedded.Embedded.Activation
+	return nil
+}
+
+// EnrollTOTP starts enrolling a TOTP factor of provider, FactorProviderOkta
+// for Okta Verify or FactorProviderGoogle for Google Authenticator, in
+// MFA_ENROLL. The returned MFA_ENROLL_ACTIVATE transaction holds the
+// shared secret and QR code in Embedded.Factor.Embedded.TOTP.
+// https://developer.okta.com/docs/reference/api/authn/#enroll-okta-verify-totp-factor
+func (r *AuthnResponse) EnrollTOTP(provider FactorProvider) (*AuthnResponse, error) {
+	if r.Status != AuthnMFAEnroll {
+		return nil, fmt.Errorf("can not enroll a factor in %s", r.Status)
+	}
+
+	for _, factor := range r.Embedded.Factors {
+		if factor.FactorType == FactorTOTP && factor.Provider == provider && factor.Links.Enroll.Href != "" {
+			return postAuthn(factor.Links.Enroll.Href, map[string]interface{}{
+				"stateToken": r.StateToken,
+				"factorType": FactorTOTP,
+				"provider":   provider,
+			})
+		}
+	}
+	return nil, errors.New("totp of " + string(provider) + " is not available for enrollment")
+}
+
+// ActivateTOTP completes the enrollment started by EnrollTOTP with a
+// passcode of the authenticator app
+// https://developer.okta.com/docs/reference/api/authn/#activate-totp-factor
+func (r *AuthnResponse) ActivateTOTP(passCode string) (*AuthnResponse, error) {
+	if r.Status != AuthnMFAEnrollActivate {
+		return nil, fmt.Errorf("can not activate a factor in %s", r.Status)
+	}
+	return r.Next(map[string]interface{}{
+		"passCode": passCode,
+	})
+}
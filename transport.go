@@ -0,0 +1,66 @@
+package okta
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// defaultTransport is shared by the clients of NewClient, so that clients
+// of the same org share their connections
+var defaultTransport = NewTransport(TransportOptions{})
+
+// TransportOptions tune the connections of a client to its org, see
+// NewTransport. Every org is a single host, the per host limits are those
+// that matter.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept for reuse,
+	// 100 when zero. It should not be lower than the concurrency of the
+	// calls or connections are closed and opened again all the time.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost bounds the connections, unlimited when zero
+	MaxConnsPerHost int
+	// IdleConnTimeout closes idle connections, 90 seconds when zero
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 sticks to HTTP/1.1, e.g. behind proxies mishandling
+	// HTTP/2. HTTP/2 multiplexes the calls over few connections otherwise.
+	DisableHTTP2 bool
+}
+
+// NewTransport returns a transport tuned for many concurrent calls to an
+// org, with the proxy, dial and TLS settings of http.DefaultTransport
+func NewTransport(opts TransportOptions) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost == 0 {
+		transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if transport.MaxIdleConns < transport.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = transport.MaxIdleConnsPerHost
+	}
+	transport.MaxConnsPerHost = opts.MaxConnsPerHost
+	transport.IdleConnTimeout = opts.IdleConnTimeout
+	if transport.IdleConnTimeout == 0 {
+		transport.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	transport.ForceAttemptHTTP2 = !opts.DisableHTTP2
+	if opts.DisableHTTP2 {
+		// a non-nil empty map disables the HTTP/2 upgrade
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
+
+// SetTransportOptions replaces the transport of the client with one of
+// NewTransport, the client no longer shares connections with the other
+// clients of NewClient
+func (c *Client) SetTransportOptions(opts TransportOptions) {
+	client := *c.client
+	client.Transport = NewTransport(opts)
+	c.client = &client
+}
@@ -0,0 +1,122 @@
+package okta
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport := NewTransport(TransportOptions{})
+	if transport.MaxIdleConnsPerHost != 100 || transport.MaxIdleConns < 100 || !transport.ForceAttemptHTTP2 || transport.Proxy == nil {
+		t.Error("Unexpected defaults ", transport.MaxIdleConnsPerHost, transport.MaxIdleConns, transport.ForceAttemptHTTP2)
+	}
+
+	transport = NewTransport(TransportOptions{MaxIdleConnsPerHost: 8, MaxConnsPerHost: 16, IdleConnTimeout: time.Second, DisableHTTP2: true})
+	if transport.MaxIdleConnsPerHost != 8 || transport.MaxConnsPerHost != 16 || transport.IdleConnTimeout != time.Second {
+		t.Error("Expected the options to be applied, got ", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout)
+	}
+	if transport.ForceAttemptHTTP2 || transport.TLSNextProto == nil {
+		t.Error("Expected HTTP/2 to be disabled")
+	}
+
+	client := NewClient("organization")
+	if client.client.Transport != defaultTransport || NewClient("other").client.Transport != defaultTransport {
+		t.Error("Expected the clients to share the default transport")
+	}
+	client.SetTransportOptions(TransportOptions{MaxIdleConnsPerHost: 8})
+	if client.client.Transport == defaultTransport || client.client.Jar == nil {
+		t.Error("Expected a transport of its own keeping the cookie jar")
+	}
+}
+
+// newCountingServer returns a server counting the connections opened to it
+func newCountingServer(tb testing.TB, body string) (*httptest.Server, *int64) {
+	var conns int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&conns, 1)
+		}
+	}
+	server.Start()
+	tb.Cleanup(server.Close)
+	return server, &conns
+}
+
+func TestConnectionReuse(t *testing.T) {
+	server, conns := newCountingServer(t, `{"id":"00u1"}`)
+	client := NewClient("organization")
+	client.BaseURL = server.URL
+	client.ApiToken = "token"
+
+	const concurrency = 20
+	for round := 0; round < 3; round++ {
+		var wg sync.WaitGroup
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.User("00u1"); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	if opened := atomic.LoadInt64(conns); opened > concurrency {
+		t.Error("Expected the connections to be reused, opened ", opened)
+	}
+}
+
+func benchmarkUsers(n int) string {
+	users := make([]string, n)
+	for i := range users {
+		id := strconv.Itoa(i)
+		users[i] = `{"id":"00u` + id + `","status":"ACTIVE","created":"2026-01-02T03:04:05.000Z","profile":{"login":"user` + id +
+			`@example.com","email":"user` + id + `@example.com","firstName":"First","lastName":"Last","costCenter":"42","badge":` + id + `}}`
+	}
+	return "[" + strings.Join(users, ",") + "]"
+}
+
+func BenchmarkListUsersPage(b *testing.B) {
+	server, _ := newCountingServer(b, benchmarkUsers(200))
+	client := NewClient("organization")
+	client.BaseURL = server.URL
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var users []User
+		if err, _ := client.call("users?limit=200", "GET", nil, &users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUserParallel(b *testing.B) {
+	server, conns := newCountingServer(b, `{"id":"00u1","status":"ACTIVE","profile":{"login":"jane@example.com"}}`)
+	client := NewClient("organization")
+	client.BaseURL = server.URL
+
+	b.ReportAllocs()
+	b.SetParallelism(8)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.User("00u1"); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(atomic.LoadInt64(conns)), "conns")
+}
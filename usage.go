@@ -0,0 +1,162 @@
+package okta
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// EndpointUsage is the recorded traffic for one endpoint template, e.g.
+// GET users/{id}/groups
+type EndpointUsage struct {
+	Method   string
+	Endpoint string
+	Calls    int
+	Errors   int
+	// Throttled counts 429 Too Many Requests responses
+	Throttled int
+	// Limit and MinRemaining come from the X-Rate-Limit-* headers, both are
+	// zero if Okta never sent them for this endpoint
+	Limit        int
+	MinRemaining int
+}
+
+// PeakUtilization is the largest share of the rate limit bucket consumed at
+// any point, between 0 and 1
+func (u EndpointUsage) PeakUtilization() float64 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return float64(u.Limit-u.MinRemaining) / float64(u.Limit)
+}
+
+// UsageAnalyzer records per-endpoint call counts and rate limit consumption
+// of a client, set it as Client.Usage before a run and print the report
+// afterwards to see whether a job fits in the org's API quotas.
+type UsageAnalyzer struct {
+	mu        sync.Mutex
+	started   time.Time
+	endpoints map[string]*EndpointUsage
+}
+
+// NewUsageAnalyzer returns an empty analyzer, the run duration used for
+// per-minute rates is measured from this call
+func NewUsageAnalyzer() *UsageAnalyzer {
+	return &UsageAnalyzer{
+		started:   time.Now(),
+		endpoints: map[string]*EndpointUsage{},
+	}
+}
+
+func (a *UsageAnalyzer) record(method, endpoint string, resp *http.Response) {
+	template := endpointTemplate(endpoint)
+	key := method + " " + template
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	usage, ok := a.endpoints[key]
+	if !ok {
+		usage = &EndpointUsage{Method: method, Endpoint: template}
+		a.endpoints[key] = usage
+	}
+
+	usage.Calls++
+	if resp == nil {
+		usage.Errors++
+		return
+	}
+	if resp.StatusCode >= 400 {
+		usage.Errors++
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		usage.Throttled++
+	}
+
+	limit, err := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Remaining"))
+	if err != nil {
+		return
+	}
+	if usage.Limit == 0 || remaining < usage.MinRemaining {
+		usage.MinRemaining = remaining
+	}
+	usage.Limit = limit
+}
+
+// Summary returns the usage of every endpoint called so far, busiest first
+func (a *UsageAnalyzer) Summary() []EndpointUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary := make([]EndpointUsage, 0, len(a.endpoints))
+	for _, usage := range a.endpoints {
+		summary = append(summary, *usage)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Calls != summary[j].Calls {
+			return summary[i].Calls > summary[j].Calls
+		}
+		return summary[i].Method+summary[i].Endpoint < summary[j].Method+summary[j].Endpoint
+	})
+	return summary
+}
+
+// WriteReport writes a table of the summary to w including the average
+// calls per minute, Okta rate limits are enforced per minute
+func (a *UsageAnalyzer) WriteReport(w io.Writer) error {
+	elapsed := time.Since(a.started)
+	minutes := elapsed.Minutes()
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Okta API usage over %s\n", elapsed.Round(time.Second))
+	fmt.Fprintln(tw, "METHOD\tENDPOINT\tCALLS\tCALLS/MIN\tERRORS\t429s\tLIMIT\tPEAK USED")
+	for _, u := range a.Summary() {
+		peak := "-"
+		limit := "-"
+		if u.Limit > 0 {
+			peak = fmt.Sprintf("%.0f%%", u.PeakUtilization()*100)
+			limit = strconv.Itoa(u.Limit)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%d\t%d\t%s\t%s\n",
+			u.Method, u.Endpoint, u.Calls, float64(u.Calls)/minutes,
+			u.Errors, u.Throttled, limit, peak)
+	}
+	return tw.Flush()
+}
+
+// endpointTemplate replaces resource ids in an endpoint so calls for
+// different users or groups are counted together,
+// users/00u1/groups?limit=200 becomes users/{id}/groups
+func endpointTemplate(endpoint string) string {
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	segments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	for i, segment := range segments {
+		if isResourceID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isResourceID reports whether a path segment is an Okta id, like 00u1 or
+// 0oa1, or a login. Collections and operations like groups/rules or
+// lifecycle/activate never hold a digit, nor does users/me.
+func isResourceID(segment string) bool {
+	return strings.ContainsAny(segment, "0123456789@")
+}
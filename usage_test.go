@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEndpointTemplate(t *testing.T) {
+	cases := map[string]string{
+		"users/00u1/groups?limit=200":                   "users/{id}/groups",
+		"users/00u1/lifecycle/activate":                 "users/{id}/lifecycle/activate",
+		"users/me/appLinks":                             "users/me/appLinks",
+		"authorizationServers/aus1/policies/00p1/rules": "authorizationServers/{id}/policies/{id}/rules",
+		"authn":                                "authn",
+		"groups/rules":                         "groups/rules",
+		"groups/rules/0pr1/lifecycle/activate": "groups/rules/{id}/lifecycle/activate",
+		"threats/configuration":                "threats/configuration",
+		"apps/0oa1/credentials/keys/akm5hvbbevE341ovl0h7": "apps/{id}/credentials/keys/{id}",
+		"users/jane@example.com":                          "users/{id}",
+	}
+	for endpoint, expected := range cases {
+		if got := endpointTemplate(endpoint); got != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, endpoint, got)
+		}
+	}
+}
+
+func TestUsageAnalyzer(t *testing.T) {
+	analyzer := NewUsageAnalyzer()
+	for _, remaining := range []string{"590", "580", "585"} {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		resp.Header.Set("X-Rate-Limit-Limit", "600")
+		resp.Header.Set("X-Rate-Limit-Remaining", remaining)
+		analyzer.record("GET", "users/00u1", resp)
+	}
+	analyzer.record("GET", "users/00u2", &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+
+	summary := analyzer.Summary()
+	if len(summary) != 1 {
+		t.Fatal("Expected 1 endpoint, got ", len(summary))
+	}
+	usage := summary[0]
+	if usage.Calls != 4 || usage.Throttled != 1 || usage.MinRemaining != 580 || usage.Limit != 600 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+
+	var report bytes.Buffer
+	if err := analyzer.WriteReport(&report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report.String(), "users/{id}") {
+		t.Error("Expected endpoint in report, got ", report.String())
+	}
+}
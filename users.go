@@ -1,44 +1,43 @@
 package okta
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 )
 
+// UserStatus is the lifecycle state of a user
+type UserStatus string
+
+// https://developer.okta.com/docs/reference/api/users/#user-status
+const (
+	UserStaged          UserStatus = "STAGED"
+	UserProvisioned     UserStatus = "PROVISIONED"
+	UserActive          UserStatus = "ACTIVE"
+	UserRecovery        UserStatus = "RECOVERY"
+	UserPasswordExpired UserStatus = "PASSWORD_EXPIRED"
+	UserLockedOut       UserStatus = "LOCKED_OUT"
+	UserSuspended       UserStatus = "SUSPENDED"
+	UserDeprovisioned   UserStatus = "DEPROVISIONED"
+)
+
 type User struct {
-	ID              string     `json:"id"`
-	Status          string     `json:"status"`
-	Created         *time.Time `json:"created"`
-	Activated       *time.Time `json:"activated"`
-	StatusChanged   *time.Time `json:"statusChanged"`
-	LastLogin       *time.Time `json:"lastLogin"`
-	LastUpdated     *time.Time `json:"lastUpdated"`
-	PasswordChanged *time.Time `json:"passwordChanged"`
-	Profile         struct {
-		Login             string `json:"login"`
-		FirstName         string `json:"firstName"`
-		LastName          string `json:"lastName"`
-		NickName          string `json:"nickName"`
-		DisplayName       string `json:"displayName"`
-		Email             string `json:"email"`
-		SecondEmail       string `json:"secondEmail"`
-		ProfileURL        string `json:"profileUrl"`
-		PreferredLanguage string `json:"preferredLanguage"`
-		UserType          string `json:"userType"`
-		Organization      string `json:"organization"`
-		Title             string `json:"title"`
-		Division          string `json:"division"`
-		Department        string `json:"department"`
-		CostCenter        string `json:"costCenter"`
-		EmployeeNumber    string `json:"employeeNumber"`
-		MobilePhone       string `json:"mobilePhone"`
-		PrimaryPhone      string `json:"primaryPhone"`
-		StreetAddress     string `json:"streetAddress"`
-		City              string `json:"city"`
-		State             string `json:"state"`
-		ZipCode           string `json:"zipCode"`
-		CountryCode       string `json:"countryCode"`
-	} `json:"profile"`
-	Credentials struct {
+	ID              string      `json:"id"`
+	Status          UserStatus  `json:"status"`
+	Created         *time.Time  `json:"created"`
+	Activated       *time.Time  `json:"activated"`
+	StatusChanged   *time.Time  `json:"statusChanged"`
+	LastLogin       *time.Time  `json:"lastLogin"`
+	LastUpdated     *time.Time  `json:"lastUpdated"`
+	PasswordChanged *time.Time  `json:"passwordChanged"`
+	Profile         UserProfile `json:"profile"`
+	Credentials     struct {
 		Password struct {
 		} `json:"password"`
 		RecoveryQuestion struct {
@@ -49,35 +48,229 @@ type User struct {
 			Name string `json:"name"`
 		} `json:"provider"`
 	} `json:"credentials"`
-	Links struct {
-		ResetPassword struct {
-			Href string `json:"href"`
-		} `json:"resetPassword"`
-		ResetFactors struct {
-			Href string `json:"href"`
-		} `json:"resetFactors"`
-		ExpirePassword struct {
-			Href string `json:"href"`
-		} `json:"expirePassword"`
-		ForgotPassword struct {
-			Href string `json:"href"`
-		} `json:"forgotPassword"`
-		ChangeRecoveryQuestion struct {
-			Href string `json:"href"`
-		} `json:"changeRecoveryQuestion"`
-		Deactivate struct {
-			Href string `json:"href"`
-		} `json:"deactivate"`
-		ChangePassword struct {
-			Href string `json:"href"`
-		} `json:"changePassword"`
-	} `json:"_links"`
+	Links    UserLinks `json:"_links"`
+	Embedded struct {
+		// Blocks is set when fetched with WithExpand("blocks")
+		Blocks []UserBlock `json:"blocks"`
+	} `json:"_embedded"`
+}
+
+// UserLinks are the _links of a user, All holds every relation including
+// those without a field of their own
+type UserLinks struct {
+	ResetPassword          Link  `json:"resetPassword"`
+	ResetFactors           Link  `json:"resetFactors"`
+	ExpirePassword         Link  `json:"expirePassword"`
+	ForgotPassword         Link  `json:"forgotPassword"`
+	ChangeRecoveryQuestion Link  `json:"changeRecoveryQuestion"`
+	Deactivate             Link  `json:"deactivate"`
+	ChangePassword         Link  `json:"changePassword"`
+	All                    Links `json:"-"`
 }
 
+func (l *UserLinks) UnmarshalJSON(data []byte) error {
+	type plain UserLinks
+	if err := json.Unmarshal(data, (*plain)(l)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &l.All)
+}
+
+// UserBlock is a sign in block of a user after too many failed attempts
+type UserBlock struct {
+	Type      string `json:"type"`
+	AppliesTo string `json:"appliesTo"`
+}
+
+// Group types
+const (
+	GroupOkta    = "OKTA_GROUP"
+	GroupApp     = "APP_GROUP"
+	GroupBuiltIn = "BUILT_IN"
+)
+
 type Group struct {
-	ID      string `json:"id"`
-	Profile struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	} `json:"profile"`
-}
\ No newline at end of file
+	ID                    string       `json:"id"`
+	Type                  string       `json:"type"`
+	Created               *time.Time   `json:"created"`
+	LastUpdated           *time.Time   `json:"lastUpdated"`
+	LastMembershipUpdated *time.Time   `json:"lastMembershipUpdated"`
+	ObjectClass           []string     `json:"objectClass"`
+	Profile               GroupProfile `json:"profile"`
+	Links                 Links        `json:"_links"`
+	Embedded              struct {
+		// Stats is set when listed with Expand "stats"
+		Stats *GroupStats `json:"stats"`
+		// App is the app an APP_GROUP is imported from, set when listed
+		// with Expand "app"
+		App *App `json:"app"`
+	} `json:"_embedded"`
+}
+
+// GroupStats are the counts embedded in groups listed with Expand "stats"
+type GroupStats struct {
+	UsersCount             int  `json:"usersCount"`
+	AppsCount              int  `json:"appsCount"`
+	GroupPushMappingsCount int  `json:"groupPushMappingsCount"`
+	HasAdminPrivilege      bool `json:"hasAdminPrivilege"`
+}
+
+// CreateUserRequest is the body of CreateUser, Profile may be any value
+// encoding to a JSON object such as a map[string]interface{}. Without
+// Credentials the user sets a password on activation.
+type CreateUserRequest struct {
+	Profile     interface{}      `json:"profile"`
+	Credentials *UserCredentials `json:"credentials,omitempty"`
+	GroupIDs    []string         `json:"groupIds,omitempty"`
+}
+
+// UserProfile is the profile of a user. Attributes that aren't part of the
+// default Okta profile are kept in Custom so they survive a round trip
+// through UpdateUserProfile.
+type UserProfile struct {
+	Login             string `json:"login"`
+	FirstName         string `json:"firstName"`
+	LastName          string `json:"lastName"`
+	NickName          string `json:"nickName"`
+	DisplayName       string `json:"displayName"`
+	Email             string `json:"email"`
+	SecondEmail       string `json:"secondEmail"`
+	ProfileURL        string `json:"profileUrl"`
+	PreferredLanguage string `json:"preferredLanguage"`
+	UserType          string `json:"userType"`
+	Organization      string `json:"organization"`
+	Title             string `json:"title"`
+	Division          string `json:"division"`
+	Department        string `json:"department"`
+	CostCenter        string `json:"costCenter"`
+	EmployeeNumber    string `json:"employeeNumber"`
+	MobilePhone       string `json:"mobilePhone"`
+	PrimaryPhone      string `json:"primaryPhone"`
+	StreetAddress     string `json:"streetAddress"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	ZipCode           string `json:"zipCode"`
+	CountryCode       string `json:"countryCode"`
+
+	// Custom holds the custom attributes of the profile keyed by their
+	// schema name, numbers are decoded as json.Number
+	Custom map[string]interface{} `json:"-"`
+}
+
+// standardProfileAttributes are the json names of the UserProfile fields
+var standardProfileAttributes = func() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(UserProfile{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+func (p *UserProfile) UnmarshalJSON(data []byte) error {
+	type standard UserProfile
+	if err := json.Unmarshal(data, (*standard)(p)); err != nil {
+		return err
+	}
+
+	var attributes map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil {
+		return err
+	}
+
+	p.Custom = nil
+	for name, value := range attributes {
+		if standardProfileAttributes[name] {
+			continue
+		}
+		if p.Custom == nil {
+			p.Custom = map[string]interface{}{}
+		}
+		p.Custom[name] = value
+	}
+	return nil
+}
+
+func (p UserProfile) MarshalJSON() ([]byte, error) {
+	type standard UserProfile
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Custom) == 0 {
+		return data, err
+	}
+
+	var attributes map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil {
+		return nil, err
+	}
+	for name, value := range p.Custom {
+		if !standardProfileAttributes[name] {
+			attributes[name] = value
+		}
+	}
+	return json.Marshal(attributes)
+}
+
+// DecodeCustom decodes the custom attributes into v, typically a pointer to
+// a struct describing the org's profile extensions
+func (p *UserProfile) DecodeCustom(v interface{}) error {
+	data, err := json.Marshal(p.Custom)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ListUsers returns the users of the org matching opts, all of them if
+// opts is nil
+func (c *Client) ListUsers(opts *ListUsersOptions) (*[]User, error) {
+	var response = &[]User{}
+	err := c.listAll(withQuery("users", opts.values()), response)
+	return response, err
+}
+
+// lookupUser fetches a user by id, login or login shortname. Logins with a
+// slash can't be used in the path and are searched instead, and when no
+// user has the login an email address is looked up as the primary email.
+func (c *Client) lookupUser(ctx context.Context, userID string, response *User) error {
+	if strings.Contains(userID, "/") {
+		return c.searchUser(ctx, "profile.login", userID, response)
+	}
+
+	err, _ := c.callContext(ctx, "users/"+url.PathEscape(userID), "GET", nil, response)
+	if ClassOf(err).Kind == ErrorNotFound && strings.Contains(userID, "@") {
+		return c.searchUser(ctx, "profile.email", userID, response)
+	}
+	return err
+}
+
+// searchUser fetches the single user whose attribute equals value
+func (c *Client) searchUser(ctx context.Context, attribute, value string, response *User) error {
+	v := url.Values{}
+	v.Set("search", filterExpr(attribute, "eq", value))
+	endpoint := withQuery("users", v)
+
+	var users = &[]User{}
+	if err, _ := c.callContext(ctx, endpoint, "GET", nil, users); err != nil {
+		return err
+	}
+	switch len(*users) {
+	case 0:
+		return &errorResponse{
+			HTTPCode: http.StatusNotFound,
+			Response: ErrorResponse{ErrorCode: "E0000007", ErrorSummary: "Not found: " + value},
+			Endpoint: endpoint,
+			Class:    c.errorMap().Classify(http.StatusNotFound, "E0000007"),
+		}
+	case 1:
+		*response = (*users)[0]
+		return nil
+	}
+	return fmt.Errorf("okta: %d users have %s %s", len(*users), attribute, value)
+}
@@ -0,0 +1,127 @@
+package okta
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestUserProfileCustomAttributes(t *testing.T) {
+	data := []byte(`{"profile":{"login":"jane@example.com","email":"jane@example.com","costCenter":"42","badgeNumber":1234567890123,"regions":["us","eu"]}}`)
+
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Profile.Login != "jane@example.com" || user.Profile.CostCenter != "42" {
+		t.Errorf("Expected standard attributes, got %+v", user.Profile)
+	}
+	if len(user.Profile.Custom) != 2 || user.Profile.Custom["badgeNumber"] != json.Number("1234567890123") {
+		t.Errorf("Expected custom attributes, got %+v", user.Profile.Custom)
+	}
+
+	var custom struct {
+		BadgeNumber int64    `json:"badgeNumber"`
+		Regions     []string `json:"regions"`
+	}
+	if err := user.Profile.DecodeCustom(&custom); err != nil {
+		t.Fatal(err)
+	}
+	if custom.BadgeNumber != 1234567890123 || len(custom.Regions) != 2 {
+		t.Errorf("Expected typed custom attributes, got %+v", custom)
+	}
+
+	encoded, err := json.Marshal(user.Profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip map[string]interface{}
+	json.Unmarshal(encoded, &roundTrip)
+	if roundTrip["badgeNumber"] != float64(1234567890123) || roundTrip["login"] != "jane@example.com" {
+		t.Errorf("Expected custom attributes to round trip, got %s", encoded)
+	}
+}
+
+func TestUserLookup(t *testing.T) {
+	var paths []string
+	client, done := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath()+" "+r.URL.Query().Get("search"))
+		switch {
+		case r.URL.EscapedPath() == "/api/v1/users/login%20with%20spaces@corp.com":
+			w.Write([]byte(`{"id":"00u1"}`))
+		case r.URL.Path == "/api/v1/users" && r.URL.Query().Get("search") != "":
+			w.Write([]byte(`[{"id":"00u2"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errorCode":"E0000007"}`))
+		}
+	}))
+	defer done()
+
+	user, err := client.User("login with spaces@corp.com")
+	if err != nil || user.ID != "00u1" {
+		t.Fatal("Expected the login to be escaped in the path, got ", user.ID, err)
+	}
+
+	user, err = client.User("jane.doe@corp.com")
+	if err != nil || user.ID != "00u2" {
+		t.Fatal("Expected a fallback to the primary email, got ", user.ID, err)
+	}
+
+	user, err = client.User("corp/jane")
+	if err != nil || user.ID != "00u2" {
+		t.Fatal("Expected logins with a slash to be searched, got ", user.ID, err)
+	}
+
+	expected := []string{
+		"/api/v1/users/login%20with%20spaces@corp.com ",
+		"/api/v1/users/jane.doe@corp.com ",
+		`/api/v1/users profile.email eq "jane.doe@corp.com"`,
+		`/api/v1/users profile.login eq "corp/jane"`,
+	}
+	if len(paths) != len(expected) {
+		t.Fatal("Expected requests ", expected, ", got ", paths)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Error("Expected ", expected[i], ", got ", paths[i])
+		}
+	}
+
+	if _, err := client.User("00u404"); ClassOf(err).Kind != ErrorNotFound {
+		t.Error("Expected ids to not be searched, got ", err)
+	}
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	var bodies []map[string]map[string]interface{}
+	recorder := recordRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"id":"00u1","profile":{"login":"jane@example.com","nickName":"JD","badgeNumber":42}}`))
+	}))
+	client, done := newTestClient(recorder)
+	defer done()
+
+	user, err := client.UpdateUserProfile("00u1", map[string]interface{}{"login": "jane@example.com", "nickName": "JD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Profile.NickName != "JD" || user.Profile.Custom["badgeNumber"] != json.Number("42") {
+		t.Errorf("Expected the updated profile, got %+v", user.Profile)
+	}
+	if _, err := client.UpdateUserProfilePartial("00u1", json.RawMessage(`{"badgeNumber":42}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 2 || len(bodies[0]["profile"]) != 2 || bodies[0]["profile"]["nickName"] != "JD" ||
+		len(bodies[1]["profile"]) != 1 || bodies[1]["profile"]["badgeNumber"] != float64(42) {
+		t.Error("Expected the profiles to be sent as given, got ", bodies)
+	}
+	// a PUT replaces the whole profile, a POST only the attributes sent
+	recorder.expect(t,
+		"PUT /api/v1/users/00u1",
+		"POST /api/v1/users/00u1",
+	)
+}
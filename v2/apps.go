@@ -0,0 +1,40 @@
+package okta
+
+import (
+	"context"
+	"net/url"
+)
+
+// AppLinks are the links of the apps assigned to a user, shown on the end
+// user dashboard
+type AppLinks []AppLink
+
+type AppLink struct {
+	AppAssignmentID  string `json:"appAssignmentId"`
+	AppInstanceID    string `json:"appInstanceId"`
+	AppName          string `json:"appName"`
+	CredentialsSetup bool   `json:"credentialsSetup"`
+	Hidden           bool   `json:"hidden"`
+	ID               string `json:"id"`
+	Label            string `json:"label"`
+	LinkURL          string `json:"linkUrl"`
+	LogoURL          string `json:"logoUrl"`
+	SortOrder        int64  `json:"sortOrder"`
+}
+
+// AppsService deals with the apps of users
+type AppsService service
+
+// ListAppLinks returns a page of the app links of a user, only those of
+// apps named appName unless it is empty
+func (s *AppsService) ListAppLinks(ctx context.Context, userID, appName string, opts *ListOptions) ([]AppLink, *Response, error) {
+	v := url.Values{}
+	if appName != "" {
+		v.Set("filter", filterExpr("appName", "eq", appName))
+	}
+	opts.encode(v)
+
+	var response []AppLink
+	resp, err := s.client.do(ctx, "GET", withQuery("users/"+url.PathEscape(userID)+"/appLinks", v), nil, &response)
+	return response, resp, err
+}
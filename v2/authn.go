@@ -0,0 +1,182 @@
+package okta
+
+import (
+	"context"
+	"time"
+)
+
+type ErrorResponse struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorSummary string `json:"errorSummary"`
+	ErrorLink    string `json:"errorLink"`
+	ErrorID      string `json:"errorId"`
+	ErrorCauses  []struct {
+		ErrorSummary string `json:"errorSummary"`
+	} `json:"errorCauses"`
+}
+
+type AuthnRequest struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	RelayState string `json:"relayState"`
+	Options    struct {
+		MultiOptionalFactorEnroll bool `json:"multiOptionalFactorEnroll"`
+		WarnBeforePasswordExpired bool `json:"warnBeforePasswordExpired"`
+	} `json:"options"`
+}
+
+// AuthnStatus is the state of an authentication transaction
+type AuthnStatus string
+
+// https://developer.okta.com/docs/reference/api/authn/#transaction-state
+const (
+	AuthnUnauthenticated   AuthnStatus = "UNAUTHENTICATED"
+	AuthnPasswordWarn      AuthnStatus = "PASSWORD_WARN"
+	AuthnPasswordExpired   AuthnStatus = "PASSWORD_EXPIRED"
+	AuthnRecovery          AuthnStatus = "RECOVERY"
+	AuthnRecoveryChallenge AuthnStatus = "RECOVERY_CHALLENGE"
+	AuthnPasswordReset     AuthnStatus = "PASSWORD_RESET"
+	AuthnLockedOut         AuthnStatus = "LOCKED_OUT"
+	AuthnMFAEnroll         AuthnStatus = "MFA_ENROLL"
+	AuthnMFAEnrollActivate AuthnStatus = "MFA_ENROLL_ACTIVATE"
+	AuthnMFARequired       AuthnStatus = "MFA_REQUIRED"
+	AuthnMFAChallenge      AuthnStatus = "MFA_CHALLENGE"
+	AuthnSuccess           AuthnStatus = "SUCCESS"
+)
+
+type AuthnResponse struct {
+	StateToken   string      `json:"stateToken"`
+	ExpiresAt    *time.Time  `json:"expiresAt"`
+	Status       AuthnStatus `json:"status"`
+	RelayState   string      `json:"relayState"`
+	FactorResult string      `json:"factorResult"`
+	SessionToken string      `json:"sessionToken"`
+	Embedded     struct {
+		User struct {
+			ID              string     `json:"id"`
+			PasswordChanged *time.Time `json:"passwordChanged"`
+			Profile         struct {
+				Login     string `json:"login"`
+				FirstName string `json:"firstName"`
+				LastName  string `json:"lastName"`
+				Locale    string `json:"locale"`
+				TimeZone  string `json:"timeZone"`
+			} `json:"profile"`
+		} `json:"user"`
+		Factors []Factor `json:"factors"`
+		// Factor is the factor being activated in MFA_ENROLL_ACTIVATE
+		Factor *Factor `json:"factor"`
+		// Challenge is set in MFA_CHALLENGE for WebAuthn factors
+		Challenge *WebAuthnChallenge `json:"challenge"`
+		Policy    struct {
+			AllowRememberDevice             bool `json:"allowRememberDevice"`
+			RememberDeviceLifetimeInMinutes int  `json:"rememberDeviceLifetimeInMinutes"`
+			RememberDeviceByDefault         bool `json:"rememberDeviceByDefault"`
+		} `json:"policy"`
+	} `json:"_embedded"`
+	Links struct {
+		Cancel struct {
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"cancel"`
+		Next struct {
+			Name  string `json:"name"`
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"next"`
+		Prev struct {
+			Href string `json:"href"`
+		} `json:"prev"`
+		Skip struct {
+			Href string `json:"href"`
+		} `json:"skip"`
+		Resend []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		} `json:"resend"`
+	} `json:"_links"`
+}
+
+type Factor struct {
+	ID         string `json:"id"`
+	FactorType string `json:"factorType"`
+	Provider   string `json:"provider"`
+	VendorName string `json:"vendorName"`
+	Profile    struct {
+		CredentialID string `json:"credentialId"`
+		// AuthenticatorName is set for WebAuthn factors
+		AuthenticatorName string `json:"authenticatorName"`
+	} `json:"profile"`
+	Embedded struct {
+		// Activation is set for WebAuthn factors in MFA_ENROLL_ACTIVATE
+		Activation *WebAuthnActivation `json:"activation"`
+	} `json:"_embedded"`
+	Links struct {
+		Verify struct {
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"verify"`
+		Enroll struct {
+			Href string `json:"href"`
+		} `json:"enroll"`
+	} `json:"_links"`
+}
+
+// WebAuthnChallenge is the challenge to sign with navigator.credentials.get
+// or a FIDO2 authenticator, the credential ids allowed are the
+// Profile.CredentialID of the factors of the transaction
+type WebAuthnChallenge struct {
+	Challenge  string                 `json:"challenge"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// WebAuthnActivation holds the options for navigator.credentials.create
+// when enrolling a WebAuthn factor
+type WebAuthnActivation struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams []struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	} `json:"pubKeyCredParams"`
+	Attestation            string `json:"attestation"`
+	AuthenticatorSelection struct {
+		UserVerification   string `json:"userVerification"`
+		RequireResidentKey bool   `json:"requireResidentKey"`
+	} `json:"authenticatorSelection"`
+	ExcludeCredentials []struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"excludeCredentials"`
+	U2FParams struct {
+		AppID string `json:"appid"`
+	} `json:"u2fParams"`
+}
+
+// AuthnService signs users in
+type AuthnService service
+
+// Authenticate with okta using username and password
+func (s *AuthnService) Authenticate(ctx context.Context, username, password string) (*AuthnResponse, *Response, error) {
+	var request = &AuthnRequest{
+		Username: username,
+		Password: password,
+	}
+
+	var response = &AuthnResponse{}
+	resp, err := s.client.do(ctx, "POST", "authn", request, response)
+	return response, resp, err
+}
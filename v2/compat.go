@@ -0,0 +1,99 @@
+package okta
+
+import (
+	"context"
+)
+
+// Compat offers the version 1 signatures on top of a Client so that code
+// can switch the import path first and migrate call by call. Lists are
+// fetched completely like they were in version 1.
+//
+//	client := okta.NewClient("example")
+//	user, err := okta.Compat{client}.User(userID)
+type Compat struct {
+	Client *Client
+}
+
+// Deprecated: use Client.Authn.Authenticate
+func (c Compat) Authenticate(username, password string) (*AuthnResponse, error) {
+	response, _, err := c.Client.Authn.Authenticate(context.Background(), username, password)
+	return response, err
+}
+
+// Deprecated: use Client.Sessions.Create
+func (c Compat) Session(sessionToken string) (*SessionResponse, error) {
+	response, _, err := c.Client.Sessions.Create(context.Background(), sessionToken)
+	return response, err
+}
+
+// Deprecated: use Client.Users.Get
+func (c Compat) User(userID string) (*User, error) {
+	response, _, err := c.Client.Users.Get(context.Background(), userID)
+	return response, err
+}
+
+// Deprecated: use Client.Users.ListGroups
+func (c Compat) Groups(userID string) (*[]Group, error) {
+	var groups = []Group{}
+	opts := &ListOptions{Limit: 200}
+	for {
+		page, resp, err := c.Client.Users.ListGroups(context.Background(), userID, opts)
+		groups = append(groups, page...)
+		if err != nil || resp.NextPage == "" {
+			return &groups, err
+		}
+		opts.After = resp.NextPage
+	}
+}
+
+// Deprecated: use Client.Apps.ListAppLinks
+func (c Compat) AppLinks(userID string, appName string) (*AppLinks, error) {
+	var links = AppLinks{}
+	opts := &ListOptions{}
+	for {
+		page, resp, err := c.Client.Apps.ListAppLinks(context.Background(), userID, appName, opts)
+		links = append(links, page...)
+		if err != nil || resp.NextPage == "" {
+			return &links, err
+		}
+		opts.After = resp.NextPage
+	}
+}
+
+// Deprecated: use Client.Users.Create
+func (c Compat) CreateUser(user *CreateUserRequest, activate bool) (*User, error) {
+	response, _, err := c.Client.Users.Create(context.Background(), user, activate)
+	return response, err
+}
+
+// Deprecated: use Client.Groups.Get
+func (c Compat) Group(groupID string) (*Group, error) {
+	response, _, err := c.Client.Groups.Get(context.Background(), groupID)
+	return response, err
+}
+
+// Deprecated: use Client.Groups.ListMembers
+func (c Compat) GroupMembers(groupID string) (*[]User, error) {
+	var users = []User{}
+	opts := &ListOptions{Limit: 200}
+	for {
+		page, resp, err := c.Client.Groups.ListMembers(context.Background(), groupID, opts)
+		users = append(users, page...)
+		if err != nil || resp.NextPage == "" {
+			return &users, err
+		}
+		opts.After = resp.NextPage
+	}
+}
+
+// Deprecated: use Client.Groups.AddMember
+func (c Compat) AddUserToGroup(groupID, userID string) error {
+	_, err := c.Client.Groups.AddMember(context.Background(), groupID, userID)
+	return err
+}
+
+// Deprecated: use Client.Groups.RemoveMember
+func (c Compat) RemoveUserFromGroup(groupID, userID string) error {
+	_, err := c.Client.Groups.RemoveMember(context.Background(), groupID, userID)
+	return err
+}
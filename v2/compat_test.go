@@ -0,0 +1,111 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompatGroups(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") == "" {
+			w.Header().Add("Link", `<`+server.URL+`/api/v1/users/00u1/groups?after=00g1&limit=200>; rel="next"`)
+			w.Write([]byte(`[{"id":"00g1"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"00g2"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("example")
+	client.BaseURL = server.URL
+
+	page, resp, err := client.Users.ListGroups(context.Background(), "00u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || resp.NextPage != "00g1" {
+		t.Error("Expected the first page and its cursor, got ", page, resp.NextPage)
+	}
+
+	groups, err := Compat{client}.Groups("00u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*groups) != 2 || (*groups)[1].ID != "00g2" {
+		t.Error("Expected both pages, got ", *groups)
+	}
+}
+
+func TestCompatGroupMembers(t *testing.T) {
+	var requests []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method != "GET":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Query().Get("after") == "":
+			w.Header().Add("Link", `<`+server.URL+`/api/v1/groups/00g1/users?after=00u1&limit=200>; rel="next"`)
+			w.Write([]byte(`[{"id":"00u1"}]`))
+		default:
+			w.Write([]byte(`[{"id":"00u2"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("example")
+	client.BaseURL = server.URL
+	compat := Compat{client}
+
+	if err := compat.AddUserToGroup("00g1", "00u3"); err != nil {
+		t.Fatal(err)
+	}
+	users, err := compat.GroupMembers("00g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*users) != 2 || (*users)[1].ID != "00u2" {
+		t.Error("Expected both pages, got ", *users)
+	}
+	if err := compat.RemoveUserFromGroup("00g1", "00u3"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /api/v1/groups/00g1/users/00u3",
+		"GET /api/v1/groups/00g1/users",
+		"GET /api/v1/groups/00g1/users",
+		"DELETE /api/v1/groups/00g1/users/00u3",
+	}
+	if len(requests) != len(expected) {
+		t.Fatal("Unexpected requests ", requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Error("Expected ", expected[i], ", got ", requests[i])
+		}
+	}
+}
+
+func TestCompatAppLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"0oa1","appName":"bookmark","label":"Wiki","linkUrl":"https://example.okta.com/home/bookmark/0oa1/2"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("example")
+	client.BaseURL = server.URL
+
+	// the version 1 type, so callers ranging over *links keep compiling
+	var links *AppLinks
+	links, err := Compat{client}.AppLinks("00u1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*links) != 1 || (*links)[0].Label != "Wiki" {
+		t.Error("Expected the app link, got ", *links)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/Cox-Automotive/go-okta/v2
+
+go 1.13
@@ -0,0 +1,55 @@
+package okta
+
+import (
+	"context"
+	"net/url"
+)
+
+type Group struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Profile struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"profile"`
+}
+
+// GroupsService manages groups and their members
+type GroupsService service
+
+// Get takes a group id and returns the group
+func (s *GroupsService) Get(ctx context.Context, groupID string) (*Group, *Response, error) {
+	var response = &Group{}
+	resp, err := s.client.do(ctx, "GET", "groups/"+url.PathEscape(groupID), nil, response)
+	return response, resp, err
+}
+
+// List returns a page of the groups of the org
+func (s *GroupsService) List(ctx context.Context, opts *ListOptions) ([]Group, *Response, error) {
+	v := url.Values{}
+	opts.encode(v)
+
+	var response []Group
+	resp, err := s.client.do(ctx, "GET", withQuery("groups", v), nil, &response)
+	return response, resp, err
+}
+
+// ListMembers returns a page of the users in a group
+func (s *GroupsService) ListMembers(ctx context.Context, groupID string, opts *ListOptions) ([]User, *Response, error) {
+	v := url.Values{}
+	opts.encode(v)
+
+	var response []User
+	resp, err := s.client.do(ctx, "GET", withQuery("groups/"+url.PathEscape(groupID)+"/users", v), nil, &response)
+	return response, resp, err
+}
+
+// AddMember makes a user a member of a group
+func (s *GroupsService) AddMember(ctx context.Context, groupID, userID string) (*Response, error) {
+	return s.client.do(ctx, "PUT", "groups/"+url.PathEscape(groupID)+"/users/"+url.PathEscape(userID), nil, nil)
+}
+
+// RemoveMember removes a user from a group
+func (s *GroupsService) RemoveMember(ctx context.Context, groupID, userID string) (*Response, error) {
+	return s.client.do(ctx, "DELETE", "groups/"+url.PathEscape(groupID)+"/users/"+url.PathEscape(userID), nil, nil)
+}
@@ -0,0 +1,56 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGroupsService(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/api/v1/groups/00g1/users":
+			w.Write([]byte(`[{"id":"00u1"}]`))
+		case "/api/v1/groups/00g1/users/00u2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`[{"id":"00g1"}]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("example")
+	client.BaseURL = server.URL
+	ctx := context.Background()
+
+	groups, _, err := client.Groups.List(ctx, &ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].ID != "00g1" {
+		t.Error("Expected one group, got ", groups)
+	}
+
+	members, _, err := client.Groups.ListMembers(ctx, "00g1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || members[0].ID != "00u1" {
+		t.Error("Expected one member, got ", members)
+	}
+
+	if _, err := client.Groups.AddMember(ctx, "00g1", "00u2"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"GET /api/v1/groups?limit=1", "GET /api/v1/groups/00g1/users", "PUT /api/v1/groups/00g1/users/00u2"}
+	for i := range expected {
+		if i >= len(requests) || requests[i] != expected[i] {
+			t.Error("Expected requests ", expected, ", got ", requests)
+			break
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package okta is version 2 of the Okta client. The API is split into
+// services per resource, e.g. client.Users.Get, every call takes a context
+// and returns the decoded result, a *Response with the HTTP metadata and an
+// error last. Compat offers the version 1 signatures on top of a Client for
+// code that hasn't been migrated yet.
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of this module, it is sent in the User-Agent
+// header as go-okta/Version
+const Version = "2.0.0"
+
+// Client to access okta
+type Client struct {
+	// HTTPClient sends the requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	// BaseURL is the scheme and host of the org, e.g.
+	// https://example.okta.com or a custom domain
+	BaseURL string
+
+	ApiToken      string
+	SessionCookie *http.Cookie
+
+	// UserAgent is appended to the go-okta/Version User-Agent header, e.g.
+	// exporter/1.2
+	UserAgent string
+
+	Apps     *AppsService
+	Authn    *AuthnService
+	Groups   *GroupsService
+	Sessions *SessionsService
+	Users    *UsersService
+}
+
+// service is embedded by the services of the API
+type service struct {
+	client *Client
+}
+
+// NewClient returns a client for https://{org}.okta.com, set BaseURL for a
+// custom domain
+func NewClient(org string) *Client {
+	c := &Client{BaseURL: "https://" + org + ".okta.com"}
+	common := service{client: c}
+	c.Apps = (*AppsService)(&common)
+	c.Authn = (*AuthnService)(&common)
+	c.Groups = (*GroupsService)(&common)
+	c.Sessions = (*SessionsService)(&common)
+	c.Users = (*UsersService)(&common)
+	return c
+}
+
+// Response wraps the HTTP response of a call
+type Response struct {
+	*http.Response
+
+	// RequestID is the X-Okta-Request-Id, useful when contacting support
+	RequestID string
+
+	// NextPage is the cursor of the next page to pass as ListOptions.After,
+	// empty on the last page
+	NextPage string
+}
+
+// ListOptions selects a page of a collection
+type ListOptions struct {
+	// After is the cursor returned as Response.NextPage
+	After string
+	// Limit is the page size, Okta's default for the endpoint when zero
+	Limit int
+}
+
+func (o *ListOptions) encode(v url.Values) {
+	if o == nil {
+		return
+	}
+	if o.After != "" {
+		v.Set("after", o.After)
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+}
+
+// filterExpr builds a filter comparison of attribute with a quoted value,
+// escaping the value so it can't end the string early
+func filterExpr(attribute, operator, value string) string {
+	value = strings.Replace(strings.Replace(value, `\`, `\\`, -1), `"`, `\"`, -1)
+	return attribute + " " + operator + ` "` + value + `"`
+}
+
+// Error is returned for responses outside of 2xx
+type Error struct {
+	ErrorResponse
+	Response *http.Response
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Error hitting api endpoint %s %s", e.Response.Request.URL, e.ErrorCode)
+}
+
+// do sends a request to an /api/v1 endpoint and decodes the response into
+// v unless it is nil or the response is empty
+func (c *Client) do(ctx context.Context, method, endpoint string, body, v interface{}) (*Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method,
+		strings.TrimSuffix(c.BaseURL, "/")+"/api/v1/"+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Set("User-Agent", strings.TrimSpace("go-okta/"+Version+" "+c.UserAgent))
+	if c.ApiToken != "" {
+		req.Header.Add("Authorization", "SSWS "+c.ApiToken)
+	}
+	if c.SessionCookie != nil {
+		req.Header.Add("Cookie", c.SessionCookie.String())
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := &Response{
+		Response:  resp,
+		RequestID: resp.Header.Get("X-Okta-Request-Id"),
+		NextPage:  nextPage(resp.Header),
+	}
+
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := &Error{Response: resp}
+		_ = json.Unmarshal(data, &e.ErrorResponse)
+		return response, e
+	}
+
+	if len(data) > 0 && v != nil {
+		err = json.Unmarshal(data, v)
+	}
+	return response, err
+}
+
+// nextPage returns the after cursor of the rel="next" Link header
+func nextPage(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			for _, param := range parts[1:] {
+				param = strings.Replace(strings.TrimSpace(param), " ", "", -1)
+				if param != `rel="next"` && param != "rel=next" {
+					continue
+				}
+				u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+				if err != nil {
+					return ""
+				}
+				return u.Query().Get("after")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type SessionRequest struct {
+	SessionToken string `json:"sessionToken"`
+}
+
+type SessionResponse struct {
+	ID                       string     `json:"id"`
+	Login                    string     `json:"login"`
+	UserID                   string     `json:"userId"`
+	ExpiresAt                *time.Time `json:"expiresAt"`
+	Status                   string     `json:"status"`
+	LastPasswordVerification *time.Time `json:"lastPasswordVerification"`
+	LastFactorVerification   *time.Time `json:"lastFactorVerification"`
+	Amr                      []string   `json:"amr"`
+	Idp                      struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"idp"`
+	MfaActive bool `json:"mfaActive"`
+	Links     struct {
+		Self struct {
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"self"`
+		Refresh struct {
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"refresh"`
+		User struct {
+			Name  string `json:"name"`
+			Href  string `json:"href"`
+			Hints struct {
+				Allow []string `json:"allow"`
+			} `json:"hints"`
+		} `json:"user"`
+	} `json:"_links"`
+}
+
+// SessionsService manages Okta sessions
+type SessionsService service
+
+// Create takes a session token and returns a session, the ID is stored as a
+// cookie of the client so it can be consumed by this library and its clients.
+func (s *SessionsService) Create(ctx context.Context, sessionToken string) (*SessionResponse, *Response, error) {
+	var request = &SessionRequest{
+		SessionToken: sessionToken,
+	}
+
+	var response = &SessionResponse{}
+	resp, err := s.client.do(ctx, "POST", "sessions", request, response)
+	if err == nil {
+		domain := ""
+		if resp.Request != nil {
+			domain = resp.Request.URL.Hostname()
+		}
+		s.client.SessionCookie = &http.Cookie{
+			Name:     "sid",
+			Value:    response.ID,
+			Path:     "/",
+			Domain:   domain,
+			Secure:   true,
+			HttpOnly: true,
+		}
+	}
+	return response, resp, err
+}
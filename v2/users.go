@@ -0,0 +1,207 @@
+package okta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type User struct {
+	ID              string      `json:"id"`
+	Status          string      `json:"status"`
+	Created         *time.Time  `json:"created"`
+	Activated       *time.Time  `json:"activated"`
+	StatusChanged   *time.Time  `json:"statusChanged"`
+	LastLogin       *time.Time  `json:"lastLogin"`
+	LastUpdated     *time.Time  `json:"lastUpdated"`
+	PasswordChanged *time.Time  `json:"passwordChanged"`
+	Profile         UserProfile `json:"profile"`
+	Credentials     struct {
+		Password struct {
+		} `json:"password"`
+		RecoveryQuestion struct {
+			Question string `json:"question"`
+		} `json:"recovery_question"`
+		Provider struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		} `json:"provider"`
+	} `json:"credentials"`
+	Links struct {
+		ResetPassword struct {
+			Href string `json:"href"`
+		} `json:"resetPassword"`
+		ResetFactors struct {
+			Href string `json:"href"`
+		} `json:"resetFactors"`
+		ExpirePassword struct {
+			Href string `json:"href"`
+		} `json:"expirePassword"`
+		ForgotPassword struct {
+			Href string `json:"href"`
+		} `json:"forgotPassword"`
+		ChangeRecoveryQuestion struct {
+			Href string `json:"href"`
+		} `json:"changeRecoveryQuestion"`
+		Deactivate struct {
+			Href string `json:"href"`
+		} `json:"deactivate"`
+		ChangePassword struct {
+			Href string `json:"href"`
+		} `json:"changePassword"`
+	} `json:"_links"`
+}
+
+// CreateUserRequest is the body of UsersService.Create, Profile may be any value
+// encoding to a JSON object such as a map[string]interface{}
+type CreateUserRequest struct {
+	Profile  interface{} `json:"profile"`
+	GroupIDs []string    `json:"groupIds,omitempty"`
+}
+
+// UserProfile is the profile of a user. Attributes that aren't part of the
+// default Okta profile are kept in Custom so they survive a round trip
+// through UpdateUserProfile.
+type UserProfile struct {
+	Login             string `json:"login"`
+	FirstName         string `json:"firstName"`
+	LastName          string `json:"lastName"`
+	NickName          string `json:"nickName"`
+	DisplayName       string `json:"displayName"`
+	Email             string `json:"email"`
+	SecondEmail       string `json:"secondEmail"`
+	ProfileURL        string `json:"profileUrl"`
+	PreferredLanguage string `json:"preferredLanguage"`
+	UserType          string `json:"userType"`
+	Organization      string `json:"organization"`
+	Title             string `json:"title"`
+	Division          string `json:"division"`
+	Department        string `json:"department"`
+	CostCenter        string `json:"costCenter"`
+	EmployeeNumber    string `json:"employeeNumber"`
+	MobilePhone       string `json:"mobilePhone"`
+	PrimaryPhone      string `json:"primaryPhone"`
+	StreetAddress     string `json:"streetAddress"`
+	City              string `json:"city"`
+	State             string `json:"state"`
+	ZipCode           string `json:"zipCode"`
+	CountryCode       string `json:"countryCode"`
+
+	// Custom holds the custom attributes of the profile keyed by their
+	// schema name, numbers are decoded as json.Number
+	Custom map[string]interface{} `json:"-"`
+}
+
+// standardProfileAttributes are the json names of the UserProfile fields
+var standardProfileAttributes = func() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(UserProfile{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+func (p *UserProfile) UnmarshalJSON(data []byte) error {
+	type standard UserProfile
+	if err := json.Unmarshal(data, (*standard)(p)); err != nil {
+		return err
+	}
+
+	var attributes map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil {
+		return err
+	}
+
+	p.Custom = nil
+	for name, value := range attributes {
+		if standardProfileAttributes[name] {
+			continue
+		}
+		if p.Custom == nil {
+			p.Custom = map[string]interface{}{}
+		}
+		p.Custom[name] = value
+	}
+	return nil
+}
+
+func (p UserProfile) MarshalJSON() ([]byte, error) {
+	type standard UserProfile
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Custom) == 0 {
+		return data, err
+	}
+
+	var attributes map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil {
+		return nil, err
+	}
+	for name, value := range p.Custom {
+		if !standardProfileAttributes[name] {
+			attributes[name] = value
+		}
+	}
+	return json.Marshal(attributes)
+}
+
+// DecodeCustom decodes the custom attributes into v, typically a pointer to
+// a struct describing the org's profile extensions
+func (p *UserProfile) DecodeCustom(v interface{}) error {
+	data, err := json.Marshal(p.Custom)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// UsersService manages users
+type UsersService service
+
+// Get takes a user id and returns data about that user, "me" is the user
+// signed in
+func (s *UsersService) Get(ctx context.Context, userID string) (*User, *Response, error) {
+	var response = &User{}
+	resp, err := s.client.do(ctx, "GET", "users/"+url.PathEscape(userID), nil, response)
+	return response, resp, err
+}
+
+// Create creates a user, the user is activated right away if activate is
+// true, otherwise it is STAGED
+func (s *UsersService) Create(ctx context.Context, user *CreateUserRequest, activate bool) (*User, *Response, error) {
+	v := url.Values{}
+	v.Set("activate", strconv.FormatBool(activate))
+
+	var response = &User{}
+	resp, err := s.client.do(ctx, "POST", withQuery("users", v), user, response)
+	return response, resp, err
+}
+
+// ListGroups returns a page of the groups a user belongs to
+func (s *UsersService) ListGroups(ctx context.Context, userID string, opts *ListOptions) ([]Group, *Response, error) {
+	v := url.Values{}
+	opts.encode(v)
+
+	var response []Group
+	resp, err := s.client.do(ctx, "GET", withQuery("users/"+url.PathEscape(userID)+"/groups", v), nil, &response)
+	return response, resp, err
+}
+
+func withQuery(endpoint string, v url.Values) string {
+	if len(v) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + v.Encode()
+}
@@ -0,0 +1,157 @@
+package okta
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const defaultWatchInterval = 10 * time.Second
+
+// Change is a notification that a resource in the org was modified
+type Change struct {
+	ResourceType string
+	ResourceID   string
+	EventType    string
+	Event        LogEvent
+}
+
+// Watcher delivers changes on C until its context is cancelled or the
+// underlying mechanism fails, Err returns the reason once C is closed
+type Watcher struct {
+	C   <-chan Change
+	err error
+}
+
+// Err returns why C was closed, it must only be called after C is drained
+func (w *Watcher) Err() error {
+	return w.err
+}
+
+// Watch notifies about changes to resources of resourceType (e.g. "User",
+// "UserGroup", empty for all) caused by events whose type starts with
+// eventType (e.g. "group.user_membership", empty for all).
+//
+// Changes come from the deliveries of Client.HookReceiver when it is set,
+// otherwise the System Log is polled every Client.WatchInterval.
+func (c *Client) Watch(ctx context.Context, resourceType, eventType string) *Watcher {
+	changes := make(chan Change)
+	w := &Watcher{C: changes}
+
+	go func() {
+		var err error
+		if c.HookReceiver != nil {
+			err = watchHooks(ctx, c.HookReceiver, resourceType, eventType, changes)
+		} else {
+			err = c.watchLogs(ctx, resourceType, eventType, changes)
+		}
+		w.err = err
+		close(changes)
+	}()
+
+	return w
+}
+
+func watchHooks(ctx context.Context, h *EventHookHandler, resourceType, eventType string, changes chan<- Change) error {
+	events, unsubscribe := h.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-events:
+			if !strings.HasPrefix(event.EventType, eventType) {
+				continue
+			}
+			if !emitChanges(ctx, event, resourceType, changes) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (c *Client) watchLogs(ctx context.Context, resourceType, eventType string, changes chan<- Change) error {
+	interval := c.WatchInterval
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+
+	filter := ""
+	if eventType != "" {
+		filter = filterExpr("eventType", "sw", eventType)
+	}
+
+	// since is inclusive, remember what was already seen at the newest
+	// timestamp so those events aren't delivered twice
+	since := time.Now()
+	seen := map[string]bool{}
+
+	for {
+		events, err := c.Logs(since, filter)
+		if err != nil {
+			return err
+		}
+
+		fresh := 0
+		for _, event := range *events {
+			if seen[event.UUID] {
+				continue
+			}
+			if event.Published != nil && event.Published.After(since) {
+				since = *event.Published
+				seen = map[string]bool{}
+			}
+			seen[event.UUID] = true
+			fresh++
+
+			if !emitChanges(ctx, event, resourceType, changes) {
+				return ctx.Err()
+			}
+		}
+
+		// keep reading while there is a backlog
+		if fresh > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// emitChanges sends a change for every target of the event matching
+// resourceType and returns false if ctx was cancelled meanwhile
+func emitChanges(ctx context.Context, event LogEvent, resourceType string, changes chan<- Change) bool {
+	send := func(change Change) bool {
+		select {
+		case changes <- change:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if resourceType == "" && len(event.Target) == 0 {
+		return send(Change{EventType: event.EventType, Event: event})
+	}
+
+	for _, target := range event.Target {
+		if resourceType != "" && !strings.EqualFold(target.Type, resourceType) {
+			continue
+		}
+		ok := send(Change{
+			ResourceType: target.Type,
+			ResourceID:   target.ID,
+			EventType:    event.EventType,
+			Event:        event,
+		})
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
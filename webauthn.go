@@ -0,0 +1,123 @@
+package okta
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WebAuthnChallenge is the challenge to sign with navigator.credentials.get
+// or a FIDO2 authenticator, the credential ids allowed are the
+// Profile.CredentialID of the factors of the transaction
+type WebAuthnChallenge struct {
+	Challenge  string                 `json:"challenge"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// WebAuthnActivation holds the options for navigator.credentials.create
+// when enrolling a WebAuthn factor
+type WebAuthnActivation struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams []struct {
+		Type string `json:"type"`
+		Alg  int    `json:"alg"`
+	} `json:"pubKeyCredParams"`
+	Attestation            string `json:"attestation"`
+	AuthenticatorSelection struct {
+		UserVerification   string `json:"userVerification"`
+		RequireResidentKey bool   `json:"requireResidentKey"`
+	} `json:"authenticatorSelection"`
+	ExcludeCredentials []struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"excludeCredentials"`
+	U2FParams struct {
+		AppID string `json:"appid"`
+	} `json:"u2fParams"`
+}
+
+// WebAuthnAttestation is the result of navigator.credentials.create, both
+// values base64url encoded
+type WebAuthnAttestation struct {
+	Attestation string `json:"attestation"`
+	ClientData  string `json:"clientData"`
+}
+
+// WebAuthnAssertion is the result of navigator.credentials.get, all values
+// base64url encoded
+type WebAuthnAssertion struct {
+	ClientData        string `json:"clientData"`
+	AuthenticatorData string `json:"authenticatorData"`
+	SignatureData     string `json:"signatureData"`
+}
+
+// EnrollWebAuthn starts enrolling a WebAuthn factor in MFA_ENROLL, the
+// returned MFA_ENROLL_ACTIVATE transaction holds the creation options in
+// Embedded.Factor.Embedded.Activation
+// https://developer.okta.com/docs/reference/api/authn/#enroll-webauthn-factor
+func (r *AuthnResponse) EnrollWebAuthn() (*AuthnResponse, error) {
+	if r.Status != AuthnMFAEnroll {
+		return nil, fmt.Errorf("can not enroll a factor in %s", r.Status)
+	}
+
+	for _, factor := range r.Embedded.Factors {
+		if factor.FactorType == FactorWebAuthn && factor.Links.Enroll.Href != "" {
+			return postAuthn(factor.Links.Enroll.Href, map[string]interface{}{
+				"stateToken": r.StateToken,
+				"factorType": "webauthn",
+				"provider":   "FIDO",
+			})
+		}
+	}
+	return nil, errors.New("webauthn is not available for enrollment")
+}
+
+// ActivateWebAuthn completes the enrollment started by EnrollWebAuthn with
+// the new credential
+// https://developer.okta.com/docs/reference/api/authn/#activate-webauthn-factor
+func (r *AuthnResponse) ActivateWebAuthn(attestation WebAuthnAttestation) (*AuthnResponse, error) {
+	if r.Status != AuthnMFAEnrollActivate {
+		return nil, fmt.Errorf("can not activate a factor in %s", r.Status)
+	}
+	return r.Next(map[string]interface{}{
+		"attestation": attestation.Attestation,
+		"clientData":  attestation.ClientData,
+	})
+}
+
+// ChallengeWebAuthn requests a challenge for a WebAuthn factor, the
+// returned MFA_CHALLENGE transaction holds it in Embedded.Challenge
+// https://developer.okta.com/docs/reference/api/authn/#verify-webauthn-factor
+func (f Factor) ChallengeWebAuthn(stateToken string) (*AuthnResponse, error) {
+	if f.FactorType != FactorWebAuthn {
+		return nil, fmt.Errorf(
+			"can not ChallengeWebAuthn on a factor type of %s", f.FactorType)
+	}
+
+	return postAuthn(f.Links.Verify.Href, map[string]interface{}{
+		"stateToken": stateToken,
+	})
+}
+
+// VerifyWebAuthn answers the challenge of ChallengeWebAuthn with the signed
+// assertion of the authenticator
+func (f Factor) VerifyWebAuthn(stateToken string, assertion WebAuthnAssertion) (*AuthnResponse, error) {
+	if f.FactorType != FactorWebAuthn {
+		return nil, fmt.Errorf(
+			"can not VerifyWebAuthn on a factor type of %s", f.FactorType)
+	}
+
+	return postAuthn(f.Links.Verify.Href, map[string]interface{}{
+		"stateToken":        stateToken,
+		"clientData":        assertion.ClientData,
+		"authenticatorData": assertion.AuthenticatorData,
+		"signatureData":     assertion.SignatureData,
+	})
+}